@@ -0,0 +1,1013 @@
+// 视位置计算：光行时、引力偏折、光行差、岁差和章动，移植自 sweph.c。
+
+package ephgo
+
+import "math"
+
+// 天体类型，决定星历文件和光行时的计算方法
+const (
+	isPlanet       = 0
+	isMoon         = 1
+	isAnyBody      = 2
+	isMainAsteroid = 3
+)
+
+// pnoint2jpl 内部行星编号到JPL天体编号的映射
+var pnoint2jpl = [...]int{JEarth, JMoon, JMercury, JVenus, JMars, JJupiter, JSaturn, JUranus, JNeptune, JPluto, JSun}
+
+// pnoext2int 外部天体编号到内部行星编号的映射
+var pnoext2int = [...]int{SeiSun, SeiMoon, SeiMercury, SeiVenus, SeiMars, SeiJupiter, SeiSaturn, SeiUranus, SeiNeptune, SeiPluto, 0, 0, 0, 0, SeiEarth, SeiChiron, SeiPholus, SeiCeres, SeiPallas, SeiJuno, SeiVesta}
+
+// lightTime 返回距离为dx（天文单位）的光行时（天）
+func lightTime(dx []Float64) Float64 {
+	return math.Sqrt(squareSum3(dx)) * Aunit / Clight / 86400.0
+}
+
+// appPosEtcPlan 将行星由质心坐标转换为地心（或日心、质心）视位置
+// 包括光行时、引力偏折、光行差、参考架偏差、岁差和章动
+func appPosEtcPlan(ipli int, iflag Int32) (int, error) {
+	var xx, xx0, xxsp, xxsv, xobs, xobs2, xearth, xsun [6]Float64
+	var dx [3]Float64
+	var ifno, ibody int
+	var pdp *PlanData
+	var dt, dtsaveForDefl Float64
+	retc := Ok
+	pedp := &swed.Pldat[SeiEarth]
+	epheflag := iflag & sefEphMask
+	// 星历文件
+	switch {
+	case ipli > SePlmoonOffset:
+		ifno = SeiFileAnyAst
+		ibody = isAnyBody
+		pdp = &swed.Pldat[SeiAnybody]
+	case ipli == SeiChiron, ipli == SeiPholus, ipli == SeiCeres,
+		ipli == SeiPallas, ipli == SeiJuno, ipli == SeiVesta:
+		ifno = SeiFileMainAst
+		ibody = isMainAsteroid
+		pdp = &swed.Pldat[ipli]
+	default:
+		ifno = SeiFilePlanet
+		ibody = isPlanet
+		pdp = &swed.Pldat[ipli]
+	}
+	t := pdp.Teval
+	// 同一时刻已经做过同样的转换
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := pdp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & sefEphMask
+		return Ok, nil
+	}
+	copy(xx[:], pdp.X[:])
+	xx0 = xx
+	// 日心位置
+	if iflag&SeflgHelctr != 0 {
+		if pdp.Iephe == SeflgJpleph || pdp.Iephe == SeflgSwieph {
+			for i := 0; i <= 5; i++ {
+				xx[i] -= swed.Pldat[SeiSunbary].X[i]
+			}
+		}
+	}
+	// 观测者：地心或站心的质心位置
+	if iflag&SeflgTopoctr != 0 {
+		xo, err := topoObserver(pedp.Teval, iflag)
+		if err != nil {
+			return Err, err
+		}
+		for i := 0; i <= 5; i++ {
+			xobs[i] = xo[i] + pedp.X[i]
+		}
+	} else {
+		copy(xobs[:], pedp.X[:])
+	}
+	// 光行时
+	if iflag&SeflgTruepos == 0 {
+		// 迭代次数减一
+		niter := 0
+		if pdp.Iephe == SeflgJpleph || pdp.Iephe == SeflgSwieph {
+			niter = 1
+		}
+		if iflag&SeflgSpeed != 0 {
+			// 视速度受光行时变化的影响，约为每日百分之几角秒。
+			// 为此分别计算t-1时刻和t时刻的真位置与视位置之差。
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xx[i] - xx[i+3]
+				xxsv[i] = xxsp[i]
+			}
+			for j := 0; j <= niter; j++ {
+				for i := 0; i <= 2; i++ {
+					dx[i] = xxsp[i]
+					if iflag&SeflgHelctr == 0 && iflag&SeflgBaryctr == 0 {
+						dx[i] -= xobs[i] - xobs[i+3]
+					}
+				}
+				dt = lightTime(dx[:])
+				for i := 0; i <= 2; i++ {
+					xxsp[i] = xxsv[i] - dt*xx0[i+3]
+				}
+			}
+			// t-1时刻的真位置减视位置
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xxsv[i] - xxsp[i]
+			}
+		}
+		// 光行时和视位置时刻
+		for j := 0; j <= niter; j++ {
+			for i := 0; i <= 2; i++ {
+				dx[i] = xx[i]
+				if iflag&SeflgHelctr == 0 && iflag&SeflgBaryctr == 0 {
+					dx[i] -= xobs[i]
+				}
+			}
+			dt = lightTime(dx[:])
+			t = pdp.Teval - dt
+			dtsaveForDefl = dt
+			for i := 0; i <= 2; i++ {
+				xx[i] = xx0[i] - dt*xx0[i+3]
+			}
+		}
+		// 光行时变化引起的那部分日运动
+		if iflag&SeflgSpeed != 0 {
+			for i := 0; i <= 2; i++ {
+				xxsp[i] = xx0[i] - xx[i] - xxsp[i]
+			}
+		}
+		// 考虑光行时的精确新位置
+		var err error
+		switch epheflag {
+		case SeflgJpleph:
+			if ibody == isPlanet {
+				retc, err = jplPleph(t, pnoint2jpl[ipli], JSbary, xx[:])
+				if retc != Ok {
+					closeJplFileSwe()
+				}
+			} else {
+				// 先算太阳，再算小行星
+				retc, err = jplPleph(t, JSun, JSbary, xsun[:])
+				if retc != Ok {
+					closeJplFileSwe()
+				} else {
+					retc, err = sweph(t, ipli, ifno, iflag, xsun[:], false, xx[:])
+				}
+			}
+			if retc != Ok {
+				return retc, err
+			}
+			// 为了速度的精度，还需要地球
+			if iflag&SeflgSpeed != 0 && iflag&SeflgHelctr == 0 && iflag&SeflgBaryctr == 0 {
+				retc, err = jplPleph(t, JEarth, JSbary, xearth[:])
+				if retc != Ok {
+					closeJplFileSwe()
+					return retc, err
+				}
+			}
+		case SeflgSwieph:
+			if ibody == isPlanet {
+				retc, err = sweplan(t, ipli, ifno, iflag, false, xx[:], xearth[:], xsun[:], nil)
+			} else {
+				retc, err = sweplan(t, SeiEarth, SeiFilePlanet, iflag, false, xearth[:], nil, xsun[:], nil)
+				if retc == Ok {
+					retc, err = sweph(t, ipli, ifno, iflag, xsun[:], false, xx[:])
+				}
+			}
+			if retc != Ok {
+				return retc, err
+			}
+		default:
+			// Moshier星历只需在上面的迭代中减去dt*速度。
+			// 需要速度时在新的时刻重新计算，以提高速度的精度
+			if iflag&SeflgSpeed != 0 && iflag&(SeflgHelctr|SeflgBaryctr) == 0 {
+				if ibody == isPlanet {
+					retc, err = moshplan(t, ipli, false, xxsv[:], xearth[:])
+				} else {
+					retc, err = sweph(t, ipli, ifno, iflag, nil, false, xxsv[:])
+					if retc == Ok {
+						retc, err = moshplan(t, SeiEarth, false, xearth[:], xearth[:])
+					}
+				}
+				if retc != Ok {
+					return retc, err
+				}
+				// 只取速度，否则有无速度时的位置会不一致
+				for i := 3; i <= 5; i++ {
+					xx[i] = xxsv[i]
+				}
+			}
+		}
+		if iflag&SeflgHelctr != 0 {
+			if pdp.Iephe == SeflgJpleph || pdp.Iephe == SeflgSwieph {
+				for i := 0; i <= 5; i++ {
+					xx[i] -= swed.Pldat[SeiSunbary].X[i]
+				}
+			}
+		}
+		// 光行时时刻的观测者位置
+		if iflag&SeflgSpeed != 0 {
+			xobs2 = xearth
+			if iflag&SeflgTopoctr != 0 {
+				xo, err := getObserver(t, iflag|SeflgNonut, false)
+				if err != nil {
+					return Err, err
+				}
+				for i := 0; i <= 5; i++ {
+					xobs2[i] += xo[i]
+				}
+			}
+		}
+	}
+	// 转换为地心坐标
+	if iflag&SeflgHelctr == 0 && iflag&SeflgBaryctr == 0 {
+		for i := 0; i <= 5; i++ {
+			xx[i] -= xobs[i]
+		}
+		// 视速度还受运动中光行时变化的影响
+		if iflag&SeflgTruepos == 0 && iflag&SeflgSpeed != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] -= xxsp[i-3]
+			}
+		}
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// 相对论光线偏折（日心和质心时已关闭）
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNogdefl == 0 {
+		deflectLight(xx[:], dtsaveForDefl, iflag)
+	}
+	// 周年光行差（日心和质心时已关闭）
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNoaberr == 0 {
+		aberrLight(xx[:], xobs[:], iflag)
+		// 视速度还受t与t-dt时刻地球速度之差的影响
+		if iflag&SeflgSpeed != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] += xobs[i] - xobs2[i]
+			}
+		}
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// ICRS到J2000
+	if iflag&SeflgIcrs == 0 && getDenum(ipli, epheflag) >= 403 {
+		bias(xx[:], t, iflag, false)
+	}
+	// 保存J2000坐标，恒星黄道坐标需要
+	xxsv = xx
+	// 岁差：J2000赤道到当天赤道
+	oe := &swed.Oec2000
+	if iflag&SeflgJ2000 == 0 {
+		precess(xx[:], pdp.Teval, iflag, j2000ToJ)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(xx[:], pdp.Teval, iflag, j2000ToJ)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe)
+}
+
+// appPosRest 完成章动、黄道坐标和极坐标的转换，结果保存在pdp.Xreturn中
+// xx为赤道直角坐标，x2000为J2000坐标，oe为所用的黄赤交角
+func appPosRest(pdp *PlanData, iflag Int32, xx, x2000 []Float64, oe *Epsilon) (int, error) {
+	// 章动
+	if iflag&SeflgNonut == 0 {
+		nutate(xx, iflag, false)
+	}
+	// 赤道直角坐标
+	copy(pdp.Xreturn[18:24], xx[:6])
+	// 转换为黄道坐标
+	coortrf2(xx, xx, oe.Seps, oe.Ceps)
+	if iflag&SeflgSpeed != 0 {
+		coortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	}
+	if iflag&SeflgNonut == 0 {
+		coortrf2(xx, xx, swed.Nut.Snut, swed.Nut.Cnut)
+		if iflag&SeflgSpeed != 0 {
+			coortrf2(xx[3:], xx[3:], swed.Nut.Snut, swed.Nut.Cnut)
+		}
+	}
+	// 黄道直角坐标
+	copy(pdp.Xreturn[6:12], xx[:6])
+	// 恒星黄道坐标
+	if iflag&SeflgSidereal != 0 {
+		if swed.Sidd.SidMode&SeSidbitEclT0 != 0 {
+			// 投影到t0的黄道
+			tropRa2sidLon(x2000, pdp.Xreturn[6:], pdp.Xreturn[18:], iflag)
+		} else if swed.Sidd.SidMode&SeSidbitSsyPlane != 0 {
+			// 投影到太阳系不变平面
+			tropRa2sidLonSosy(x2000, pdp.Xreturn[6:], iflag)
+		} else {
+			// 传统算法：从黄经中减去岁差
+			cartpolSp(pdp.Xreturn[6:], pdp.Xreturn[:])
+			// 岁差基于恒星位置时会重新计算行星，先保存当前结果
+			xxsv := pdp.Xreturn
+			daya, _, err := getAyanamsaWithSpeed(pdp.Teval, iflag)
+			if err != nil {
+				return Err, err
+			}
+			pdp.Xreturn = xxsv
+			pdp.Xreturn[0] -= daya[0] * DegToRad
+			pdp.Xreturn[3] -= daya[1] * DegToRad
+			polcartSp(pdp.Xreturn[:], pdp.Xreturn[6:])
+		}
+	}
+	// 转换为极坐标
+	cartpolSp(pdp.Xreturn[18:], pdp.Xreturn[12:])
+	cartpolSp(pdp.Xreturn[6:], pdp.Xreturn[0:])
+	// 弧度转换为度
+	for i := 0; i < 2; i++ {
+		pdp.Xreturn[i] *= RadToDeg // 黄道
+		pdp.Xreturn[i+3] *= RadToDeg
+		pdp.Xreturn[i+12] *= RadToDeg // 赤道
+		pdp.Xreturn[i+15] *= RadToDeg
+	}
+	// 保存所做的转换
+	pdp.Xflgs = iflag
+	pdp.Iephe = iflag & sefEphMask
+	return Ok, nil
+}
+
+// precessSpeed 对速度矢量做岁差改正
+// 先旋转速度矢量，再加上岁差引起的黄经变化（约每日0.137角秒）
+func precessSpeed(xx []Float64, t Float64, iflag Int32, direction int) {
+	fac := Float64(1)
+	oe := &swed.Oec
+	if direction != j2000ToJ {
+		fac = -1
+		oe = &swed.Oec2000
+	}
+	precess(xx[3:], t, iflag, direction)
+	coortrf2(xx, xx, oe.Seps, oe.Ceps)
+	coortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	cartpolSp(xx, xx)
+	precModel := swed.AstroModels[SeModelPrecLongterm]
+	if precModel == 0 {
+		precModel = SemodPrecDefault
+	}
+	if precModel == SemodPrecVondrak2011 {
+		dpre, _ := ldpPeps(t)
+		dpre2, _ := ldpPeps(t + 1)
+		xx[3] += (dpre2 - dpre) * fac
+	} else {
+		// Montenbruck（1994）第18页的公式
+		tprec := (t - J2000) / 36525.0
+		xx[3] += (50.290966 + 0.0222226*tprec) / 3600 / 365.25 * DegToRad * fac
+	}
+	polcartSp(xx, xx)
+	coortrf2(xx, xx, -oe.Seps, oe.Ceps)
+	coortrf2(xx[3:], xx[3:], -oe.Seps, oe.Ceps)
+}
+
+// nutate 用章动矩阵旋转赤道直角坐标，并改正速度
+// backward为真时去除章动
+func nutate(xx []Float64, iflag Int32, backward bool) {
+	var x, xv [6]Float64
+	m := &swed.Nut.Matrix
+	mv := &swed.Nutv.Matrix
+	for i := 0; i <= 2; i++ {
+		if backward {
+			x[i] = xx[0]*m[i][0] + xx[1]*m[i][1] + xx[2]*m[i][2]
+		} else {
+			x[i] = xx[0]*m[0][i] + xx[1]*m[1][i] + xx[2]*m[2][i]
+		}
+	}
+	if iflag&SeflgSpeed != 0 {
+		// 先旋转速度矢量
+		for i := 0; i <= 2; i++ {
+			if backward {
+				x[i+3] = xx[3]*m[i][0] + xx[4]*m[i][1] + xx[5]*m[i][2]
+			} else {
+				x[i+3] = xx[3]*m[0][i] + xx[4]*m[1][i] + xx[5]*m[2][i]
+			}
+		}
+		// 再加上一日内章动变化引起的视运动，约0.01角秒
+		for i := 0; i <= 2; i++ {
+			if backward {
+				xv[i] = xx[0]*mv[i][0] + xx[1]*mv[i][1] + xx[2]*mv[i][2]
+			} else {
+				xv[i] = xx[0]*mv[0][i] + xx[1]*mv[1][i] + xx[2]*mv[2][i]
+			}
+			xx[3+i] = x[3+i] + (x[i]-xv[i])/nutSpeedIntv
+		}
+	}
+	copy(xx[:3], x[:3])
+}
+
+// aberrLight 周年光行差改正
+// xx为经过光行时和引力偏折改正的位置，xe为地球（观测者）的位置和速度
+func aberrLight(xx, xe []Float64, iflag Int32) {
+	var xxs, v, u, xx2 [6]Float64
+	intv := Float64(planSpeedIntv)
+	copy(xxs[:], xx[:6])
+	u = xxs
+	ru := math.Sqrt(squareSum3(u[:]))
+	for i := 0; i <= 2; i++ {
+		v[i] = xe[i+3] / 24.0 / 3600.0 / Clight * Aunit
+	}
+	v2 := squareSum3(v[:])
+	b1 := math.Sqrt(1 - v2)
+	f1 := dotProd3(u[:], v[:]) / ru
+	f2 := 1.0 + f1/(1.0+b1)
+	for i := 0; i <= 2; i++ {
+		xx[i] = (b1*xx[i] + f2*ru*v[i]) / (1.0 + f1)
+	}
+	if iflag&SeflgSpeed != 0 {
+		// 光行差对视速度的影响可达每日0.4角秒
+		for i := 0; i <= 2; i++ {
+			u[i] = xxs[i] - intv*xxs[i+3]
+		}
+		ru = math.Sqrt(squareSum3(u[:]))
+		f1 = dotProd3(u[:], v[:]) / ru
+		f2 = 1.0 + f1/(1.0+b1)
+		for i := 0; i <= 2; i++ {
+			xx2[i] = (b1*u[i] + f2*ru*v[i]) / (1.0 + f1)
+		}
+		for i := 0; i <= 2; i++ {
+			dx1 := xx[i] - xxs[i]
+			dx2 := xx2[i] - u[i]
+			dx1 -= dx2
+			xx[i+3] += dx1 / intv
+		}
+	}
+}
+
+// aberrLightEx 周年光行差改正，速度由观测者在tjd-dt时刻的位置xeDt求得
+func aberrLightEx(xx, xe, xeDt []Float64, dt Float64, iflag Int32) {
+	var xxs, xx2 [6]Float64
+	copy(xxs[:], xx[:6])
+	aberrLight(xx, xe, 0)
+	// 光行差对视速度的影响可达每日0.4角秒
+	if iflag&SeflgSpeed != 0 {
+		for i := 0; i <= 2; i++ {
+			xx2[i] = xxs[i] - dt*xxs[i+3]
+		}
+		aberrLight(xx2[:], xeDt, 0)
+		for i := 0; i <= 2; i++ {
+			xx[i+3] = (xx[i] - xx2[i]) / dt
+		}
+	}
+}
+
+// deflectLight 太阳引起的相对论光线偏折
+// xx为经过光行时改正的地心位置，dt为光行时
+func deflectLight(xx []Float64, dt Float64, iflag Int32) {
+	var xx2, xx3, xsun, xearth [6]Float64
+	var u, e, q [3]Float64
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	iephe := pedp.Iephe
+	copy(xearth[:], pedp.X[:])
+	if iflag&SeflgTopoctr != 0 {
+		for i := 0; i <= 5; i++ {
+			xearth[i] += swed.Topd.Xobs[i]
+		}
+	}
+	// 太阳在t-tau时刻的质心位置
+	if iephe == SeflgJpleph || iephe == SeflgSwieph {
+		for i := 0; i <= 2; i++ {
+			xsun[i] = psdp.X[i] - dt*psdp.X[i+3]
+		}
+		for i := 3; i <= 5; i++ {
+			xsun[i] = psdp.X[i]
+		}
+	} else {
+		copy(xsun[:], psdp.X[:])
+	}
+	// deflected 由u（地心行星）、e（日心地球）和q（日心行星）计算偏折后的位置
+	deflected := func(out []Float64) Float64 {
+		ru := math.Sqrt(squareSum3(u[:]))
+		rq := math.Sqrt(squareSum3(q[:]))
+		re := math.Sqrt(squareSum3(e[:]))
+		for i := 0; i <= 2; i++ {
+			u[i] /= ru
+			q[i] /= rq
+			e[i] /= re
+		}
+		uq := dotProd3(u[:], q[:])
+		ue := dotProd3(u[:], e[:])
+		qe := dotProd3(q[:], e[:])
+		// 行星接近太阳中心时，点质量公式不再适用，
+		// 改用考虑太阳内部质量分布的有效质量
+		sina := math.Sqrt(1 - ue*ue)
+		sinSunr := sunRadius / re
+		meffFact := 1.0
+		if sina < sinSunr {
+			meffFact = meff(sina / sinSunr)
+		}
+		g1 := 2.0 * Helgravconst * meffFact / Clight / Clight / Aunit / re
+		g2 := 1.0 + qe
+		for i := 0; i <= 2; i++ {
+			out[i] = ru * (u[i] + g1/g2*(uq*e[i]-ue*q[i]))
+		}
+		return ru
+	}
+	// U = 地心行星，E = 日心地球，Q = 日心行星
+	for i := 0; i <= 2; i++ {
+		u[i] = xx[i]
+		if iephe == SeflgJpleph || iephe == SeflgSwieph {
+			e[i] = xearth[i] - psdp.X[i]
+		} else {
+			e[i] = xearth[i]
+		}
+		q[i] = xx[i] + xearth[i] - xsun[i]
+	}
+	deflected(xx2[:])
+	if iflag&SeflgSpeed != 0 {
+		// 光线偏折对视速度的影响：在日面边缘可达每日7角秒，
+		// 在日面内可达30角秒以上。用稍有不同的u、e、q重新计算偏折量，
+		// 由两者之差求速度改正
+		dtsp := -Float64(deflSpeedIntv)
+		for i := 0; i <= 2; i++ {
+			u[i] = xx[i] - dtsp*xx[i+3]
+			if iephe == SeflgJpleph || iephe == SeflgSwieph {
+				e[i] = xearth[i] - psdp.X[i] - dtsp*(xearth[i+3]-psdp.X[i+3])
+			} else {
+				e[i] = xearth[i] - dtsp*xearth[i+3]
+			}
+			q[i] = u[i] + xearth[i] - xsun[i] - dtsp*(xearth[i+3]-xsun[i+3])
+		}
+		ru := deflected(xx3[:])
+		for i := 0; i <= 2; i++ {
+			dx1 := xx2[i] - xx[i]
+			dx2 := xx3[i] - u[i]*ru
+			dx1 -= dx2
+			xx[i+3] += dx1 / dtsp
+		}
+	}
+	copy(xx[:3], xx2[:3])
+}
+
+// meffTable 光子以最小距离r（太阳半径的分数）经过太阳时的有效质量
+// 太阳质量分布取自Michael Stix, The Sun, p. 47
+var meffTable = [...][2]Float64{
+	{1.000, 1.000000},
+	{0.990, 0.999979},
+	{0.980, 0.999940},
+	{0.970, 0.999881},
+	{0.960, 0.999811},
+	{0.950, 0.999724},
+	{0.940, 0.999622},
+	{0.930, 0.999497},
+	{0.920, 0.999354},
+	{0.910, 0.999192},
+	{0.900, 0.999000},
+	{0.890, 0.998786},
+	{0.880, 0.998535},
+	{0.870, 0.998242},
+	{0.860, 0.997919},
+	{0.850, 0.997571},
+	{0.840, 0.997198},
+	{0.830, 0.996792},
+	{0.820, 0.996316},
+	{0.810, 0.995791},
+	{0.800, 0.995226},
+	{0.790, 0.994625},
+	{0.780, 0.993991},
+	{0.770, 0.993326},
+	{0.760, 0.992598},
+	{0.750, 0.991770},
+	{0.740, 0.990873},
+	{0.730, 0.989919},
+	{0.720, 0.988912},
+	{0.710, 0.987856},
+	{0.700, 0.986755},
+	{0.690, 0.985610},
+	{0.680, 0.984398},
+	{0.670, 0.982986},
+	{0.660, 0.981437},
+	{0.650, 0.979779},
+	{0.640, 0.978024},
+	{0.630, 0.976182},
+	{0.620, 0.974256},
+	{0.610, 0.972253},
+	{0.600, 0.970174},
+	{0.590, 0.968024},
+	{0.580, 0.965594},
+	{0.570, 0.962797},
+	{0.560, 0.959758},
+	{0.550, 0.956515},
+	{0.540, 0.953088},
+	{0.530, 0.949495},
+	{0.520, 0.945741},
+	{0.510, 0.941838},
+	{0.500, 0.937790},
+	{0.490, 0.933563},
+	{0.480, 0.928668},
+	{0.470, 0.923288},
+	{0.460, 0.917527},
+	{0.450, 0.911432},
+	{0.440, 0.905035},
+	{0.430, 0.898353},
+	{0.420, 0.891022},
+	{0.410, 0.882940},
+	{0.400, 0.874312},
+	{0.390, 0.865206},
+	{0.380, 0.855423},
+	{0.370, 0.844619},
+	{0.360, 0.833074},
+	{0.350, 0.820876},
+	{0.340, 0.808031},
+	{0.330, 0.793962},
+	{0.320, 0.778931},
+	{0.310, 0.763021},
+	{0.300, 0.745815},
+	{0.290, 0.727557},
+	{0.280, 0.708234},
+	{0.270, 0.687583},
+	{0.260, 0.665741},
+	{0.250, 0.642597},
+	{0.240, 0.618252},
+	{0.230, 0.592586},
+	{0.220, 0.565747},
+	{0.210, 0.537697},
+	{0.200, 0.508554},
+	{0.190, 0.478420},
+	{0.180, 0.447322},
+	{0.170, 0.415454},
+	{0.160, 0.382892},
+	{0.150, 0.349955},
+	{0.140, 0.316691},
+	{0.130, 0.283565},
+	{0.120, 0.250431},
+	{0.110, 0.218327},
+	{0.100, 0.186794},
+	{0.090, 0.156287},
+	{0.080, 0.128421},
+	{0.070, 0.102237},
+	{0.060, 0.077393},
+	{0.050, 0.054833},
+	{0.040, 0.036361},
+	{0.030, 0.020953},
+	{0.020, 0.009645},
+	{0.010, 0.002767},
+	{0.000, 0.000000},
+}
+
+// meff 返回距离太阳中心r（太阳半径的分数）处的有效质量比例
+func meff(r Float64) Float64 {
+	if r <= 0 {
+		return 0.0
+	} else if r >= 1 {
+		return 1.0
+	}
+	i := 0
+	for meffTable[i][0] > r {
+		i++
+	}
+	f := (r - meffTable[i-1][0]) / (meffTable[i][0] - meffTable[i-1][0])
+	return meffTable[i-1][1] + f*(meffTable[i][1]-meffTable[i-1][1])
+}
+
+// appPosEtcSun 将太阳由质心坐标转换为地心视位置，或将地球转换为日心（质心）位置
+func appPosEtcSun(iflag Int32) (int, error) {
+	var xx, xxsv, xearth, xsun, xobs [6]Float64
+	var dx [3]Float64
+	var t Float64
+	retc := Ok
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	// 同一时刻已经做过同样的转换
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := pedp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	if flg1 == flg2 {
+		pedp.Xflgs = iflag
+		pedp.Iephe = iflag & sefEphMask
+		return Ok, nil
+	}
+	// 观测者：地心或站心的质心位置
+	if iflag&SeflgTopoctr != 0 {
+		xo, err := topoObserver(pedp.Teval, iflag)
+		if err != nil {
+			return Err, err
+		}
+		for i := 0; i <= 5; i++ {
+			xobs[i] = xo[i] + pedp.X[i]
+		}
+	} else {
+		copy(xobs[:], pedp.X[:])
+	}
+	// 地球的真日心位置
+	if pedp.Iephe == SeflgMoseph || iflag&SeflgBaryctr != 0 {
+		xx = xobs
+	} else {
+		for i := 0; i <= 5; i++ {
+			xx[i] = xobs[i] - psdp.X[i]
+		}
+	}
+	// 光行时
+	// JPL和瑞士星历：地心太阳改正质心太阳的位置，日心或质心地球改正地球的位置。
+	// Moshier星历（日心）：地心太阳不做改正（之后做光行差），日心或质心地球改正地球的位置
+	if iflag&SeflgTruepos == 0 {
+		if pedp.Iephe == SeflgJpleph || pedp.Iephe == SeflgSwieph ||
+			iflag&SeflgHelctr != 0 || iflag&SeflgBaryctr != 0 {
+			xearth = xobs
+			if pedp.Iephe != SeflgMoseph {
+				copy(xsun[:], psdp.X[:])
+			}
+			niter := 1
+			for j := 0; j <= niter; j++ {
+				// 日地距离
+				for i := 0; i <= 2; i++ {
+					dx[i] = xearth[i]
+					if iflag&SeflgBaryctr == 0 {
+						dx[i] -= xsun[i]
+					}
+				}
+				t = pedp.Teval - lightTime(dx[:])
+				var err error
+				switch pedp.Iephe {
+				case SeflgJpleph:
+					if iflag&SeflgHelctr != 0 || iflag&SeflgBaryctr != 0 {
+						retc, err = jplPleph(t, JEarth, JSbary, xearth[:])
+					} else {
+						retc, err = jplPleph(t, JSun, JSbary, xsun[:])
+					}
+					if retc != Ok {
+						closeJplFileSwe()
+					}
+				case SeflgSwieph:
+					if iflag&SeflgHelctr != 0 || iflag&SeflgBaryctr != 0 {
+						retc, err = sweplan(t, SeiEarth, SeiFilePlanet, iflag, false, xearth[:], nil, xsun[:], nil)
+					} else {
+						retc, err = sweph(t, SeiSunbary, SeiFilePlanet, iflag, nil, false, xsun[:])
+					}
+				case SeflgMoseph:
+					// Moshier星历没有质心太阳
+					if iflag&SeflgHelctr != 0 || iflag&SeflgBaryctr != 0 {
+						retc, err = moshplan(t, SeiEarth, false, xearth[:], xearth[:])
+					}
+				default:
+					retc = Err
+				}
+				if retc != Ok {
+					return retc, err
+				}
+			}
+			// 地球的视日心位置
+			for i := 0; i <= 5; i++ {
+				xx[i] = xearth[i]
+				if iflag&SeflgBaryctr == 0 {
+					xx[i] -= xsun[i]
+				}
+			}
+		}
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// 转换为地心坐标
+	if iflag&SeflgHelctr == 0 && iflag&SeflgBaryctr == 0 {
+		for i := 0; i <= 5; i++ {
+			xx[i] = -xx[i]
+		}
+	}
+	// 周年光行差（日心和质心时已关闭）
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNoaberr == 0 {
+		aberrLight(xx[:], xobs[:], iflag)
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// ICRS到J2000
+	if iflag&SeflgIcrs == 0 && getDenum(SeiSun, iflag) >= 403 {
+		bias(xx[:], t, iflag, false)
+	}
+	// 保存J2000坐标，恒星黄道坐标需要
+	xxsv = xx
+	// 岁差：J2000赤道到当天赤道
+	oe := &swed.Oec2000
+	if iflag&SeflgJ2000 == 0 {
+		precess(xx[:], pedp.Teval, iflag, j2000ToJ)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(xx[:], pedp.Teval, iflag, j2000ToJ)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pedp, iflag, xx[:], xxsv[:], oe)
+}
+
+// appPosEtcMoon 将月球转换为地心（日心、质心）视位置
+// 视位置把地月系统作为独立系统处理；天体测量位置则考虑地球和月球相对于太阳系质心的运动
+func appPosEtcMoon(iflag Int32) (int, error) {
+	var xx, xxsv, xobs, xxm, xs, xe, xobs2 [6]Float64
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	pdp := &swed.Pldat[SeiMoon]
+	// 同一时刻已经做过同样的转换
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := pdp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & sefEphMask
+		return Ok, nil
+	}
+	copy(xx[:], pdp.X[:])
+	xxm = xx
+	// 转换为太阳系质心坐标
+	for i := 0; i <= 5; i++ {
+		xx[i] += pedp.X[i]
+	}
+	// 观测者
+	switch {
+	case iflag&SeflgTopoctr != 0:
+		xo, err := topoObserver(pdp.Teval, iflag)
+		if err != nil {
+			return Err, err
+		}
+		for i := 0; i <= 5; i++ {
+			xxm[i] -= xo[i]
+			xobs[i] = xo[i] + pedp.X[i]
+		}
+	case iflag&SeflgBaryctr != 0:
+		for i := 0; i <= 5; i++ {
+			xxm[i] += pedp.X[i]
+		}
+	case iflag&SeflgHelctr != 0:
+		copy(xobs[:], psdp.X[:])
+		for i := 0; i <= 5; i++ {
+			xxm[i] += pedp.X[i] - psdp.X[i]
+		}
+	default:
+		copy(xobs[:], pedp.X[:])
+	}
+	// 光行时
+	t := pdp.Teval
+	if iflag&SeflgTruepos == 0 {
+		dt := lightTime(xxm[:])
+		t = pdp.Teval - dt
+		switch pdp.Iephe {
+		case SeflgJpleph:
+			retc, err := jplPleph(t, JMoon, JEarth, xx[:])
+			if retc == Ok {
+				retc, err = jplPleph(t, JEarth, JSbary, xe[:])
+			}
+			if retc == Ok && iflag&SeflgHelctr != 0 {
+				retc, err = jplPleph(t, JSun, JSbary, xs[:])
+			}
+			if retc != Ok {
+				closeJplFileSwe()
+				return retc, err
+			}
+			for i := 0; i <= 5; i++ {
+				xx[i] += xe[i]
+			}
+		case SeflgSwieph:
+			if retc, err := sweplan(t, SeiMoon, SeiFileMoon, iflag, false, xx[:], xe[:], xs[:], nil); retc != Ok {
+				return retc, err
+			}
+			for i := 0; i <= 5; i++ {
+				xx[i] += xe[i]
+			}
+		case SeflgMoseph:
+			// 此方法导致速度有千分之一角秒的误差
+			for i := 0; i <= 2; i++ {
+				xx[i] -= dt * xx[i+3]
+				xe[i] = pedp.X[i] - dt*pedp.X[i+3]
+				xe[i+3] = pedp.X[i+3]
+				xs[i] = 0
+				xs[i+3] = 0
+			}
+		}
+		switch {
+		case iflag&SeflgTopoctr != 0:
+			xo, err := getObserver(t, iflag|SeflgNonut, false)
+			if err != nil {
+				return Err, err
+			}
+			for i := 0; i <= 5; i++ {
+				xobs2[i] = xo[i] + xe[i]
+			}
+		case iflag&SeflgBaryctr != 0:
+			xobs2 = [6]Float64{}
+		case iflag&SeflgHelctr != 0:
+			xobs2 = xs
+		default:
+			xobs2 = xe
+		}
+	}
+	// 转换到所需的中心
+	for i := 0; i <= 5; i++ {
+		xx[i] -= xobs[i]
+	}
+	// 周年光行差（日心和质心时已关闭）
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNoaberr == 0 {
+		aberrLight(xx[:], xobs[:], iflag)
+		// 视速度还受t与t-dt时刻地球速度之差的影响
+		if iflag&SeflgSpeed != 0 {
+			for i := 3; i <= 5; i++ {
+				xx[i] += xobs[i] - xobs2[i]
+			}
+		}
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// ICRS到J2000
+	if iflag&SeflgIcrs == 0 && getDenum(SeiMoon, iflag) >= 403 {
+		bias(xx[:], t, iflag, false)
+	}
+	// 保存J2000坐标，恒星黄道坐标需要
+	xxsv = xx
+	// 岁差：J2000赤道到当天赤道
+	oe := &swed.Oec2000
+	if iflag&SeflgJ2000 == 0 {
+		precess(xx[:], pdp.Teval, iflag, j2000ToJ)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(xx[:], pdp.Teval, iflag, j2000ToJ)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe)
+}
+
+// appPosEtcSbar 质心太阳的光行时、岁差和章动，结果保存在地球的数据区中
+func appPosEtcSbar(iflag Int32) (int, error) {
+	var xx, xxsv [6]Float64
+	psdp := &swed.Pldat[SeiEarth]
+	psbdp := &swed.Pldat[SeiSunbary]
+	copy(xx[:], psbdp.X[:])
+	// 光行时
+	if iflag&SeflgTruepos == 0 {
+		dt := lightTime(xx[:])
+		for i := 0; i <= 2; i++ {
+			xx[i] -= dt * xx[i+3]
+		}
+	}
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// ICRS到J2000
+	if iflag&SeflgIcrs == 0 && getDenum(SeiSun, iflag) >= 403 {
+		bias(xx[:], psdp.Teval, iflag, false)
+	}
+	// 保存J2000坐标，恒星黄道坐标需要
+	xxsv = xx
+	// 岁差：J2000赤道到当天赤道
+	oe := &swed.Oec2000
+	if iflag&SeflgJ2000 == 0 {
+		precess(xx[:], psbdp.Teval, iflag, j2000ToJ)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(xx[:], psbdp.Teval, iflag, j2000ToJ)
+		}
+		oe = &swed.Oec
+	}
+	return appPosRest(psdp, iflag, xx[:], xxsv[:], oe)
+}
+
+// appPosEtcMean 转换月球平交点或平远地点
+// 输入为当天平黄道极坐标，根据iflag输出J2000、章动、赤道或直角坐标
+func appPosEtcMean(ipl int, iflag Int32) (int, error) {
+	var xx, xxsv [6]Float64
+	pdp := &swed.Nddat[ipl]
+	// 同一时刻已经做过同样的转换
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := pdp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	if flg1 == flg2 {
+		pdp.Xflgs = iflag
+		pdp.Iephe = iflag & sefEphMask
+		return Ok, nil
+	}
+	copy(xx[:], pdp.X[:])
+	// 赤道直角坐标
+	polcartSp(xx[:], xx[:])
+	coortrf2(xx[:], xx[:], -swed.Oec.Seps, swed.Oec.Ceps)
+	coortrf2(xx[3:], xx[3:], -swed.Oec.Seps, swed.Oec.Ceps)
+	if iflag&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// 恒星黄道坐标需要J2000坐标
+	if iflag&SeflgSidereal != 0 && swed.Sidd.SidMode&SeSidbitEclT0 != 0 ||
+		swed.Sidd.SidMode&SeSidbitSsyPlane != 0 {
+		xxsv = xx
+		if pdp.Teval != J2000 {
+			precess(xxsv[:], pdp.Teval, iflag, jToJ2000)
+			if iflag&SeflgSpeed != 0 {
+				precessSpeed(xxsv[:], pdp.Teval, iflag, jToJ2000)
+			}
+		}
+	}
+	// 无岁差时由当天赤道转换到J2000赤道
+	oe := &swed.Oec
+	if iflag&SeflgJ2000 != 0 {
+		precess(xx[:], pdp.Teval, iflag, jToJ2000)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(xx[:], pdp.Teval, iflag, jToJ2000)
+		}
+		oe = &swed.Oec2000
+	}
+	return appPosRest(pdp, iflag, xx[:], xxsv[:], oe)
+}
@@ -0,0 +1,401 @@
+// 天文模型（ΔT、岁差、章动、参考架偏差、恒星时）的选择，
+// 移植自 swephlib.c 的 swe_set_astro_models 和 swe_get_astro_models。
+
+package ephgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 各版本Swiss Ephemeris所用的模型，顺序为 D P P N B J J S
+const (
+	amodelsSe100 = "1,3,1,1,1,0,0,1"
+	amodelsSe164 = "2,3,1,1,1,0,0,1"
+	amodelsSe170 = "2,8,8,4,2,0,0,2"
+	amodelsSe172 = "3,8,8,4,2,0,0,2"
+	amodelsSe177 = "4,8,8,4,2,0,0,2"
+	amodelsSe178 = "4,9,9,4,2,0,0,2"
+	amodelsSe180 = "4,9,9,4,3,0,0,1" // 注意恒星时模型
+	amodelsSe200 = "4,9,9,4,3,0,0,4"
+	amodelsSe206 = "5,9,9,4,3,0,0,4"
+)
+
+// tidAccSe1Old 1.77版以前所用的月球潮汐加速度
+const tidAccSe1Old = -25.7376
+
+// astroModelCount 各类模型的个数，下标为SeModel*
+var astroModelCount = [SeiNmodels]Int32{
+	SeModelDeltat:        SemodNdeltat,
+	SeModelPrecLongterm:  SemodNprec,
+	SeModelPrecShortterm: SemodNprec,
+	SeModelNut:           SemodNnut,
+	SeModelBias:          SemodNbias,
+	SeModelJplhorMode:    SemodNjplhor,
+	SeModelJplhoraMode:   SemodNjplhora,
+	SeModelSidt:          SemodNsidt,
+}
+
+// astroModelDefault 各类模型的默认值，下标为SeModel*
+var astroModelDefault = [SeiNmodels]Int32{
+	SeModelDeltat:        SemodDeltatDefault,
+	SeModelPrecLongterm:  SemodPrecDefault,
+	SeModelPrecShortterm: SemodPrecDefaultShort,
+	SeModelNut:           SemodNutDefault,
+	SeModelBias:          SemodBiasDefault,
+	SeModelJplhorMode:    SemodJplhorDefault,
+	SeModelJplhoraMode:   SemodJplhoraDefault,
+	SeModelSidt:          SemodSidtDefault,
+}
+
+// SetAstroModels 选择计算所用的天文模型
+// samod可以是逗号分隔的模型编号，顺序为 D P P N B J J S（ΔT、长期岁差、
+// 短期岁差、章动、参考架偏差、JPL Horizons模式、近似JPL Horizons模式、恒星时），
+// 0表示默认模型，如"4,9,9,4,3,0,0,1"；
+// 也可以是版本字符串，如"SE2.05.01"或"SE1.80"，选用该版本所用的模型和潮汐加速度，
+// 用于重现旧版本的结果；空字符串选用当前版本的模型
+func SetAstroModels(samod string, iflag Int32) {
+	swed := GetSweData()
+	setAstroModels(samod, iflag)
+	SetSweData(swed)
+}
+
+// setAstroModels 按模型编号列表或版本字符串设置天文模型
+func setAstroModels(samod string, iflag Int32) {
+	if samod != "" && samod[0] >= '0' && samod[0] <= '9' {
+		setAstroModelList(samod)
+		return
+	}
+	if samod != "" && !strings.HasPrefix(samod, "SE") {
+		return
+	}
+	s := samod
+	if len(s) > 20 {
+		s = s[:20]
+	}
+	// 去掉"SE2.05.01"中的第二个'.'和"SE2.05.02b04"中的'b'
+	if len(s) > 5 {
+		if i := strings.IndexByte(s[5:], '.'); i >= 0 {
+			s = s[:5+i] + s[5+i+1:]
+		}
+	}
+	if len(s) > 5 {
+		if i := strings.IndexByte(s[5:], 'b'); i >= 0 {
+			s = s[:5+i] + s[5+i+1:]
+		}
+	}
+	var dversion Float64
+	if len(s) > 2 {
+		dversion = atofPrefix(s[2:])
+	}
+	if dversion == 0 {
+		dversion = atofPrefix(SeVersion)
+	}
+	switch {
+	case dversion >= 2.06:
+		setAstroModelList(amodelsSe206)
+	case dversion >= 2.01:
+		setAstroModelList(amodelsSe200)
+	case dversion >= 2.00:
+		setAstroModelList(amodelsSe200)
+		if getDenum(SeiSun, iflag) == 431 {
+			setTidAcc(SeTidalDe406)
+		}
+	case dversion >= 1.80:
+		setAstroModelList(amodelsSe180)
+		setTidAcc(SeTidalDe406)
+	case dversion >= 1.78:
+		setAstroModelList(amodelsSe178)
+		setTidAcc(SeTidalDe406)
+	case dversion >= 1.77:
+		setAstroModelList(amodelsSe177)
+		setTidAcc(SeTidalDe406)
+	case dversion >= 1.72:
+		setAstroModelList(amodelsSe172)
+		setTidAcc(tidAccSe1Old)
+	case dversion >= 1.70:
+		setAstroModelList(amodelsSe170)
+		setTidAcc(tidAccSe1Old)
+	case dversion >= 1.64:
+		setAstroModelList(amodelsSe164)
+		setTidAcc(tidAccSe1Old)
+	default:
+		setAstroModelList(amodelsSe100)
+		setTidAcc(tidAccSe1Old)
+	}
+}
+
+// setAstroModelList 按逗号分隔的模型编号设置天文模型，缺少的项不变
+func setAstroModelList(samod string) {
+	for i, f := range strings.SplitN(samod, ",", SeiNmodels+1) {
+		if i >= SeiNmodels {
+			break
+		}
+		swed.AstroModels[i] = Int32(atofPrefix(f))
+	}
+	resetModelDependentData()
+}
+
+// resetModelDependentData 模型改变后，使保存的黄赤交角、章动和位置失效
+// Moshier星历的原始位置也依赖岁差模型，因此一并重新计算
+func resetModelDependentData() {
+	swed.Oec = Epsilon{}
+	swed.Oec2000 = Epsilon{}
+	swed.Nut = Nut{}
+	swed.Nut2000 = Nut{}
+	swed.Nutv = Nut{}
+	for i := range swed.Pldat {
+		swed.Pldat[i].Teval = 0
+	}
+	for i := range swed.Nddat {
+		swed.Nddat[i].Teval = 0
+	}
+	forceAppPosEtc()
+}
+
+// SetAstroModel 设置一类天文模型
+// model为SeModel*，value为相应的SemodXxx常量，0表示默认模型
+func SetAstroModel(model int, value Int32) error {
+	if model < 0 || model >= SeiNmodels {
+		return fmt.Errorf("无效的模型类别%d", model)
+	}
+	if value < 0 || value > astroModelCount[model] {
+		return fmt.Errorf("模型类别%d没有编号为%d的模型", model, value)
+	}
+	swed := GetSweData()
+	swed.AstroModels[model] = value
+	resetModelDependentData()
+	SetSweData(swed)
+	return nil
+}
+
+// GetAstroModel 返回一类天文模型当前所用的模型，未设置时返回默认模型
+// model为SeModel*，无效时返回0
+func GetAstroModel(model int) Int32 {
+	if model < 0 || model >= SeiNmodels {
+		return 0
+	}
+	swed := GetSweData()
+	if swed.AstroModels[model] == 0 {
+		return astroModelDefault[model]
+	}
+	return swed.AstroModels[model]
+}
+
+// precessionModelName 岁差模型的名称
+func precessionModelName(precmod Int32) string {
+	if precmod == 0 {
+		precmod = SemodPrecDefault
+	}
+	switch precmod {
+	case SemodPrecIau1976:
+		return "IAU 1976 (Lieske)"
+	case SemodPrecIau2000:
+		return "IAU 2000 (Lieske 1976, Mathews 2002)"
+	case SemodPrecIau2006:
+		return "IAU 2006 (Capitaine & alii)"
+	case SemodPrecBretagnon2003:
+		return "Bretagnon 2003"
+	case SemodPrecLaskar1986:
+		return "Laskar 1986"
+	case SemodPrecSimon1994:
+		return "Simon 1994"
+	case SemodPrecWilliams1994:
+		return "Williams 1994"
+	case SemodPrecWillEpsLask:
+		return "Williams 1994 / Epsilon Laskar 1986"
+	case SemodPrecOwen1990:
+		return "Owen 1990"
+	case SemodPrecNewcomb:
+		return "Newcomb 1895"
+	case SemodPrecVondrak2011:
+		return "Vondrák 2011"
+	}
+	return ""
+}
+
+// deltatModelName ΔT长期模型的名称
+func deltatModelName(dtmod Int32) string {
+	if dtmod == 0 {
+		dtmod = SemodDeltatDefault
+	}
+	switch dtmod {
+	case SemodDeltatEspenakMeeus2006:
+		return "Espenak/Meeus 2006 (before 1633)"
+	case SemodDeltatStephensonMorrison2004:
+		return "Stephenson/Morrison 2004 (before 1600)"
+	case SemodDeltatStephenson1997:
+		return "Stephenson 1997 (before 1600)"
+	case SemodDeltatStephensonMorrison1984:
+		return "Stephenson/Morrison 1984 (before 1600)"
+	case SemodDeltatStephensonEtc2016:
+		return "Stephenson/Morrison/Hohenkerk 2016 (before 1955)"
+	}
+	return ""
+}
+
+// nutationModelName 章动模型的名称
+func nutationModelName(nutmod Int32) string {
+	if nutmod == 0 {
+		nutmod = SemodNutDefault
+	}
+	switch nutmod {
+	case SemodNutWoolard:
+		return "Woolard 1953"
+	case SemodNutIau1980:
+		return "IAU 1980 (Wahr)"
+	case SemodNutIauCorr1987:
+		return "Herring 1986"
+	case SemodNutIau2000A:
+		return "IAU 2000A (Mathews)"
+	case SemodNutIau2000B:
+		return "IAU 2000B (Mathews)"
+	}
+	return ""
+}
+
+// frameBiasModelName 参考架偏差模型的名称
+func frameBiasModelName(biasmod Int32) string {
+	if biasmod == 0 {
+		biasmod = SemodBiasDefault
+	}
+	switch biasmod {
+	case SemodBiasIau2000:
+		return "IAU 2000"
+	case SemodBiasIau2006:
+		return "IAU 2006"
+	case SemodBiasNone:
+		return "none"
+	}
+	return ""
+}
+
+// sidtModelName 恒星时模型的名称
+func sidtModelName(sidtmod Int32) string {
+	if sidtmod == 0 {
+		sidtmod = SemodSidtDefault
+	}
+	switch sidtmod {
+	case SemodSidtIau1976:
+		return "IAU 1976"
+	case SemodSidtIau2006:
+		return "IAU 2006 (Capitaine 2003)"
+	case SemodSidtIersConv2010:
+		return "IERS Convention 2010"
+	case SemodSidtLongterm:
+		return "IERS Convention 2010 + long-term extension by Astrodienst"
+	}
+	return ""
+}
+
+// jplhorModelName 使用JPL星历的JPL Horizons模式时，岁差（nutation为假）或章动模型的名称
+func jplhorModelName(name string, iflag Int32, nutation bool) string {
+	if iflag&SeflgJpleph == 0 {
+		return name
+	}
+	if !nutation {
+		switch {
+		case iflag&SeflgJplhor != 0:
+			return "IAU 1976 (Lieske) / Owen 1990 before 1799"
+		case iflag&SeflgJplhorApprox != 0:
+			return "Vondrak 2011 / IAU 1976 (Lieske) before 1962 / Owen 1990 before 1799"
+		}
+		return name
+	}
+	switch {
+	case iflag&SeflgJplhor != 0:
+		name = "IAU 1980 (Wahr)\n+ daily corrections to dpsi/deps 1962-today"
+		if swed.AstroModels[SeModelJplhorMode] == 0 || swed.AstroModels[SeModelJplhorMode] == SemodJplhorLongAgreement {
+			name += "\n  good agreement with JPL Horizons between 1800 and today"
+		} else {
+			name += "\n  defaults to SEFLG_JPLEPH_APPROX before 1962"
+		}
+	case iflag&SeflgJplhorApprox != 0:
+		name += fmt.Sprintf("\n+ some corrections, approximating JPL Horizons (SEMOD_JPLHORA_%d)", jplhoraModel())
+	}
+	return name
+}
+
+// GetAstroModels 返回当前所用的天文模型
+// samod不为空时先按SetAstroModels设置模型；samod中含有'+'时在说明中列出所有可用的模型
+// 返回值models为逗号分隔的模型编号（默认模型为0），格式同SetAstroModels；
+// sdet为各模型的文字说明，与swe_get_astro_models的输出相同
+func GetAstroModels(samod string, iflag Int32) (models string, sdet string) {
+	swed := GetSweData()
+	defer SetSweData(swed)
+	listAllModels := strings.Contains(samod, "+")
+	if samod != "" {
+		setAstroModels(samod, iflag)
+	}
+	pmodel := &swed.AstroModels
+	var sb strings.Builder
+	for i := 0; i < SeiNmodels; i++ {
+		imod := pmodel[i]
+		if imod == astroModelDefault[i] {
+			imod = 0
+		}
+		fmt.Fprintf(&sb, "%d,", imod)
+	}
+	models = sb.String()
+	var sd strings.Builder
+	// 星历的JPL编号及与之配合的潮汐加速度
+	fmt.Fprintf(&sd, "JPL eph. %d; tidal acc. Moon used by SE: %.4f\n", getDenum(SeiSun, iflag), getTidAcc())
+	switch {
+	case iflag&SeflgJpleph != 0:
+		if iflag&SeflgJplhor != 0 {
+			sd.WriteString("JPL Horizons method:\n")
+		}
+		if iflag&SeflgJplhorApprox != 0 {
+			sd.WriteString("JPL Horizons method (approximation):\n")
+		}
+	case iflag&SeflgSwieph != 0:
+		sd.WriteString("Swiss Ephemeris compressed files sepl*/semo*\n")
+	default:
+		sd.WriteString("Moshier semi-analytical approximation\n")
+	}
+	fmt.Fprintf(&sd, "Delta T (long-term): %s\n", deltatModelName(pmodel[SeModelDeltat]))
+	fmt.Fprintf(&sd, "Precession: %s\n", jplhorModelName(precessionModelName(pmodel[SeModelPrecLongterm]), iflag, false))
+	if pmodel[SeModelPrecLongterm] != pmodel[SeModelPrecShortterm] && iflag&(SeflgJplhor|SeflgJplhorApprox) == 0 {
+		fmt.Fprintf(&sd, "+ short-term model: %s\n", precessionModelName(pmodel[SeModelPrecShortterm]))
+	}
+	fmt.Fprintf(&sd, "Nutation: %s\n", jplhorModelName(nutationModelName(pmodel[SeModelNut]), iflag, true))
+	fmt.Fprintf(&sd, "Frame bias: %s\n", frameBiasModelName(pmodel[SeModelBias]))
+	fmt.Fprintf(&sd, "Sid. time: %s\n", sidtModelName(pmodel[SeModelSidt]))
+	// swetest的参数
+	sd.WriteString("swetest parameters:      D P P N B J J S\n")
+	fmt.Fprintf(&sd, "                    -amod%s", models)
+	fmt.Fprintf(&sd, " -tidacc%f", getTidAcc())
+	sd.WriteString("\n")
+	if !listAllModels {
+		fmt.Fprintf(&sd, "For list of all available astronomical models, add a '+' to the version string\n(swetest parameter -amod%s+ or -amod%s+)\n", samod, models)
+		return models, sd.String()
+	}
+	// 列出所有可用的模型
+	listModels := func(title string, n, dflt Int32, name func(Int32) string) {
+		sd.WriteString(title)
+		for i := Int32(0); i <= n; i++ {
+			if i == dflt {
+				continue
+			}
+			fmt.Fprintf(&sd, "  (%d)", i)
+			if i == 0 {
+				fmt.Fprintf(&sd, " (=%d)", dflt)
+			}
+			fmt.Fprintf(&sd, ": %s\n", name(i))
+		}
+	}
+	listModels("DELTA T MODELS (D)\n", SemodNdeltat, SemodDeltatDefault, deltatModelName)
+	listModels("PRECESSION MODELS (P P) (long-term/short-term)\n", SemodNprec, SemodPrecDefault, precessionModelName)
+	listModels("NUTATION MODELS (N)\n", SemodNnut, SemodNutDefault, nutationModelName)
+	listModels("FRAME BIAS MODELS (B)\n", SemodNbias, SemodBiasDefault, frameBiasModelName)
+	sd.WriteString("JPL HORIZONS MODELS (J) (with SEFLG_JPLEPH|SEFLG_JPLHOR).\n")
+	sd.WriteString("  IAU 1980 (Wahr) + daily corrections to dpsi/deps 1962-today.\n")
+	sd.WriteString("  (0 (=1): between 1799 and 1962, dpsi/deps of 20-jan-1962 are used.\n")
+	sd.WriteString("           For times beyond the dpsi/deps table, the last tabulated values are used.\n")
+	sd.WriteString("           Beyond 1799 and 2201, precession Owen 1990 is used..\n")
+	sd.WriteString("  Documentation in swephexp.h under 'methods of JPL Horizons'\n")
+	sd.WriteString("JPL HORIZONS APPROXIMATION (J) (with SEFLG_JPLEPH|SEFLG_JPLHORA)\n")
+	sd.WriteString("  Documentation in swephexp.h under 'methods of JPL Horizons'\n")
+	listModels("SIDEREAL TIME MODELS (S)\n", SemodNsidt, SemodSidtDefault, sidtModelName)
+	return models, sd.String()
+}
@@ -0,0 +1,191 @@
+// 地平坐标：黄道或赤道坐标与方位角、高度之间的转换，以及大气折射和地平俯角，
+// 同swe_azalt、swe_azalt_rev和swe_refrac_extended。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// 坐标转换的方向
+const (
+	SeEcl2hor = 0 // 黄道坐标转换为地平坐标
+	SeEqu2hor = 1 // 赤道坐标转换为地平坐标
+	SeHor2ecl = 0 // 地平坐标转换为黄道坐标
+	SeHor2equ = 1 // 地平坐标转换为赤道坐标
+)
+
+// 大气折射的方向
+const (
+	SeTrueToApp = 0 // 真高度转换为视高度
+	SeAppToTrue = 1 // 视高度转换为真高度
+)
+
+// lapseRate 大气温度递减率（K/m），用于计算地平俯角
+const lapseRate = 0.0065
+
+// defaultAtpress 估计海拔geoalt（米）处的气压（hPa），海平面为1013.25
+func defaultAtpress(geoalt Float64) Float64 {
+	return 1013.25 * math.Pow(1-0.0065*geoalt/288, 5.255)
+}
+
+// Azalt 将世界时tjdUt的黄道坐标（calcFlag为SeEcl2hor）或赤道坐标（SeEqu2hor）转换为地平坐标
+// geopos: 观测者的地理经度、纬度（度）和海拔（米）
+// atpress: 气压（hPa），为0时按海拔估计；attemp: 气温（摄氏度）
+// xin: 当日真黄道或真赤道的经度和纬度（度），如Calc的结果
+// 返回方位角（由南向西起算）、真高度和视高度（考虑大气折射）
+func Azalt(tjdUt Float64, calcFlag Int32, geopos [3]Float64, atpress, attemp Float64, xin [2]Float64) ([3]Float64, error) {
+	if calcFlag != SeEcl2hor && calcFlag != SeEqu2hor {
+		return [3]Float64{}, fmt.Errorf("无效的坐标转换方向: %d", calcFlag)
+	}
+	if err := initializeSwissEph(); err != nil {
+		return [3]Float64{}, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	xaz := azalt(tjdUt, calcFlag, geopos, atpress, attemp, xin[:])
+	SetSweData(swed)
+	return xaz, nil
+}
+
+// AzaltRev 将世界时tjdUt的方位角和视高度转换为黄道坐标（calcFlag为SeHor2ecl）或赤道坐标（SeHor2equ）
+// xin: 方位角（由南向西起算）和视高度（度），由气压atpress（hPa，为0时按海拔估计）和气温attemp
+// （摄氏度）去掉大气折射，是Azalt的逆运算，输入Azalt返回的视高度即还原其坐标。
+// 与swe_azalt_rev不同：C版没有气压和气温参数，总是输入真高度；这里atpress为负时xin[1]为真高度，
+// 结果同swe_azalt_rev。返回当日真黄道或真赤道的经度和纬度（度）
+func AzaltRev(tjdUt Float64, calcFlag Int32, geopos [3]Float64, atpress, attemp Float64, xin [2]Float64) ([2]Float64, error) {
+	if calcFlag != SeHor2ecl && calcFlag != SeHor2equ {
+		return [2]Float64{}, fmt.Errorf("无效的坐标转换方向: %d", calcFlag)
+	}
+	if err := initializeSwissEph(); err != nil {
+		return [2]Float64{}, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	if atpress >= 0 {
+		if atpress == 0 {
+			atpress = defaultAtpress(geopos[2])
+		}
+		xin[1], _ = refracExtended(xin[1], geopos[2], atpress, attemp, lapseRate, SeAppToTrue)
+	}
+	xout := azaltRev(tjdUt, calcFlag, geopos, xin[:])
+	SetSweData(swed)
+	return xout, nil
+}
+
+// azalt 将黄道或赤道坐标xin（度）转换为地平坐标
+// 返回方位角（由南向西起算）、真高度和视高度；atpress为0时按海拔估计气压
+func azalt(tjdUt Float64, calcFlag Int32, geopos [3]Float64, atpress, attemp Float64, xin []Float64) [3]Float64 {
+	var xaz [3]Float64
+	armc := degnorm(sidtime(tjdUt)*15 + geopos[0])
+	xra := []Float64{xin[0], xin[1], 1}
+	if calcFlag == SeEcl2hor {
+		x, _, _ := calc(tjdUt+calcDeltat(tjdUt, -1)/86400.0, SeEclNut, 0)
+		cotrans(xra, xra, -x[0])
+	}
+	mdd := degnorm(xra[0] - armc)
+	x := []Float64{degnorm(mdd - 90), xra[1], 1}
+	// 由东向北起算的方位角
+	cotrans(x, x, 90-geopos[1])
+	// 由南向西起算的方位角
+	x[0] = degnorm(x[0] + 90)
+	xaz[0] = 360 - x[0]
+	xaz[1] = x[1]
+	if atpress == 0 {
+		atpress = defaultAtpress(geopos[2])
+	}
+	xaz[2], _ = refracExtended(x[1], geopos[2], atpress, attemp, lapseRate, SeTrueToApp)
+	return xaz
+}
+
+// azaltRev 将方位角和真高度xin（度）转换为黄道或赤道坐标，返回经度和纬度
+func azaltRev(tjdUt Float64, calcFlag Int32, geopos [3]Float64, xin []Float64) [2]Float64 {
+	armc := degnorm(sidtime(tjdUt)*15 + geopos[0])
+	// 方位角由南向西起算，转换为由东向北起算
+	xaz := []Float64{degnorm(360 - xin[0] - 90), xin[1], 1}
+	// 赤道坐标
+	cotrans(xaz, xaz, geopos[1]-90)
+	xaz[0] = degnorm(xaz[0] + armc + 90)
+	xout := [2]Float64{xaz[0], xaz[1]}
+	// 黄道坐标
+	if calcFlag == SeHor2ecl {
+		x, _, _ := calc(tjdUt+calcDeltat(tjdUt, -1)/86400.0, SeEclNut, 0)
+		xecl := make([]Float64, 3)
+		cotrans(xaz, xecl, x[0])
+		xout = [2]Float64{xecl[0], xecl[1]}
+	}
+	return xout
+}
+
+// refracExtended 真高度与视高度的转换，考虑观测者的海拔geoalt（米）和地平俯角
+// inalt为相对于几何地平（垂直于重力方向的平面）的高度（度）。
+// 第二个返回值依次为真高度、视高度、折射和地平俯角；
+// 天体在地平以下时真高度和视高度都是输入值，折射为0
+func refracExtended(inalt, geoalt, atpress, attemp, lapseRate Float64, calcFlag Int32) (Float64, [4]Float64) {
+	var dret [4]Float64
+	dip := calcDip(geoalt, atpress, attemp, lapseRate)
+	if inalt > 90 {
+		inalt = 180 - inalt
+	}
+	if calcFlag == SeTrueToApp {
+		if inalt < -10 {
+			dret = [4]Float64{inalt, inalt, 0, dip}
+			return inalt, dret
+		}
+		// 迭代，以数值估计的导数做牛顿迭代
+		y := inalt
+		var d, d0, yy0 Float64
+		for i := 0; i < 5; i++ {
+			d = calcAstronomicalRefr(y, atpress, attemp)
+			n := y - yy0
+			yy0 = d - d0 - n
+			if n != 0 && yy0 != 0 {
+				n = y - n*(inalt+d-y)/yy0
+			} else {
+				// 第一次不能计算导数
+				n = inalt + d
+			}
+			yy0 = y
+			d0 = d
+			y = n
+		}
+		refr := d
+		if inalt+refr < dip {
+			dret = [4]Float64{inalt, inalt, 0, dip}
+			return inalt, dret
+		}
+		dret = [4]Float64{inalt, inalt + refr, refr, dip}
+		return inalt + refr, dret
+	}
+	refr := calcAstronomicalRefr(inalt, atpress, attemp)
+	trualt := inalt - refr
+	if inalt > dip {
+		dret = [4]Float64{trualt, inalt, refr, dip}
+	} else {
+		dret = [4]Float64{inalt, inalt, 0, dip}
+	}
+	// 视高度不能低于地平俯角，这时返回输入的视高度
+	if inalt >= dip {
+		return trualt, dret
+	}
+	return inalt, dret
+}
+
+// calcAstronomicalRefr 视高度inalt（度）的天文折射（度），Sinclair公式，
+// 视高度为负时比Bennett公式好
+func calcAstronomicalRefr(inalt, atpress, attemp Float64) Float64 {
+	var r Float64
+	// 以17.904…度为界使函数连续
+	if inalt > 17.904104638432 {
+		r = 0.97 / math.Tan(inalt*DegToRad)
+	} else {
+		r = (34.46 + 4.23*inalt + 0.004*inalt*inalt) / (1 + 0.505*inalt + 0.0845*inalt*inalt)
+	}
+	return ((atpress - 80) / 930 / (1 + 0.00008*(r+39)*(attemp-10)) * r) / 60.0
+}
+
+// calcDip 海拔geoalt（米）处的地平俯角（度，为负），A. Thom的公式，V. Reijs换算为公制
+func calcDip(geoalt, atpress, attemp, lapseRate Float64) Float64 {
+	krefr := (0.0342 + lapseRate) / (0.154 * 0.0238)
+	d := 1 - 1.8480*krefr*atpress/(273.15+attemp)/(273.15+attemp)
+	return -180.0 / math.Pi * math.Acos(1/(1+geoalt/EarthRadius)) * math.Sqrt(d)
+}
@@ -0,0 +1,89 @@
+// 儒略历和格里高利历的混合历法：可设定格里高利历的改革日期和年的编号方式，
+// 日期经Revjul反算检查，同swe_date_conversion。
+
+package ephgo
+
+import "fmt"
+
+// 常用的格里高利历改革日期：格里高利历第一天0时的儒略日
+const (
+	ReformJd1582 = 2299160.5 // 1582年10月15日，天主教国家（前一日为儒略历10月4日）
+	ReformJd1752 = 2361221.5 // 1752年9月14日，英国及其殖民地（前一日为儒略历9月2日）
+)
+
+// YearNumbering 年的编号方式
+type YearNumbering int
+
+const (
+	YearAstronomical YearNumbering = iota // 天文纪年：公元前1年为0年，公元前2年为-1年
+	YearHistorical                        // 历史纪年：公元前1年为-1年，没有0年
+)
+
+// Calendar 混合历法：改革日期以前为儒略历，以后为格里高利历
+// 零值可以直接使用，即1582年的改革和天文纪年
+type Calendar struct {
+	Reform Float64       // 格里高利历第一天0时的儒略日，0表示ReformJd1582
+	Years  YearNumbering // 年的编号方式
+}
+
+// reformJd 返回格里高利历第一天0时的儒略日
+func (c Calendar) reformJd() Float64 {
+	if c.Reform == 0 {
+		return ReformJd1582
+	}
+	return c.Reform
+}
+
+// astronomicalYear 将c的纪年换算为天文纪年
+func (c Calendar) astronomicalYear(year int) (int, error) {
+	if c.Years != YearHistorical {
+		return year, nil
+	}
+	if year == 0 {
+		return 0, fmt.Errorf("历史纪年没有0年")
+	}
+	if year < 0 {
+		return year + 1, nil
+	}
+	return year, nil
+}
+
+// Gregflag 返回儒略日tjd所在日期使用的历法：SeGregCal或SeJulCal
+func (c Calendar) Gregflag(tjd Float64) int {
+	if tjd >= c.reformJd() {
+		return SeGregCal
+	}
+	return SeJulCal
+}
+
+// Julday 计算c中的日期的儒略日，hour为世界时（小时）
+// 改革日期以前的日期按儒略历、以后的日期按格里高利历计算；
+// 不存在的日期（如2月31日、历史纪年的0年）以及因改革而跳过的日期返回错误
+func (c Calendar) Julday(year, month, day int, hour Float64) (Float64, error) {
+	y, err := c.astronomicalYear(year)
+	if err != nil {
+		return 0, err
+	}
+	reform := c.reformJd()
+	jdGreg, errGreg := DateConversion(y, month, day, 0, 'g')
+	if errGreg == nil && jdGreg >= reform {
+		return Julday(y, month, day, hour, SeGregCal), nil
+	}
+	jdJul, errJul := DateConversion(y, month, day, 0, 'j')
+	if errJul == nil && jdJul < reform {
+		return Julday(y, month, day, hour, SeJulCal), nil
+	}
+	if errGreg == nil && errJul == nil {
+		return 0, fmt.Errorf("日期%d-%d-%d因历法改革而跳过", year, month, day)
+	}
+	return 0, fmt.Errorf("无效日期: 年 = %d, 月 = %d, 日 = %d", year, month, day)
+}
+
+// Revjul 由儒略日tjd求c中的日期，年按c的编号方式
+func (c Calendar) Revjul(tjd Float64) (year, month, day int, jut Float64) {
+	year, month, day, jut = Revjul(tjd, c.Gregflag(tjd))
+	if c.Years == YearHistorical && year <= 0 {
+		year--
+	}
+	return
+}
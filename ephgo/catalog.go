@@ -0,0 +1,324 @@
+// 恒星星表：恒星函数按优先顺序查找的星表接口，以及由CSV文件（如Hipparcos或Gaia DR3的摘录）
+// 读取的星表。sefstars.txt本身也是一个星表，未设置星表时只使用它。
+
+package ephgo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StarCatalog 恒星星表
+// FindStar按搜索名sstar查找恒星，sstar已去掉空格，逗号前的传统名已转为小写，写法见Fixstar2。
+// 返回的FixedStar与恒星文件的记录单位相同：赤经赤纬（弧度）、自行（弧度/世纪，赤经自行不乘cos赤纬）、
+// 视差（弧度）、径向速度（天文单位/世纪），Epoch为0表示ICRS，2000表示FK5，1950表示FK4；
+// 可用NewFixedStar由星表常用的单位换算
+type StarCatalog interface {
+	FindStar(sstar string) (FixedStar, error)
+}
+
+// sefstarsCatalog 星历路径中的恒星文件sefstars.txt
+type sefstarsCatalog struct{}
+
+// FindStar 在恒星文件中查找恒星，第一次调用时读取文件
+func (sefstarsCatalog) FindStar(sstar string) (FixedStar, error) {
+	if err := loadFixstars(); err != nil {
+		return FixedStar{}, err
+	}
+	return searchStarInList(sstar)
+}
+
+// SefstarsCatalog 返回星历路径中的恒星文件sefstars.txt（或fixstars.cat）作为星表，
+// 用于在SetStarCatalogs中确定它的优先顺序
+func SefstarsCatalog() StarCatalog {
+	return sefstarsCatalog{}
+}
+
+// SetStarCatalogs 设置恒星函数查找的星表，按参数顺序查找，使用第一个找到的记录
+// 不含SefstarsCatalog()时不再查找sefstars.txt；不带参数时恢复为只使用sefstars.txt。
+// Fixstar2、Fixstar2UT和Fixstar2Mag对所有恒星（包括Spica等）都按此顺序查找，
+// 星表中都找不到时才使用内置的恒星记录（Spica、Revati、Pushya、Mula、SgrA*、GP1958和GPol）；
+// 基于恒星的岁差（如SeSidmTrueCitra）只使用内置的恒星记录，不受星表影响
+func SetStarCatalogs(cats ...StarCatalog) {
+	swed := GetSweData()
+	swed.StarCatalogs = append([]StarCatalog(nil), cats...)
+	SetSweData(swed)
+}
+
+// findStar 按优先顺序在星表中查找搜索名sstar
+// 只有一个星表时返回它的错误，否则返回未找到
+func findStar(sstar string) (FixedStar, error) {
+	cats := swed.StarCatalogs
+	if len(cats) == 0 {
+		cats = []StarCatalog{sefstarsCatalog{}}
+	}
+	var err error
+	for _, cat := range cats {
+		var stardata FixedStar
+		if stardata, err = cat.FindStar(sstar); err == nil {
+			return stardata, nil
+		}
+	}
+	if len(cats) > 1 {
+		return FixedStar{}, fmt.Errorf("在%d个星表中都未找到恒星%s", len(cats), sstar)
+	}
+	return FixedStar{}, err
+}
+
+// NewFixedStar 由星表常用的单位生成恒星记录，位置和运动已换算到J2000.0历元（ICRS）
+// ra, dec: 历元epoch的ICRS赤经和赤纬（度）
+// epoch: 位置的历元（儒略年，如Hipparcos为1991.25，Gaia DR3为2016.0）
+// pmra, pmdec: 自行（毫角秒/年），赤经自行已乘cos赤纬，与Hipparcos和Gaia相同，位于天极时不用
+// parallax: 视差（毫角秒），不大于0时视为未知，恒星按极远处理
+// rv: 径向速度（km/s），mag: 星等
+// 历元不是2000.0时按空间直线运动换算，与计算位置时的运动模型一致
+func NewFixedStar(name, designation string, ra, dec, epoch, pmra, pmdec, parallax, rv, mag Float64) FixedStar {
+	stardata := FixedStar{
+		Starname:  name,
+		Starbayer: designation,
+		Starno:    designation,
+		Ra:        ra * DegToRad,
+		De:        dec * DegToRad,
+		Ramot:     pmra / 36000.0 * DegToRad,
+		Demot:     pmdec / 36000.0 * DegToRad,
+		Radvel:    rv * kmSToAuCty,
+		Mag:       mag,
+	}
+	if parallax > 0 {
+		stardata.Parall = parallax / 1000.0 / 3600.0 * DegToRad
+	}
+	// 星表给出的赤经自行为大圆弧长；天极处赤经自行没有意义，取0
+	if math.Abs(dec) >= 90 {
+		stardata.Ramot = 0
+	} else {
+		stardata.Ramot /= math.Cos(stardata.De)
+	}
+	if epoch == 2000 {
+		return stardata
+	}
+	// 换算到J2000.0：直角坐标的位置加上速度乘以时间
+	var x [6]Float64
+	x[0] = stardata.Ra
+	x[1] = stardata.De
+	if stardata.Parall == 0 {
+		x[2] = 1000000000
+	} else {
+		x[2] = 1.0 / (stardata.Parall * RadToDeg * 3600) * ParsecToAunit
+	}
+	x[3] = stardata.Ramot / 36525.0
+	x[4] = stardata.Demot / 36525.0
+	x[5] = stardata.Radvel / 36525.0
+	polcartSp(x[:], x[:])
+	dt := (2000 - epoch) * 365.25
+	for i := 0; i <= 2; i++ {
+		x[i] += dt * x[i+3]
+	}
+	cartpolSp(x[:], x[:])
+	stardata.Ra = x[0]
+	stardata.De = x[1]
+	stardata.Ramot = x[3] * 36525.0
+	stardata.Demot = x[4] * 36525.0
+	stardata.Radvel = x[5] * 36525.0
+	if stardata.Parall != 0 {
+		stardata.Parall = ParsecToAunit / (x[2] * RadToDeg * 3600)
+	}
+	return stardata
+}
+
+// csvStarColumns CSV星表的列名及其别名（不区分大小写），别名为Gaia Archive导出的列名
+var csvStarColumns = map[string][]string{
+	"name":     {"name"},
+	"id":       {"id", "source_id"},
+	"ra":       {"ra"},
+	"dec":      {"dec"},
+	"epoch":    {"epoch", "ref_epoch"},
+	"pmra":     {"pmra"},
+	"pmdec":    {"pmdec"},
+	"parallax": {"parallax"},
+	"rv":       {"rv", "radial_velocity"},
+	"mag":      {"mag", "phot_g_mean_mag"},
+}
+
+// CSVStarCatalog 由CSV文件读取的星表，见ReadCSVStarCatalog
+type CSVStarCatalog struct {
+	stars []FixedStar      // 按文件顺序，序号即下标加1
+	index map[string]int   // 传统名和","加编号的搜索关键字到stars下标的索引
+	names []fixstarNameKey // 按关键字排序的传统名，用于前缀查找
+}
+
+// fixstarNameKey 传统名的搜索关键字和记录下标
+type fixstarNameKey struct {
+	skey string
+	i    int
+}
+
+// LoadCSVStarCatalog 读取CSV星表文件path，格式见ReadCSVStarCatalog
+func LoadCSVStarCatalog(path string, epoch Float64) (*CSVStarCatalog, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开星表文件失败: %v", err)
+	}
+	defer fp.Close()
+	cat, err := ReadCSVStarCatalog(fp, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("星表文件%s: %v", path, err)
+	}
+	return cat, nil
+}
+
+// ReadCSVStarCatalog 读取CSV格式的星表
+// 第一行为列名（不区分大小写，顺序任意，不认识的列忽略），以'#'开头的行为注释：
+//
+//	name      传统名，可以为空
+//	id        编号，如"HIP 32349"或Gaia的source_id，作为拜耳名的位置用于查找（",HIP32349"）
+//	ra, dec   ICRS赤经和赤纬（度），必须有
+//	epoch     位置的历元（儒略年），没有此列或为空时使用参数epoch
+//	pmra      赤经自行（毫角秒/年，已乘cos赤纬）
+//	pmdec     赤纬自行（毫角秒/年）
+//	parallax  视差（毫角秒），不大于0时视为未知
+//	rv        径向速度（km/s）
+//	mag       星等
+//
+// 可选的数值列为空时取0。Gaia Archive导出的source_id、ref_epoch、radial_velocity和
+// phot_g_mean_mag可直接使用。恒星按名称、",编号"、文件中的序号和以'%'结尾的名称前缀查找
+func ReadCSVStarCatalog(r io.Reader, epoch Float64) (*CSVStarCatalog, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("读取列名失败: %v", err)
+	}
+	cols := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for col, aliases := range csvStarColumns {
+			for _, a := range aliases {
+				if h == a {
+					cols[col] = i
+				}
+			}
+		}
+	}
+	if _, ok := cols["ra"]; !ok {
+		return nil, fmt.Errorf("缺少ra列")
+	}
+	if _, ok := cols["dec"]; !ok {
+		return nil, fmt.Errorf("缺少dec列")
+	}
+	cat := &CSVStarCatalog{index: make(map[string]int)}
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(col string) string {
+			if i, ok := cols[col]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		// number 读取数值列，空白时为def
+		number := func(col string, def Float64) (Float64, error) {
+			s := field(col)
+			if s == "" {
+				return def, nil
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, fmt.Errorf("第%d行%s列的数值无效: %s", line, col, s)
+			}
+			return v, nil
+		}
+		if field("ra") == "" || field("dec") == "" {
+			return nil, fmt.Errorf("第%d行缺少赤经或赤纬", line)
+		}
+		var v [8]Float64
+		for i, col := range []string{"ra", "dec", "epoch", "pmra", "pmdec", "parallax", "rv", "mag"} {
+			def := Float64(0)
+			if col == "epoch" {
+				def = epoch
+			}
+			if v[i], err = number(col, def); err != nil {
+				return nil, err
+			}
+		}
+		if math.Abs(v[1]) > 90 {
+			return nil, fmt.Errorf("第%d行的赤纬超出范围: %v", line, v[1])
+		}
+		cat.add(NewFixedStar(field("name"), field("id"), v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]))
+	}
+	sort.SliceStable(cat.names, func(i, j int) bool { return cat.names[i].skey < cat.names[j].skey })
+	return cat, nil
+}
+
+// add 添加一条记录并建立索引，同名或同编号的记录以第一条为准
+func (cat *CSVStarCatalog) add(stardata FixedStar) {
+	i := len(cat.stars)
+	if stardata.Starname != "" {
+		stardata.Skey = strings.ToLower(strings.ReplaceAll(stardata.Starname, " ", ""))
+		if _, ok := cat.index[stardata.Skey]; !ok {
+			cat.index[stardata.Skey] = i
+		}
+		cat.names = append(cat.names, fixstarNameKey{stardata.Skey, i})
+	}
+	if stardata.Starbayer != "" {
+		key := "," + strings.ReplaceAll(stardata.Starbayer, " ", "")
+		if _, ok := cat.index[key]; !ok {
+			cat.index[key] = i
+		}
+	}
+	cat.stars = append(cat.stars, stardata)
+}
+
+// Len 星表中的恒星数量
+func (cat *CSVStarCatalog) Len() int {
+	return len(cat.stars)
+}
+
+// FindStar 按搜索名查找恒星，实现StarCatalog
+// 序号是文件中的顺序；"名称,编号"按编号查找；名称前缀返回按字母顺序的第一个
+func (cat *CSVStarCatalog) FindStar(sstar string) (FixedStar, error) {
+	if sstar == "" {
+		return FixedStar{}, fmt.Errorf("恒星名称为空")
+	}
+	// 序号
+	if sstar[0] >= '0' && sstar[0] <= '9' {
+		starNr := int(atofPrefix(sstar))
+		if starNr < 1 || starNr > len(cat.stars) {
+			return FixedStar{}, fmt.Errorf("恒星序号%d超出范围1至%d", starNr, len(cat.stars))
+		}
+		return cat.stars[starNr-1], nil
+	}
+	if i := strings.IndexByte(sstar, ','); i >= 0 {
+		// 编号
+		sstar = sstar[i:]
+	} else if i := strings.IndexByte(sstar, '%'); i >= 0 {
+		// 名称前缀
+		if i != len(sstar)-1 {
+			return FixedStar{}, fmt.Errorf("无效的恒星搜索名%s", sstar)
+		}
+		prefix := sstar[:i]
+		k := sort.Search(len(cat.names), func(k int) bool { return cat.names[k].skey >= prefix })
+		if k < len(cat.names) && strings.HasPrefix(cat.names[k].skey, prefix) {
+			return cat.stars[cat.names[k].i], nil
+		}
+		return FixedStar{}, fmt.Errorf("没有与%s匹配的恒星", sstar)
+	}
+	i, ok := cat.index[sstar]
+	if !ok {
+		return FixedStar{}, fmt.Errorf("未找到恒星%s", sstar)
+	}
+	return cat.stars[i], nil
+}
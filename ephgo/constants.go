@@ -148,8 +148,187 @@ const (
 	SeflgTropical   = 0     // 回归坐标（默认）
 	SeflgSidereal   = 65536 // 恒星坐标
 	SeflgIcrs       = 131072 // ICRS参考系
+	SeflgDpsideps1980 = 262144 // 重现JPL Horizons 1962年至今的结果，使用EOP文件中的dpsi和deps
+	SeflgJplhor       = SeflgDpsideps1980
+	SeflgJplhorApprox = 524288 // 近似JPL Horizons 1962年至今的结果
 )
 
+// 恒星黄道模式（岁差，ayanamsa）
+const (
+	SeSidmFaganBradley       = 0
+	SeSidmLahiri             = 1
+	SeSidmDeluce             = 2
+	SeSidmRaman              = 3
+	SeSidmUshashashi         = 4
+	SeSidmKrishnamurti       = 5
+	SeSidmDjwhalKhul         = 6
+	SeSidmYukteshwar         = 7
+	SeSidmJnBhasin           = 8
+	SeSidmBabylKugler1       = 9
+	SeSidmBabylKugler2       = 10
+	SeSidmBabylKugler3       = 11
+	SeSidmBabylHuber         = 12
+	SeSidmBabylEtpsc         = 13
+	SeSidmAldebaran15Tau     = 14
+	SeSidmHipparchos         = 15
+	SeSidmSassanian          = 16
+	SeSidmGalcent0Sag        = 17
+	SeSidmJ2000              = 18
+	SeSidmJ1900              = 19
+	SeSidmB1950              = 20
+	SeSidmSuryasiddhanta     = 21
+	SeSidmSuryasiddhantaMsun = 22
+	SeSidmAryabhata          = 23
+	SeSidmAryabhataMsun      = 24
+	SeSidmSsRevati           = 25
+	SeSidmSsCitra            = 26
+	SeSidmTrueCitra          = 27
+	SeSidmTrueRevati         = 28
+	SeSidmTruePushya         = 29
+	SeSidmGalcentRgilbrand   = 30
+	SeSidmGalequIau1958      = 31
+	SeSidmGalequTrue         = 32
+	SeSidmGalequMula         = 33
+	SeSidmGalalignMardyks    = 34
+	SeSidmTrueMula           = 35
+	SeSidmGalcentMulaWilhelm = 36
+	SeSidmAryabhata522       = 37
+	SeSidmBabylBritton       = 38
+	SeSidmTrueSheoran        = 39
+	SeSidmGalcentCochrane    = 40
+	SeSidmGalequFiorenza     = 41
+	SeSidmValensMoon         = 42
+	SeSidmLahiri1940         = 43
+	SeSidmLahiriVp285        = 44
+	SeSidmKrishnamurtiVp291  = 45
+	SeSidmLahiriIcrc         = 46
+	SeSidmUser               = 255 // 用户自定义岁差，t0为TT
+
+	SeNsidmPredef = 47 // 预定义的岁差个数
+)
+
+// 恒星黄道模式的附加位，与模式编号按位或
+const (
+	SeSidbits            = 256
+	SeSidbitEclT0        = 256  // 投影到t0时刻的黄道
+	SeSidbitSsyPlane     = 512  // 投影到太阳系不变平面
+	SeSidbitUserUt       = 1024 // 用户自定义岁差的t0为UT
+	SeSidbitEclDate      = 2048 // 在当天黄道上量度岁差
+	SeSidbitNoPrecOffset = 4096 // 不按原始岁差模型修正岁差
+	SeSidbitPrecOrig     = 8192 // 使用岁差定义时的原始岁差模型
+)
+
+// 天体模型的类别（swed.AstroModels的下标）
+const (
+	SeModelDeltat        = 0
+	SeModelPrecLongterm  = 1
+	SeModelPrecShortterm = 2
+	SeModelNut           = 3
+	SeModelBias          = 4
+	SeModelJplhorMode    = 5
+	SeModelJplhoraMode   = 6
+	SeModelSidt          = 7
+)
+
+// 岁差模型
+const (
+	SemodNprec             = 11
+	SemodPrecIau1976       = 1
+	SemodPrecLaskar1986    = 2
+	SemodPrecWillEpsLask   = 3
+	SemodPrecWilliams1994  = 4
+	SemodPrecSimon1994     = 5
+	SemodPrecIau2000       = 6
+	SemodPrecBretagnon2003 = 7
+	SemodPrecIau2006       = 8
+	SemodPrecVondrak2011   = 9
+	SemodPrecOwen1990      = 10
+	SemodPrecNewcomb       = 11
+	SemodPrecDefault       = SemodPrecVondrak2011
+	SemodPrecDefaultShort  = SemodPrecVondrak2011
+)
+
+// 章动模型
+const (
+	SemodNnut           = 5
+	SemodNutIau1980     = 1
+	SemodNutIauCorr1987 = 2 // Herring（1987）对IAU 1980的修正
+	SemodNutIau2000A    = 3 // 非常耗时
+	SemodNutIau2000B    = 4 // 快速，精度为毫角秒
+	SemodNutWoolard     = 5
+	SemodNutDefault     = SemodNutIau2000B
+)
+
+// 恒星时模型
+const (
+	SemodNsidt            = 4
+	SemodSidtIau1976      = 1
+	SemodSidtIau2006      = 2
+	SemodSidtIersConv2010 = 3
+	SemodSidtLongterm     = 4
+	SemodSidtDefault      = SemodSidtLongterm
+)
+
+// 参考架偏差模型
+const (
+	SemodNbias       = 3
+	SemodBiasNone    = 1 // 忽略参考架偏差
+	SemodBiasIau2000 = 2 // IAU 2000参考架偏差矩阵
+	SemodBiasIau2006 = 3 // IAU 2006参考架偏差矩阵
+	SemodBiasDefault = SemodBiasIau2006
+)
+
+// JPL Horizons模式的方法
+const (
+	SemodNjplhor             = 2
+	SemodJplhorLongAgreement = 1 // 使用EOP文件中逐日的dpsi和deps
+	SemodJplhorDefault       = SemodJplhorLongAgreement
+)
+
+// 近似JPL Horizons模式的方法
+const (
+	SemodNjplhora       = 3
+	SemodJplhora1       = 1
+	SemodJplhora2       = 2
+	SemodJplhora3       = 3
+	SemodJplhoraDefault = SemodJplhora3
+)
+
+// ΔT模型
+const (
+	SemodNdeltat                      = 5
+	SemodDeltatStephensonMorrison1984 = 1
+	SemodDeltatStephenson1997         = 2
+	SemodDeltatStephensonMorrison2004 = 3
+	SemodDeltatEspenakMeeus2006       = 4
+	SemodDeltatStephensonEtc2016      = 5
+	SemodDeltatDefault                = SemodDeltatStephensonEtc2016
+)
+
+// 月球潮汐加速度（角秒/世纪²），与各JPL星历一致
+const (
+	SeTidalDe200          = -23.8946
+	SeTidalDe403          = -25.580
+	SeTidalDe404          = -25.580
+	SeTidalDe405          = -25.826
+	SeTidalDe406          = -25.826
+	SeTidalDe421          = -25.85
+	SeTidalDe422          = -25.85
+	SeTidalDe430          = -25.82
+	SeTidalDe431          = -25.80
+	SeTidalDe441          = -25.936
+	SeTidal26             = -26.0
+	SeTidalStephenson2016 = -25.85
+	SeTidalDefault        = SeTidalDe431
+	SeTidalAutomatic      = 999999
+	SeTidalMoseph         = SeTidalDe404
+	SeTidalSwieph         = SeTidalDefault
+	SeTidalJpleph         = SeTidalDefault
+)
+
+// SeDeltatAutomatic 传给SetDeltaTUserdef时取消用户定义的ΔT
+const SeDeltatAutomatic = -1e-10
+
 // 日历类型
 const (
 	SeJulCal  = 0 // 儒略历
@@ -192,6 +371,50 @@ const (
 	SeiNmodels        = 8
 )
 
+// 星历文件编号
+const (
+	SeiFilePlanet  = 0
+	SeiFileMoon    = 1
+	SeiFileMainAst = 2
+	SeiFileAnyAst  = 3
+	SeiFileFixstar = 4
+	SeiFilePlmoon  = 5
+)
+
+// 星历文件的字节序
+const (
+	SeiFileTestEndian = 0x616263 // "abc"
+	SeiFileBigendian  = 0
+	SeiFileNoreord    = 0
+	SeiFileLitendian  = 1
+	SeiFileReord      = 2
+)
+
+// 星历文件中的行星标志
+const (
+	SeiFlgHelio   = 1 // 日心（否则为质心）
+	SeiFlgRotate  = 2 // 系数需旋转回J2000赤道
+	SeiFlgEllipse = 4 // 使用参考椭圆
+	SeiFlgEmbhel  = 8 // 文件中为日心地月质心，质心太阳需另行计算
+)
+
+// 小行星和行星卫星编号偏移量
+const (
+	SePlmoonOffset = 9000
+	SeAstOffset    = 10000
+)
+
+// SeDeNumber 默认的JPL星历DE编号
+const SeDeNumber = 431
+
+// 默认的JPL星历文件名
+const (
+	SeFnameDe406 = "de406.eph"
+	SeFnameDe431 = "de431.eph"
+	SeFnameDft   = SeFnameDe431
+	SeFnameDft2  = SeFnameDe406
+)
+
 // JPL天体索引
 const (
 	JMercury = 0
@@ -1,15 +1,19 @@
 package ephgo
 
 import (
+	"bufio"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // DateConversion 日期转换函数
 // y: 年, m: 月, d: 日, utime: 世界时（小时，十进制）
 // c: 历法类型 'g'(格里高利历) 或 'j'(儒略历)
-// 返回格林威治标准时（天）
+// 返回格林威治标准时（天）；日期不存在（如2月31日）时返回错误，
+// 同swe_date_conversion，此时仍返回按输入计算的儒略日
 func DateConversion(y, m, d int, utime Float64, c byte) (Float64, error) {
 	var gregflag int
 	if c == 'g' || c == 'G' {
@@ -21,165 +25,452 @@ func DateConversion(y, m, d int, utime Float64, c byte) (Float64, error) {
 	}
 	
 	jd := Julday(y, m, d, utime, gregflag)
+	ryear, rmon, rday, _ := Revjul(jd, gregflag)
+	if ryear != y || rmon != m || rday != d {
+		return jd, fmt.Errorf("无效日期: 年 = %d, 月 = %d, 日 = %d", y, m, d)
+	}
 	return jd, nil
 }
 
 // Julday 计算儒略日
-// year: 年, month: 月, day: 日, hour: 小时（十进制）
+// year: 年（天文纪年，公元前1年为0年，公元前2年为-1年）, month: 月, day: 日, hour: 小时（十进制）
 // gregflag: 历法标志（SeGregCal或SeJulCal）
+// 不检查日期是否有效，例如2月30日即3月1日或2日；需要检查时使用DateConversion或Calendar
 func Julday(year, month, day int, hour Float64, gregflag int) Float64 {
-	var jd Float64
-	var a, b int
-	
-	if month <= 2 {
-		year--
-		month += 12
+	u := Float64(year)
+	if month < 3 {
+		u--
 	}
-	
-	if gregflag == SeGregCal {
-		a = year / 100
-		b = 2 - a + a/4
-	} else {
-		b = 0
+	u0 := u + 4712.0
+	u1 := Float64(month) + 1.0
+	if u1 < 4 {
+		u1 += 12.0
 	}
-	
-	if year < 0 {
-		jd = math.Floor(365.25*Float64(year-1)) + math.Floor(30.6001*Float64(month+1)) + Float64(day) + Float64(b) + 1720994.5
-	} else {
-		jd = math.Floor(365.25*Float64(year)) + math.Floor(30.6001*Float64(month+1)) + Float64(day) + Float64(b) + 1720994.5
+	jd := math.Floor(u0*365.25) + math.Floor(30.6*u1+0.000001) + Float64(day) + hour/24.0 - 63.5
+	if gregflag == SeGregCal {
+		u2 := math.Floor(math.Abs(u)/100) - math.Floor(math.Abs(u)/400)
+		if u < 0.0 {
+			u2 = -u2
+		}
+		jd = jd - u2 + 2
+		if u < 0.0 && u/100 == math.Floor(u/100) && u/400 != math.Floor(u/400) {
+			jd -= 1
+		}
 	}
-	
-	jd += hour / 24.0
 	return jd
 }
 
 // Revjul 从儒略日反推历法日期
 // jd: 儒略日
 // gregflag: 历法标志
-// 返回：年、月、日、世界时（小时）
+// 返回：年（天文纪年）、月、日、世界时（小时）
 func Revjul(jd Float64, gregflag int) (year, month, day int, jut Float64) {
-	var a, b, c, d, e int
-	var f Float64
-	
-	jd += 0.5
-	z := int(jd)
-	f = jd - Float64(z)
-	
+	u0 := jd + 32082.5
 	if gregflag == SeGregCal {
-		if z >= 2299161 {
-			alpha := int((Float64(z) - 1867216.25) / 36524.25)
-			a = z + 1 + alpha - alpha/4
-		} else {
-			a = z
+		u1 := u0 + math.Floor(u0/36525.0) - math.Floor(u0/146100.0) - 38.0
+		if jd >= 1830691.5 {
+			u1 += 1
 		}
-	} else {
-		a = z
-	}
-	
-	b = a + 1524
-	c = int((Float64(b) - 122.1) / 365.25)
-	d = int(365.25 * Float64(c))
-	e = int(Float64(b-d) / 30.6001)
-	
-	day = b - d - int(30.6001*Float64(e))
-	
-	if e < 14 {
-		month = e - 1
-	} else {
-		month = e - 13
+		u0 = u0 + math.Floor(u1/36525.0) - math.Floor(u1/146100.0) - 38.0
 	}
-	
-	if month > 2 {
-		year = c - 4716
-	} else {
-		year = c - 4715
+	u2 := math.Floor(u0 + 123.0)
+	u3 := math.Floor((u2 - 122.2) / 365.25)
+	u4 := math.Floor((u2 - math.Floor(365.25*u3)) / 30.6001)
+	month = int(u4 - 1.0)
+	if month > 12 {
+		month -= 12
 	}
-	
-	jut = f * 24.0
+	day = int(u2 - math.Floor(365.25*u3) - math.Floor(30.6001*u4))
+	year = int(u3 + math.Floor((u4-2.0)/12.0) - 4800)
+	jut = (jd - math.Floor(jd+0.5) + 0.5) * 24.0
 	return
 }
 
+// JuldaySplit 同Julday，返回两部分的儒略日：Day为当天0时，Frac为hour/24
+func JuldaySplit(year, month, day int, hour Float64, gregflag int) JulianDay {
+	return JulianDayOf(Julday(year, month, day, 0, gregflag), hour/24.0)
+}
+
+// RevjulSplit 同Revjul，输入两部分的儒略日，世界时（小时）不损失精度
+func RevjulSplit(tjd JulianDay, gregflag int) (year, month, day int, jut Float64) {
+	tjd = JulianDayOf(tjd.Day, tjd.Frac)
+	year, month, day, _ = Revjul(tjd.Day, gregflag)
+	jut = tjd.Frac * 24.0
+	return
+}
+
+// 闰秒插入在以下各日的末尾（格式yyyymmdd）
+var leapSecondsTab = []int{
+	19720630,
+	19721231,
+	19731231,
+	19741231,
+	19751231,
+	19761231,
+	19771231,
+	19781231,
+	19791231,
+	19810630,
+	19820630,
+	19830630,
+	19850630,
+	19871231,
+	19891231,
+	19901231,
+	19920630,
+	19930630,
+	19940630,
+	19951231,
+	19970630,
+	19981231,
+	20051231,
+	20081231,
+	20120630,
+	20150630,
+	20161231,
+}
+
+const (
+	nleapSecondsSpace = 100       // 闰秒表的最大长度
+	j1972             = 2441317.5 // 1972年1月1日
+	nleapInit         = 10        // 1972年时TAI与UTC之差（秒）
+)
+
+// leapSeconds 实际使用的闰秒表，由leapSecondsTab和seleapsec.txt中的日期组成
+var leapSeconds []int
+
+// initLeapsecDone 是否已读取seleapsec.txt
+var initLeapsecDone bool
+
+// initLeapsec 初始化闰秒表，并从星历路径中的seleapsec.txt读取新增的闰秒日期
+// 文件每行一个日期（yyyymmdd），以#开头的行为注释；文件不存在时不报错
+func initLeapsec() []int {
+	if !initLeapsecDone {
+		initLeapsecDone = true
+		leapSeconds = append([]int(nil), leapSecondsTab...)
+		ndatLast := leapSecondsTab[len(leapSecondsTab)-1]
+		fp, err := sweFopen(-1, "seleapsec.txt", currentEphePath())
+		if err != nil {
+			return leapSeconds
+		}
+		defer fp.Close()
+		scanner := bufio.NewScanner(fp)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			ndat, err := strconv.Atoi(fields[0])
+			if err != nil || ndat <= ndatLast {
+				continue
+			}
+			// 表的空间有限，超出时忽略
+			if len(leapSeconds) >= nleapSecondsSpace {
+				break
+			}
+			leapSeconds = append(leapSeconds, ndat)
+		}
+	}
+	return leapSeconds
+}
+
 // UtcToJd 将UTC时间转换为儒略日
 // iyear, imonth, iday: 年月日
 // ihour, imin: 时分
-// dsec: 秒（十进制）
+// dsec: 秒（十进制），闰秒日的23:59可以为60
 // gregflag: 历法标志
-// 返回：ET儒略日, UT1儒略日, 错误信息
+// 返回：ET（TT）儒略日, UT1儒略日, 错误信息
+// 1972年以前UTC尚未与原子时相联系，输入时间作为UT1处理；
+// 1972年以后由闰秒表求TAI和TT，再由ΔT求UT1；
+// 若ΔT与闰秒数不符超过1秒（闰秒表未及时更新的将来日期），输入时间作为UT1处理
 func UtcToJd(iyear, imonth, iday, ihour, imin Int32, dsec Float64, gregflag Int32) (et, ut1 Float64, err error) {
-	if iyear < -4713 || iyear > 5000000 {
-		return 0, 0, fmt.Errorf("年份超出有效范围: %d", iyear)
+	// 检查日期是否有效
+	tjdUt1 := Julday(int(iyear), int(imonth), int(iday), 0, int(gregflag))
+	iyear2, imonth2, iday2, _ := Revjul(tjdUt1, int(gregflag))
+	if int(iyear) != iyear2 || int(imonth) != imonth2 || int(iday) != iday2 {
+		return 0, 0, fmt.Errorf("无效日期: 年 = %d, 月 = %d, 日 = %d", iyear, imonth, iday)
 	}
+	if ihour < 0 || ihour > 23 ||
+		imin < 0 || imin > 59 ||
+		dsec < 0 || dsec >= 61 ||
+		(dsec >= 60 && (imin < 59 || ihour < 23 || tjdUt1 < j1972)) {
+		return 0, 0, fmt.Errorf("无效时间: %d:%d:%.2f", ihour, imin, dsec)
+	}
+	dhour := Float64(ihour) + Float64(imin)/60.0 + dsec/3600.0
 	
-	if imonth < 1 || imonth > 12 {
-		return 0, 0, fmt.Errorf("无效月份: %d", imonth)
+	// 1972年以前，输入时间作为UT1
+	if tjdUt1 < j1972 {
+		ut1 = Julday(int(iyear), int(imonth), int(iday), dhour, int(gregflag))
+		et = ut1 + DeltatEx(ut1, -1)/86400.0
+		return et, ut1, nil
 	}
 	
-	if iday < 1 || iday > 31 {
-		return 0, 0, fmt.Errorf("无效日期: %d", iday)
+	// 儒略历日期换算为格里高利历
+	if gregflag == SeJulCal {
+		y, m, d, _ := Revjul(tjdUt1, SeGregCal)
+		iyear, imonth, iday = Int32(y), Int32(m), Int32(d)
 	}
 	
-	if ihour < 0 || ihour >= 24 {
-		return 0, 0, fmt.Errorf("无效小时: %d", ihour)
+	// 1972年以来的闰秒数
+	leaps := initLeapsec()
+	nleap := nleapInit
+	ndat := int(iyear)*10000 + int(imonth)*100 + int(iday)
+	for _, l := range leaps {
+		if ndat <= l {
+			break
+		}
+		nleap++
 	}
 	
-	if imin < 0 || imin >= 60 {
-		return 0, 0, fmt.Errorf("无效分钟: %d", imin)
+	// 将来的日期：闰秒表可能未及时更新，若ΔT - nleap - 32.184 >= 1，
+	// 输入时间作为UT1处理
+	d := DeltatEx(tjdUt1, -1)
+	if d-Float64(nleap)-32.184 >= 1.0 {
+		ut1 = tjdUt1 + dhour/24.0
+		et = ut1 + DeltatEx(ut1, -1)/86400.0
+		return et, ut1, nil
 	}
 	
-	if dsec < 0 || dsec >= 60 {
-		return 0, 0, fmt.Errorf("无效秒数: %f", dsec)
+	// 秒数为60时检查当天是否有闰秒
+	if dsec >= 60 {
+		isLeap := false
+		for _, l := range leaps {
+			if ndat == l {
+				isLeap = true
+				break
+			}
+		}
+		if !isLeap {
+			return 0, 0, fmt.Errorf("无效时间（没有闰秒）: %d:%d:%.2f", ihour, imin, dsec)
+		}
 	}
 	
-	// 计算总小时数
-	dhour := Float64(ihour) + Float64(imin)/60.0 + dsec/3600.0
+	// UTC转换为TT和UT1
+	// 1972年1月1日以来的SI秒数（不计闰秒）
+	days := tjdUt1 - j1972
+	days += Float64(ihour)/24.0 + Float64(imin)/1440.0 + dsec/86400.0
+	tjdEt1972 := j1972 + (32.184+nleapInit)/86400.0
+	et = tjdEt1972 + days + Float64(nleap-nleapInit)/86400.0
+	dt := DeltatEx(et, -1) / 86400.0
+	ut1 = et - DeltatEx(et-dt, -1)/86400.0
+	ut1 = et - DeltatEx(ut1, -1)/86400.0
 	
-	// 计算UT1儒略日
-	ut1 = Julday(int(iyear), int(imonth), int(iday), dhour, int(gregflag))
+	return et, ut1, nil
+}
+
+// UtcToJdSplit 同UtcToJd，返回两部分的儒略日，秒的小数部分不损失精度
+func UtcToJdSplit(iyear, imonth, iday, ihour, imin Int32, dsec Float64, gregflag Int32) (et, ut1 JulianDay, err error) {
+	// 检查日期是否有效
+	tjdUt1 := Julday(int(iyear), int(imonth), int(iday), 0, int(gregflag))
+	iyear2, imonth2, iday2, _ := Revjul(tjdUt1, int(gregflag))
+	if int(iyear) != iyear2 || int(imonth) != imonth2 || int(iday) != iday2 {
+		return et, ut1, fmt.Errorf("无效日期: 年 = %d, 月 = %d, 日 = %d", iyear, imonth, iday)
+	}
+	if ihour < 0 || ihour > 23 ||
+		imin < 0 || imin > 59 ||
+		dsec < 0 || dsec >= 61 ||
+		(dsec >= 60 && (imin < 59 || ihour < 23 || tjdUt1 < j1972)) {
+		return et, ut1, fmt.Errorf("无效时间: %d:%d:%.2f", ihour, imin, dsec)
+	}
+	secs := Float64(ihour)*3600 + Float64(imin)*60 + dsec
 	
-	// 计算Delta T并转换为ET
-	dt := Deltat(ut1)
-	et = ut1 + dt/86400.0
+	// 1972年以前，输入时间作为UT1
+	if tjdUt1 < j1972 {
+		ut1 = JulianDayOf(tjdUt1, secs/86400.0)
+		return UtToEt(ut1, -1), ut1, nil
+	}
 	
-	return et, ut1, nil
+	// 儒略历日期换算为格里高利历
+	if gregflag == SeJulCal {
+		y, m, d, _ := Revjul(tjdUt1, SeGregCal)
+		iyear, imonth, iday = Int32(y), Int32(m), Int32(d)
+	}
+	
+	// 1972年以来的闰秒数
+	leaps := initLeapsec()
+	nleap := nleapInit
+	ndat := int(iyear)*10000 + int(imonth)*100 + int(iday)
+	for _, l := range leaps {
+		if ndat <= l {
+			break
+		}
+		nleap++
+	}
+	
+	// 将来的日期：闰秒表可能未及时更新，若ΔT - nleap - 32.184 >= 1，
+	// 输入时间作为UT1处理
+	d := DeltatEx(tjdUt1, -1)
+	if d-Float64(nleap)-32.184 >= 1.0 {
+		ut1 = JulianDayOf(tjdUt1, secs/86400.0)
+		return UtToEt(ut1, -1), ut1, nil
+	}
+	
+	// 秒数为60时检查当天是否有闰秒
+	if dsec >= 60 {
+		isLeap := false
+		for _, l := range leaps {
+			if ndat == l {
+				isLeap = true
+				break
+			}
+		}
+		if !isLeap {
+			return et, ut1, fmt.Errorf("无效时间（没有闰秒）: %d:%d:%.2f", ihour, imin, dsec)
+		}
+	}
+	
+	// UTC转换为TT和UT1：TT = UTC + (TAI - UTC) + 32.184秒
+	et = JulianDayOf(tjdUt1, (secs+Float64(nleap)+32.184)/86400.0)
+	return et, EtToUt(et, -1), nil
 }
 
-// JdetToUtc 将ET儒略日转换为UTC时间
+// JdetToUtc 将ET（TT）儒略日转换为UTC时间
+// 1972年以前返回UT1；若ΔT与闰秒数不符超过1秒，也返回UT1
 func JdetToUtc(tjdEt Float64, gregflag Int32) (iyear, imonth, iday, ihour, imin Int32, dsec Float64) {
-	// 将ET转换为UT1
-	dt := Deltat(tjdEt)
-	tjdUt := tjdEt - dt/86400.0
+	// 1972年1月1日UTC以前，返回UT1
+	tjdEt1972 := j1972 + (32.184+nleapInit)/86400.0
+	d := DeltatEx(tjdEt, -1) / 86400.0
+	tjdUt := tjdEt - DeltatEx(tjdEt-d, -1)/86400.0
+	tjdUt = tjdEt - DeltatEx(tjdUt, -1)/86400.0
+	if tjdEt < tjdEt1972 {
+		return jdToCalendarTime(tjdUt, int(gregflag))
+	}
 	
-	// 转换为日历日期
-	year, month, day, jut := Revjul(tjdUt, int(gregflag))
+	// 1972年以来最少的闰秒数，可能还少一个
+	leaps := initLeapsec()
+	iyear2, imonth2, iday2, _ := Revjul(tjdUt-1, SeGregCal)
+	ndat := iyear2*10000 + imonth2*100 + iday2
+	nleap := 0
+	for _, l := range leaps {
+		if ndat <= l {
+			break
+		}
+		nleap++
+	}
+	// 可能遗漏的闰秒的日期
+	second60 := 0
+	if nleap < len(leaps) {
+		l := leaps[nleap]
+		tjd := Julday(l/10000, (l%10000)/100, l%100, 0, SeGregCal)
+		y, m, dd, _ := Revjul(tjd+1, SeGregCal)
+		etNext, _, _ := UtcToJd(Int32(y), Int32(m), Int32(dd), 0, 0, 0, SeGregCal)
+		diff := tjdEt - etNext
+		if diff >= 0 {
+			nleap++
+		} else if diff > -1.0/86400.0 {
+			second60 = 1
+		}
+	}
 	
-	iyear = Int32(year)
-	imonth = Int32(month)
-	iday = Int32(day)
+	// UTC
+	tjd := j1972 + (tjdEt - tjdEt1972) - (Float64(nleap)+Float64(second60))/86400.0
+	iyear, imonth, iday, ihour, imin, dsec = jdToCalendarTime(tjd, SeGregCal)
+	dsec += Float64(second60)
 	
-	// 转换小时分秒
-	ihour = Int32(jut)
-	remainder := jut - Float64(ihour)
-	imin = Int32(remainder * 60.0)
-	dsec = (remainder*60.0 - Float64(imin)) * 60.0
+	// 将来的日期：闰秒表可能未及时更新，若ΔT - nleap - 32.184 >= 1，返回UT1
+	d = DeltatEx(tjdEt, -1) / 86400.0
+	d = DeltatEx(tjdEt-d, -1) / 86400.0
+	if d*86400.0-Float64(nleap+nleapInit)-32.184 >= 1.0 {
+		iyear, imonth, iday, ihour, imin, dsec = jdToCalendarTime(tjdEt-d, SeGregCal)
+	}
+	if gregflag == SeJulCal {
+		tjd = Julday(int(iyear), int(imonth), int(iday), 0, SeGregCal)
+		y, m, dd, _ := Revjul(tjd, SeJulCal)
+		iyear, imonth, iday = Int32(y), Int32(m), Int32(dd)
+	}
+	
+	return
+}
+
+// JdetToUtcSplit 同JdetToUtc，输入两部分的儒略日
+func JdetToUtcSplit(tjdEt JulianDay, gregflag Int32) (iyear, imonth, iday, ihour, imin Int32, dsec Float64) {
+	// 1972年1月1日UTC以前，返回UT1
+	tjdEt1972 := j1972 + (32.184+nleapInit)/86400.0
+	tjdUt := EtToUt(tjdEt, -1)
+	if tjdEt.Float64() < tjdEt1972 {
+		return jdToCalendarTimeSplit(tjdUt, int(gregflag))
+	}
+	
+	// 1972年以来最少的闰秒数，可能还少一个
+	leaps := initLeapsec()
+	iyear2, imonth2, iday2, _ := Revjul(tjdUt.Float64()-1, SeGregCal)
+	ndat := iyear2*10000 + imonth2*100 + iday2
+	nleap := 0
+	for _, l := range leaps {
+		if ndat <= l {
+			break
+		}
+		nleap++
+	}
+	// 可能遗漏的闰秒的日期
+	second60 := false
+	var leapDay, leapDiff Float64
+	if nleap < len(leaps) {
+		l := leaps[nleap]
+		leapDay = Julday(l/10000, (l%10000)/100, l%100, 0, SeGregCal)
+		y, m, dd, _ := Revjul(leapDay+1, SeGregCal)
+		etNext, _, _ := UtcToJdSplit(Int32(y), Int32(m), Int32(dd), 0, 0, 0, SeGregCal)
+		leapDiff = tjdEt.Sub(etNext)
+		if leapDiff >= 0 {
+			nleap++
+		} else if leapDiff > -1.0/86400.0 {
+			second60 = true
+		}
+	}
+	
+	if second60 {
+		// 闰秒之中：直接由到下一天0时的时间得出秒数，避免舍入进到下一天
+		y, m, dd, _ := Revjul(leapDay, SeGregCal)
+		iyear, imonth, iday, ihour, imin = Int32(y), Int32(m), Int32(dd), 23, 59
+		dsec = 61 + leapDiff*86400
+	} else {
+		// UTC = TT - 32.184秒 - (TAI - UTC)
+		utc := tjdEt.AddSeconds(-(32.184 + Float64(nleapInit+nleap)))
+		iyear, imonth, iday, ihour, imin, dsec = jdToCalendarTimeSplit(utc, SeGregCal)
+	}
+	
+	// 将来的日期：闰秒表可能未及时更新，若ΔT - nleap - 32.184 >= 1，返回UT1
+	d := DeltatEx(tjdEt.Float64(), -1) / 86400.0
+	d = DeltatEx(tjdEt.Float64()-d, -1)
+	if d-Float64(nleap+nleapInit)-32.184 >= 1.0 {
+		iyear, imonth, iday, ihour, imin, dsec = jdToCalendarTimeSplit(tjdEt.AddSeconds(-d), SeGregCal)
+	}
+	if gregflag == SeJulCal {
+		tjd := Julday(int(iyear), int(imonth), int(iday), 0, SeGregCal)
+		y, m, dd, _ := Revjul(tjd, SeJulCal)
+		iyear, imonth, iday = Int32(y), Int32(m), Int32(dd)
+	}
 	
 	return
 }
 
 // Jdut1ToUtc 将UT1儒略日转换为UTC时间
 func Jdut1ToUtc(tjdUt Float64, gregflag Int32) (iyear, imonth, iday, ihour, imin Int32, dsec Float64) {
-	// 转换为日历日期
-	year, month, day, jut := Revjul(tjdUt, int(gregflag))
-	
+	tjdEt := tjdUt + DeltatEx(tjdUt, -1)/86400.0
+	return JdetToUtc(tjdEt, gregflag)
+}
+
+// jdToCalendarTime 将儒略日转换为日历日期和时分秒
+func jdToCalendarTime(tjd Float64, gregflag int) (iyear, imonth, iday, ihour, imin Int32, dsec Float64) {
+	year, month, day, jut := Revjul(tjd, gregflag)
 	iyear = Int32(year)
 	imonth = Int32(month)
 	iday = Int32(day)
-	
-	// 转换小时分秒
 	ihour = Int32(jut)
-	remainder := jut - Float64(ihour)
-	imin = Int32(remainder * 60.0)
-	dsec = (remainder*60.0 - Float64(imin)) * 60.0
-	
+	d := (jut - Float64(ihour)) * 60
+	imin = Int32(d)
+	dsec = (d - Float64(imin)) * 60.0
+	return
+}
+
+// jdToCalendarTimeSplit 将两部分的儒略日转换为日历日期和时分秒
+func jdToCalendarTimeSplit(tjd JulianDay, gregflag int) (iyear, imonth, iday, ihour, imin Int32, dsec Float64) {
+	tjd = JulianDayOf(tjd.Day, tjd.Frac)
+	year, month, day, _ := Revjul(tjd.Day, gregflag)
+	iyear = Int32(year)
+	imonth = Int32(month)
+	iday = Int32(day)
+	ihour, imin, dsec = tjd.secondsOfDay()
 	return
 }
 
@@ -220,41 +511,65 @@ func UtcTimeZone(iyear, imonth, iday, ihour, imin Int32, dsec, dTimezone Float64
 	return
 }
 
-// DayOfWeek 计算星期几（0=周日，1=周一，...，6=周六）
-func DayOfWeek(jd Float64) int {
-	return int(math.Mod(math.Floor(jd+1.5), 7))
+// TimeEqu 计算世界时tjdUt的时差（日），即视太阳时减平太阳时
+// 由太阳的视赤经和恒星时求得；JPL星历已打开时使用JPL星历，否则使用默认星历
+func TimeEqu(tjdUt Float64) (Float64, error) {
+	sidt := Sidtime(tjdUt)
+	iflag := Int32(SeflgEquatorial)
+	if GetSweData().JplFileIsOpen {
+		iflag |= SeflgJpleph
+	}
+	
+	// 格林尼治世界时0时的恒星时（度）
+	t := tjdUt + 0.5
+	dt := t - math.Floor(t)
+	sidt -= dt * 24
+	sidt *= 15
+	x, err := CalcUT(tjdUt, SeSun, iflag)
+	if err != nil {
+		return 0, err
+	}
+	
+	// 太阳的时角与平太阳时之差，换算为日
+	dt = degnorm(sidt - x[0] - 180)
+	if dt > 180 {
+		dt -= 360
+	}
+	dt *= 4
+	return dt / 1440.0, nil
 }
 
-// 简化的Delta T计算（实际实现应该更复杂）
-func Deltat(tjd Float64) Float64 {
-	// 这里使用简化算法，实际应该根据历史数据和预测模型
-	year := (tjd - J2000) / 365.25 + 2000.0
-	
-	if year < 1620 {
-		// 1620年之前的估算
-		t := (year - 2000) / 100
-		return -20 + 32*t*t
-	} else if year < 1972 {
-		// 1620-1972年的估算
-		t := year - 2000
-		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
-	} else if year < 2005 {
-		// 1972-2005年的实测值区间
-		t := year - 2000
-		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
-	} else {
-		// 2005年之后的预测
-		t := year - 2005
-		return 64.184 + 0.8*t
+// LmtToLat 将地方平时tjdLmt（儒略日）换算为地理经度geolon（度，东经为正）处的地方视时
+func LmtToLat(tjdLmt, geolon Float64) (Float64, error) {
+	tjdLmt0 := tjdLmt - geolon/360.0
+	e, err := TimeEqu(tjdLmt0)
+	return tjdLmt + e, err
+}
+
+// LatToLmt 将地方视时tjdLat（儒略日）换算为地理经度geolon（度，东经为正）处的地方平时
+// 时差取决于地方平时，因此迭代两次
+func LatToLmt(tjdLat, geolon Float64) (Float64, error) {
+	tjdLmt0 := tjdLat - geolon/360.0
+	e, err := TimeEqu(tjdLmt0)
+	if err != nil {
+		return tjdLat, err
 	}
+	for i := 0; i < 2; i++ {
+		if e, err = TimeEqu(tjdLmt0 - e); err != nil {
+			return tjdLat, err
+		}
+	}
+	return tjdLat - e, nil
+}
+
+// DayOfWeek 计算星期几（0=周日，1=周一，...，6=周六）
+func DayOfWeek(jd Float64) int {
+	return int(math.Mod(math.Floor(jd+1.5), 7))
 }
 
 // GetCurrentTime 获取当前时间的儒略日
 func GetCurrentTime() Float64 {
-	now := time.Now().UTC()
-	return Julday(now.Year(), int(now.Month()), now.Day(), 
-		Float64(now.Hour()) + Float64(now.Minute())/60.0 + Float64(now.Second())/3600.0 + Float64(now.Nanosecond())/3600000000000.0, 
-		SeGregCal)
+	return TimeToJd(time.Now())
 }
 
 // IsLeapYear 判断是否为闰年
@@ -0,0 +1,111 @@
+// 儒略日分解为日历日期和时间，按指定的单位四舍五入，
+// 进位经过分、时、日、月、年；UTC可以表示闰秒23:59:60。
+
+package ephgo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 常用的舍入单位，也可以使用其他能整除一秒的时长
+const (
+	RoundSecond      = time.Second
+	RoundCentisecond = 10 * time.Millisecond
+	RoundMillisecond = time.Millisecond
+	RoundNanosecond  = time.Nanosecond
+)
+
+// DateTime 日历日期和时间，秒分为整秒和纳秒
+// UTC的闰秒Second为60
+type DateTime struct {
+	Year, Month, Day     int
+	Hour, Minute, Second int
+	Nanosecond           int
+}
+
+// Seconds 返回秒（十进制）
+func (dt DateTime) Seconds() Float64 {
+	return Float64(dt.Second) + Float64(dt.Nanosecond)/1e9
+}
+
+// String 返回“2006-01-02 15:04:05.999”格式的日期和时间，省略秒的小数末尾的0
+func (dt DateTime) String() string {
+	s := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", dt.Year, dt.Month, dt.Day, dt.Hour, dt.Minute, dt.Second)
+	if dt.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", dt.Nanosecond), "0")
+	}
+	return s
+}
+
+// UtcToJd 将dt作为UTC换算为ET（TT）和UT1儒略日，见UtcToJdSplit
+func (dt DateTime) UtcToJd(gregflag Int32) (et, ut1 JulianDay, err error) {
+	return UtcToJdSplit(Int32(dt.Year), Int32(dt.Month), Int32(dt.Day), Int32(dt.Hour), Int32(dt.Minute), dt.Seconds(), gregflag)
+}
+
+// JdToDateTime 将儒略日tjd分解为日期和时间，没有闰秒；时间按unit四舍五入
+// unit必须能整除一秒，例如RoundSecond、RoundCentisecond或RoundMillisecond
+func JdToDateTime(tjd JulianDay, gregflag int, unit time.Duration) (DateTime, error) {
+	tjd = JulianDayOf(tjd.Day, tjd.Frac)
+	year, month, day, _ := Revjul(tjd.Day, gregflag)
+	ns := tjd.Frac * nsPerDay
+	return roundDateTime(year, month, day, int64(ns+0.5), gregflag, false, unit)
+}
+
+// JdetToUtcDateTime 将ET（TT）儒略日换算为UTC的日期和时间，见JdetToUtcSplit
+// 时间按unit四舍五入，闰秒表示为23:59:60
+func JdetToUtcDateTime(tjdEt JulianDay, gregflag Int32, unit time.Duration) (DateTime, error) {
+	iyear, imonth, iday, ihour, imin, dsec := JdetToUtcSplit(tjdEt, gregflag)
+	ns := (Float64(ihour)*3600+Float64(imin)*60+dsec)*1e9 + 0.5
+	return roundDateTime(int(iyear), int(imonth), int(iday), int64(ns), int(gregflag), true, unit)
+}
+
+// Jdut1ToUtcDateTime 将UT1儒略日换算为UTC的日期和时间，见JdetToUtcDateTime
+func Jdut1ToUtcDateTime(tjdUt JulianDay, gregflag Int32, unit time.Duration) (DateTime, error) {
+	return JdetToUtcDateTime(UtToEt(tjdUt, -1), gregflag, unit)
+}
+
+// roundDateTime 将日内的纳秒数ns按unit四舍五入，再分解为时、分、秒
+// utc为真时，有闰秒的日期长86401秒，最后一秒为23:59:60
+func roundDateTime(year, month, day int, ns int64, gregflag int, utc bool, unit time.Duration) (DateTime, error) {
+	if unit <= 0 || time.Second%unit != 0 {
+		return DateTime{}, fmt.Errorf("舍入单位%v不能整除一秒", unit)
+	}
+	u := int64(unit)
+	ns = (ns + u/2) / u * u
+	dayLen := int64(86400e9)
+	jd0 := Julday(year, month, day, 0, gregflag)
+	if utc && isLeapSecondDay(jd0) {
+		dayLen += 1e9
+	}
+	// 进位到次日，Revjul处理月和年的进位
+	if ns >= dayLen {
+		ns -= dayLen
+		jd0++
+		year, month, day, _ = Revjul(jd0, gregflag)
+	}
+	dt := DateTime{Year: year, Month: month, Day: day, Nanosecond: int(ns % 1e9)}
+	secs := int(ns / 1e9)
+	if secs >= 86400 {
+		// 闰秒
+		dt.Hour, dt.Minute, dt.Second = 23, 59, secs-86340
+		return dt, nil
+	}
+	dt.Hour = secs / 3600
+	dt.Minute = secs / 60 % 60
+	dt.Second = secs % 60
+	return dt, nil
+}
+
+// isLeapSecondDay 判断0时的儒略日为jd0的日期末尾是否插入了闰秒
+func isLeapSecondDay(jd0 Float64) bool {
+	y, m, d, _ := Revjul(jd0, SeGregCal)
+	ndat := y*10000 + m*100 + d
+	for _, l := range initLeapsec() {
+		if l == ndat {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+// IERS地球定向参数（EOP）：UT1-UTC、极移以及章动改正dpsi和deps，
+// 读取星历路径中的eop_1962_today.txt（IERS C04）和eop_finals.txt（IERS finals.all），
+// 或者由LoadEopFiles指定的文件。
+
+package ephgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EOP文件
+const (
+	eopFileC04    = "eop_1962_today.txt" // IERS C04，1962年至今的逐日数据
+	eopFileFinals = "eop_finals.txt"     // IERS finals.all，包括最近的数据和预报
+	eopTjdOfs     = 2400000.5            // 简化儒略日的零点
+)
+
+// taiUtcPre1972 1972年以前TAI-UTC的分段公式：offset + (MJD - mjd0) * rate（秒）
+var taiUtcPre1972 = []struct {
+	tjd, offset, mjd0, rate Float64
+}{
+	{2437300.5, 1.4228180, 37300, 0.001296},  // 1961年1月1日
+	{2437512.5, 1.3728180, 37300, 0.001296},  // 1961年8月1日
+	{2437665.5, 1.8458580, 37665, 0.0011232}, // 1962年1月1日
+	{2438334.5, 1.9458580, 37665, 0.0011232}, // 1963年11月1日
+	{2438395.5, 3.2401300, 38761, 0.001296},  // 1964年1月1日
+	{2438486.5, 3.3401300, 38761, 0.001296},  // 1964年4月1日
+	{2438639.5, 3.4401300, 38761, 0.001296},  // 1964年9月1日
+	{2438761.5, 3.5401300, 38761, 0.001296},  // 1965年1月1日
+	{2438820.5, 3.6401300, 38761, 0.001296},  // 1965年3月1日
+	{2438942.5, 3.7401300, 38761, 0.001296},  // 1965年7月1日
+	{2439004.5, 3.8401300, 38761, 0.001296},  // 1965年9月1日
+	{2439126.5, 4.3131700, 39126, 0.002592},  // 1966年1月1日
+	{2439887.5, 4.2131700, 39126, 0.002592},  // 1968年2月1日
+}
+
+// taiMinusUtc 返回UTC儒略日tjdUtc的TAI-UTC（秒）
+// 1972年以后为整秒数，由闰秒表得出；此前按分段公式计算
+func taiMinusUtc(tjdUtc Float64) Float64 {
+	if tjdUtc >= j1972 {
+		iyear, imonth, iday, _ := Revjul(tjdUtc, SeGregCal)
+		ndat := iyear*10000 + imonth*100 + iday
+		nleap := nleapInit
+		for _, leap := range initLeapsec() {
+			if leap < ndat {
+				nleap++
+			}
+		}
+		return Float64(nleap)
+	}
+	i := len(taiUtcPre1972) - 1
+	for i > 0 && tjdUtc < taiUtcPre1972[i].tjd {
+		i--
+	}
+	c := taiUtcPre1972[i]
+	return c.offset + (tjdUtc-eopTjdOfs-c.mjd0)*c.rate
+}
+
+// eopColumn 按列号（从1开始，含end）读取finals.all中的数值，空白时ok为false
+func eopColumn(line string, beg, end int) (v Float64, ok bool) {
+	if len(line) < beg {
+		return 0, false
+	}
+	if len(line) < end {
+		end = len(line)
+	}
+	s := strings.TrimSpace(line[beg-1 : end])
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// resetEop 丢弃已加载的EOP数据，下次需要时重新读取
+func resetEop() {
+	swed.EopDpsiLoaded = 0
+	swed.EopTjdBeg = 0
+	swed.EopTjdBegHorizons = 0
+	swed.EopTjdEnd = 0
+	swed.EopTjdEndAdd = 0
+	swed.Dpsi = nil
+	swed.Deps = nil
+	swed.Ut1Tai = nil
+	swed.PolarX = nil
+	swed.PolarY = nil
+	// 观测者位置包括极移，需要重新计算
+	swed.Topd.Teval = 0
+}
+
+// loadEop 从星历路径读取EOP文件eop_1962_today.txt和eop_finals.txt，已加载时直接返回
+func loadEop() error {
+	if swed.EopDpsiLoaded > 0 {
+		return nil
+	}
+	resetEop()
+	fp, err := sweFopen(-1, eopFileC04, currentEphePath())
+	if err != nil {
+		swed.EopDpsiLoaded = -1
+		return fmt.Errorf("找不到EOP文件%s", eopFileC04)
+	}
+	defer fp.Close()
+	// finals.all可能有更新的数据，特别是最近的将来的预报；文件不存在时不报错
+	fp2, err := sweFopen(-1, eopFileFinals, currentEphePath())
+	if err != nil {
+		return readEop(fp, eopFileC04, nil, "")
+	}
+	defer fp2.Close()
+	return readEop(fp, eopFileC04, fp2, eopFileFinals)
+}
+
+// loadEopFiles 读取指定路径的EOP文件，finalsPath为空时只读取C04文件
+func loadEopFiles(c04Path, finalsPath string) error {
+	resetEop()
+	fp, err := os.Open(c04Path)
+	if err != nil {
+		swed.EopDpsiLoaded = -1
+		return fmt.Errorf("找不到EOP文件%s", c04Path)
+	}
+	defer fp.Close()
+	if finalsPath == "" {
+		return readEop(fp, c04Path, nil, "")
+	}
+	fp2, err := os.Open(finalsPath)
+	if err != nil {
+		resetEop()
+		return fmt.Errorf("找不到EOP文件%s", finalsPath)
+	}
+	defer fp2.Close()
+	return readEop(fp, c04Path, fp2, finalsPath)
+}
+
+// readEop 读取IERS C04数据c04和可选的finals.all数据finals（为nil时不读取），名称用于错误信息
+// C04必须为逐日数据；finals.all只使用C04表结束以后的数据。
+// dpsi和deps到EopTjdEnd为止（finals.all中没有章动改正的预报），
+// UT1和极移到EopTjdEndAdd为止。加载的状态记录在EopDpsiLoaded中：
+// 1或2为已加载，-1为找不到C04文件，-2为C04文件损坏，-3为finals文件损坏
+func readEop(c04 io.Reader, c04Name string, finals io.Reader, finalsName string) error {
+	resetEop()
+	swed.EopTjdBegHorizons = horizonsTjd0DpsiDepsIau1980
+	corrupt := func(status int, fname string) error {
+		resetEop()
+		swed.EopDpsiLoaded = status
+		return fmt.Errorf("EOP文件%s损坏或不是逐日数据", fname)
+	}
+	// C04：年 月 日 MJD x y UT1-UTC LOD dPsi dEps ...
+	mjdsv := 0
+	scanner := bufio.NewScanner(c04)
+	for scanner.Scan() {
+		f := strings.Fields(scanner.Text())
+		if len(f) < 10 {
+			continue
+		}
+		if iyear, err := strconv.Atoi(f[0]); err != nil || iyear == 0 {
+			continue
+		}
+		mjd, err := strconv.Atoi(f[3])
+		if err != nil || mjdsv > 0 && mjd-mjdsv != 1 {
+			return corrupt(-2, c04Name)
+		}
+		var v [5]Float64
+		for i, k := range []int{4, 5, 6, 8, 9} {
+			if v[i], err = strconv.ParseFloat(f[k], 64); err != nil {
+				return corrupt(-2, c04Name)
+			}
+		}
+		tjd := Float64(mjd) + eopTjdOfs
+		if mjdsv == 0 {
+			swed.EopTjdBeg = tjd
+		}
+		swed.PolarX = append(swed.PolarX, v[0])
+		swed.PolarY = append(swed.PolarY, v[1])
+		swed.Ut1Tai = append(swed.Ut1Tai, v[2]-taiMinusUtc(tjd))
+		swed.Dpsi = append(swed.Dpsi, v[3])
+		swed.Deps = append(swed.Deps, v[4])
+		swed.EopTjdEnd = tjd
+		mjdsv = mjd
+	}
+	if mjdsv == 0 {
+		return corrupt(-2, c04Name)
+	}
+	swed.EopTjdEndAdd = swed.EopTjdEnd
+	swed.EopDpsiLoaded = 1
+	if finals == nil {
+		return nil
+	}
+	dpsiDone := false
+	scanner = bufio.NewScanner(finals)
+	for scanner.Scan() {
+		s := scanner.Text()
+		fmjd, ok := eopColumn(s, 8, 15)
+		if !ok {
+			continue
+		}
+		mjd := int(fmjd)
+		tjd := Float64(mjd) + eopTjdOfs
+		if tjd <= swed.EopTjdEndAdd {
+			continue
+		}
+		if mjd-mjdsv != 1 {
+			return corrupt(-3, finalsName)
+		}
+		// 优先使用Bulletin B的值，没有时使用Bulletin A的值
+		dut1, ok := eopColumn(s, 155, 165)
+		if !ok {
+			if dut1, ok = eopColumn(s, 59, 68); !ok {
+				break
+			}
+		}
+		xp, ok := eopColumn(s, 135, 144)
+		if !ok {
+			xp, _ = eopColumn(s, 19, 27)
+		}
+		yp, ok := eopColumn(s, 145, 154)
+		if !ok {
+			yp, _ = eopColumn(s, 38, 46)
+		}
+		swed.PolarX = append(swed.PolarX, xp)
+		swed.PolarY = append(swed.PolarY, yp)
+		swed.Ut1Tai = append(swed.Ut1Tai, dut1-taiMinusUtc(tjd))
+		swed.EopTjdEndAdd = tjd
+		mjdsv = mjd
+		// dpsi和deps（毫角秒），预报中没有
+		if dpsiDone {
+			continue
+		}
+		dpsi, _ := eopColumn(s, 166, 175)
+		deps, _ := eopColumn(s, 176, 185)
+		if dpsi == 0 {
+			dpsi, _ = eopColumn(s, 98, 106)
+			deps, _ = eopColumn(s, 117, 125)
+		}
+		if dpsi == 0 {
+			dpsiDone = true
+			continue
+		}
+		swed.Dpsi = append(swed.Dpsi, dpsi/1000.0)
+		swed.Deps = append(swed.Deps, deps/1000.0)
+		swed.EopTjdEnd = tjd
+	}
+	swed.EopDpsiLoaded = 2
+	return nil
+}
+
+// LoadEop 从星历路径读取IERS地球定向参数文件eop_1962_today.txt和eop_finals.txt
+// 加载后：
+//   - ΔT在数据范围内由UT1-UTC和闰秒得出，从而用于UtcToJd、Jdut1ToUtc和CalcUT等世界时的换算；
+//   - GetEop返回UT1-UTC、极移和章动改正；
+//   - 站心坐标（SeflgTopoctr）的观测者位置包括极移；
+//   - JPL星历可以使用SeflgJplhor（JPL Horizons模式）。
+//
+// 打开DE403以后的JPL星历时也自动加载；SetEphePath和Close丢弃已加载的数据
+func LoadEop() error {
+	swed := GetSweData()
+	err := loadEop()
+	forceAppPosEtc()
+	SetSweData(swed)
+	return err
+}
+
+// LoadEopFiles 读取指定路径的IERS地球定向参数文件，用法同LoadEop
+// c04Path: IERS C04文件（如eopc04_IAU2000.62-now），必须为逐日数据
+// finalsPath: IERS finals.all文件，为空时不读取
+// 已加载的数据总是被替换；SetEphePath和Close丢弃已加载的数据
+func LoadEopFiles(c04Path, finalsPath string) error {
+	swed := GetSweData()
+	err := loadEopFiles(c04Path, finalsPath)
+	forceAppPosEtc()
+	SetSweData(swed)
+	return err
+}
+
+// eopDeltat 由EOP数据得出世界时tjd的ΔT（秒），即32.184 - (UT1 - TAI)
+// EOP未加载或tjd超出数据范围时ok为false
+func eopDeltat(tjd Float64) (dt Float64, ok bool) {
+	if swed.EopDpsiLoaded <= 0 || tjd < swed.EopTjdBeg || tjd > swed.EopTjdEndAdd {
+		return 0, false
+	}
+	return 32.184 - bessel(swed.Ut1Tai, len(swed.Ut1Tai), tjd-swed.EopTjdBeg), true
+}
+
+// GetEop 返回UTC儒略日tjdUtc的地球定向参数，数据用Bessel公式内插：
+// dut1为UT1-UTC（秒），xp和yp为极移（角秒），dpsi和deps为相对于IAU 1980章动的改正（角秒）。
+// UT1在闰秒处的跳变已考虑；dpsi和deps在表尾以后使用最后的值
+func GetEop(tjdUtc Float64) (dut1, xp, yp, dpsi, deps Float64, err error) {
+	swed := GetSweData()
+	if swed.EopDpsiLoaded <= 0 {
+		return 0, 0, 0, 0, 0, fmt.Errorf("EOP数据未加载")
+	}
+	if tjdUtc < swed.EopTjdBeg || tjdUtc > swed.EopTjdEndAdd {
+		return 0, 0, 0, 0, 0, fmt.Errorf("儒略日%f超出EOP数据的范围（%f至%f）", tjdUtc, swed.EopTjdBeg, swed.EopTjdEndAdd)
+	}
+	t := tjdUtc - swed.EopTjdBeg
+	n := len(swed.Ut1Tai)
+	dut1 = bessel(swed.Ut1Tai, n, t) + taiMinusUtc(tjdUtc)
+	xp = bessel(swed.PolarX, n, t)
+	yp = bessel(swed.PolarY, n, t)
+	dpsi = bessel(swed.Dpsi, len(swed.Dpsi), t)
+	deps = bessel(swed.Deps, len(swed.Deps), t)
+	return dut1, xp, yp, dpsi, deps, nil
+}
+
+// eopPolarMotion 返回世界时tjd的极移xp和yp（角秒），EOP未加载或tjd超出数据范围时ok为false
+func eopPolarMotion(tjd Float64) (xp, yp Float64, ok bool) {
+	if swed.EopDpsiLoaded <= 0 || tjd < swed.EopTjdBeg || tjd > swed.EopTjdEndAdd {
+		return 0, 0, false
+	}
+	t := tjd - swed.EopTjdBeg
+	n := len(swed.PolarX)
+	return bessel(swed.PolarX, n, t), bessel(swed.PolarY, n, t), true
+}
@@ -46,7 +46,7 @@ func calculateCurrentSunPosition() {
 	tjdUT := ephgo.GetCurrentTime()
 	
 	// 计算太阳位置（地心坐标，视位置）
-	xx, err := ephgo.CalcUT(tjdUT, ephgo.SeSun, ephgo.SeflgSwieph)
+	xx, err := ephgo.CalcUT(tjdUT, ephgo.SeSun, ephgo.SeflgSwieph|ephgo.SeflgSpeed)
 	if err != nil {
 		log.Printf("计算太阳位置失败: %v", err)
 		return
@@ -144,7 +144,7 @@ func calculateLunarNodesAndApogee() {
 	fmt.Printf("日期: 2024年1月1日 12:00 UTC\n\n")
 	
 	// 月球平交点
-	xxMeanNode, err := ephgo.Calc(tjd, ephgo.SeMeanNode, ephgo.SeflgSwieph)
+	xxMeanNode, err := ephgo.Calc(tjd, ephgo.SeMeanNode, ephgo.SeflgSwieph|ephgo.SeflgSpeed)
 	if err == nil {
 		fmt.Printf("月球平交点:\n")
 		fmt.Printf("  经度: %.6f°\n", xxMeanNode[0])
@@ -154,7 +154,7 @@ func calculateLunarNodesAndApogee() {
 	}
 	
 	// 月球真交点
-	xxTrueNode, err := ephgo.Calc(tjd, ephgo.SeTrueNode, ephgo.SeflgSwieph)
+	xxTrueNode, err := ephgo.Calc(tjd, ephgo.SeTrueNode, ephgo.SeflgSwieph|ephgo.SeflgSpeed)
 	if err == nil {
 		fmt.Printf("\n月球真交点:\n")
 		fmt.Printf("  经度: %.6f°\n", xxTrueNode[0])
@@ -164,7 +164,7 @@ func calculateLunarNodesAndApogee() {
 	}
 	
 	// 月球平远地点
-	xxMeanApog, err := ephgo.Calc(tjd, ephgo.SeMeanApog, ephgo.SeflgSwieph)
+	xxMeanApog, err := ephgo.Calc(tjd, ephgo.SeMeanApog, ephgo.SeflgSwieph|ephgo.SeflgSpeed)
 	if err == nil {
 		fmt.Printf("\n月球平远地点:\n")
 		fmt.Printf("  经度: %.6f°\n", xxMeanApog[0])
@@ -0,0 +1,425 @@
+package ephgo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// kmSToAuCty 径向速度由km/s换算为天文单位/世纪
+const kmSToAuCty = 21.095
+
+// getBuiltinStar 返回内置恒星的搜索名和星表记录
+// 这些恒星用于基于恒星位置的印度岁差，不需要恒星文件；
+// 恒星函数先按星表查找，星表中都找不到时才使用内置的记录
+func getBuiltinStar(star string) (sstar, srecord string, ok bool) {
+	switch {
+	case strings.HasPrefix(star, "spica") || strings.HasPrefix(star, "Spica"):
+		// SeSidmTrueCitra
+		return "spica", "Spica,alVir,ICRS,13,25,11.57937,-11,09,40.7501,-42.35,-30.67,1,13.06,0.97,-10,3672", true
+	case strings.Contains(star, ",zePsc") || strings.HasPrefix(star, "revati") || strings.HasPrefix(star, "Revati"):
+		// SeSidmTrueRevati
+		return "revati", "Revati,zePsc,ICRS,01,13,43.88735,+07,34,31.2745,145,-55.69,15,18.76,5.187,06,174", true
+	case strings.Contains(star, ",deCnc") || strings.HasPrefix(star, "pushya") || strings.HasPrefix(star, "Pushya"):
+		// SeSidmTruePushya、SeSidmTrueSheoran
+		return "pushya", "Pushya,deCnc,ICRS,08,44,41.09921,+18,09,15.5034,-17.67,-229.26,17.14,24.98,3.94,18,2027", true
+	case strings.Contains(star, ",laSco") || star == "mula" || star == "Mula":
+		// SeSidmTrueMula
+		return "mula", "Mula,laSco,ICRS,17,33,36.52012,-37,06,13.7648,-8.53,-30.8,-3,5.71,1.62,-37,11673", true
+	case strings.Contains(star, ",SgrA*"):
+		// SeSidmGalcent0Sag、SeSidmGalcentCochrane、SeSidmGalcentRgilbrand、SeSidmGalcentMulaWilhelm
+		return ",SgrA*", "Gal. Center,SgrA*,2000,17,45,40.03599,-29,00,28.1699,-2.755718425,-5.547,0.0,0.125,999.99,0,0", true
+	case strings.Contains(star, ",GP1958"):
+		// SeSidmGalequIau1958
+		return ",GP1958", "Gal. Pole IAU1958,GP1958,1950,12,49,0.0,27,24,0.0,0.0,0.0,0.0,0.0,0.0,0,0", true
+	case strings.Contains(star, ",GPol"):
+		// SeSidmGalequTrue、SeSidmGalequMula
+		return ",GPol", "Gal. Pole,GPol,ICRS,12,51,36.7151981,27,06,11.193172,0.0,0.0,0.0,0.0,0.0,0,0", true
+	}
+	return "", "", false
+}
+
+// fixstarCutString 解析恒星文件的一条记录
+// 返回的恒星数据已换算为弧度、弧度/世纪和天文单位/世纪，以及"传统名,拜耳名"形式的名称
+func fixstarCutString(srecord string) (FixedStar, string, error) {
+	var stardata FixedStar
+	cpos := strings.Split(strings.TrimRight(srecord, "\r\n"), ",")
+	if len(cpos) < 14 {
+		if len(cpos) >= 2 {
+			return stardata, "", fmt.Errorf("恒星'%s,%s'的数据不完整", strings.TrimRight(cpos[0], " \t"), strings.TrimRight(cpos[1], " \t"))
+		}
+		return stardata, "", fmt.Errorf("恒星文件中的无效记录: '%s'", srecord)
+	}
+	stardata.Starname = strings.TrimRight(cpos[0], " \t")
+	stardata.Starbayer = strings.TrimRight(cpos[1], " \t")
+	star := stardata.Starname + "," + stardata.Starbayer
+	epoch := atofPrefix(cpos[2])
+	raH := atofPrefix(cpos[3])
+	raM := atofPrefix(cpos[4])
+	raS := atofPrefix(cpos[5])
+	deD := atofPrefix(cpos[6])
+	deM := atofPrefix(cpos[7])
+	deS := atofPrefix(cpos[8])
+	raPm := atofPrefix(cpos[9])
+	dePm := atofPrefix(cpos[10])
+	radv := atofPrefix(cpos[11])
+	parall := math.Abs(atofPrefix(cpos[12]))
+	mag := atofPrefix(cpos[13])
+	// 赤经赤纬，度
+	ra := (raS/3600.0 + raM/60.0 + raH) * 15.0
+	var de Float64
+	if !strings.Contains(cpos[6], "-") {
+		de = deS/3600.0 + deM/60.0 + deD
+	} else {
+		de = -deS/3600.0 - deM/60.0 + deD
+	}
+	// 自行，度/世纪
+	if swed.IsOldStarfile {
+		raPm = raPm * 15 / 3600.0
+		dePm = dePm / 3600.0
+	} else {
+		raPm = raPm / 10.0 / 3600.0
+		dePm = dePm / 10.0 / 3600.0
+		parall /= 1000.0
+	}
+	// 视差，度
+	if parall > 1 {
+		parall = 1 / parall / 3600.0
+	} else {
+		parall /= 3600
+	}
+	// 径向速度，天文单位/世纪
+	radv *= kmSToAuCty
+	ra *= DegToRad
+	de *= DegToRad
+	raPm *= DegToRad
+	dePm *= DegToRad
+	// 星表给出的赤经自行为大圆弧长
+	raPm /= math.Cos(de)
+	parall *= DegToRad
+	stardata.Epoch = epoch
+	stardata.Ra = ra
+	stardata.De = de
+	stardata.Ramot = raPm
+	stardata.Demot = dePm
+	stardata.Parall = parall
+	stardata.Radvel = radv
+	stardata.Mag = mag
+	return stardata, star, nil
+}
+
+// fixstarCalcFromStruct 计算恒星在tjd（ET）时刻的位置，返回位置、实际使用的标志
+func fixstarCalcFromStruct(stardata *FixedStar, tjd Float64, iflag Int32) ([6]Float64, Int32, error) {
+	var xx, x, xxsv, xobs, xobsDt [6]Float64
+	var xearth, xearthDt, xsun, xsunDt [6]Float64
+	dt := planSpeedIntv * 0.1
+	iflgsave := iflag
+	// 计算需要速度
+	iflag |= SeflgSpeed
+	iflag = plausIflag(iflag, -1, tjd)
+	epheflag := iflag & sefEphMask
+	if swed.LastEpheflag != epheflag {
+		freePlanets()
+		if swed.JplFileIsOpen {
+			closeJplFileSwe()
+		}
+		for i := range swed.Fidat {
+			closeEphFile(i)
+			swed.Fidat[i] = FileData{}
+		}
+		swed.LastEpheflag = epheflag
+	}
+	if iflag&SeflgSidereal != 0 && !swed.AyanaIsSet {
+		setSidMode(SeSidmFaganBradley, 0, 0)
+	}
+	// 2000年和当日的黄赤交角
+	checkEcliptic(tjd, iflag)
+	// 章动
+	checkNutation(tjd, iflag)
+	epoch := stardata.Epoch
+	var t Float64
+	if epoch == 1950 {
+		t = tjd - B1950 // 自1950.0起的日数
+	} else {
+		t = tjd - J2000 // 自2000.0起的日数
+	}
+	x[0] = stardata.Ra
+	x[1] = stardata.De
+	if stardata.Parall == 0 {
+		x[2] = 1000000000
+	} else {
+		x[2] = 1.0 / (stardata.Parall * RadToDeg * 3600) * ParsecToAunit
+	}
+	x[3] = stardata.Ramot / 36525.0
+	x[4] = stardata.Demot / 36525.0
+	x[5] = stardata.Radvel / 36525.0
+	// 空间运动的直角坐标
+	polcartSp(x[:], x[:])
+	// FK5
+	if epoch == 1950 {
+		fk4Fk5(x[:], B1950)
+		precess(x[:], B1950, 0, jToJ2000)
+		precess(x[3:], B1950, 0, jToJ2000)
+	}
+	// FK5转换到ICRF；ICRS星表的历元为0
+	if epoch != 0 {
+		icrs2fk5(x[:], iflag, true)
+		// DE403以前的星历以J2000为参考系
+		if getDenum(SeiSun, iflag) >= 403 {
+			bias(x[:], J2000, SeflgSpeed, false)
+		}
+	}
+	// 视差、光线偏折和光行差所需的地球和太阳位置
+	noEarth := iflag&SeflgBaryctr != 0 || (iflag&SeflgHelctr != 0 && iflag&SeflgMoseph != 0)
+	if !noEarth {
+		if _, err := mainPlanetBary(tjd-dt, SeiEarth, epheflag, iflag, false, xearthDt[:], xearthDt[:], xsunDt[:]); err != nil {
+			return xx, Err, err
+		}
+		if _, err := mainPlanetBary(tjd, SeiEarth, epheflag, iflag, true, xearth[:], xearth[:], xsun[:]); err != nil {
+			return xx, Err, err
+		}
+	}
+	// 观测者：地心或站心
+	if iflag&SeflgTopoctr != 0 {
+		xoDt, err := getObserver(tjd-dt, iflag|SeflgNonut, false)
+		if err != nil {
+			return xx, Err, err
+		}
+		xo, err := getObserver(tjd, iflag|SeflgNonut, false)
+		if err != nil {
+			return xx, Err, err
+		}
+		for i := 0; i <= 5; i++ {
+			xobs[i] = xo[i] + xearth[i]
+			xobsDt[i] = xoDt[i] + xearthDt[i]
+		}
+	} else if !noEarth {
+		xobs = xearth
+		xobsDt = xearthDt
+	}
+	// 视差
+	var xpo, xpoDt []Float64
+	switch {
+	case iflag&SeflgHelctr != 0 && iflag&SeflgMoseph != 0:
+		// Moshier星历的日心位置不考虑视差
+	case iflag&SeflgHelctr != 0:
+		xpo = xsun[:]
+		xpoDt = xsunDt[:]
+	case iflag&SeflgBaryctr != 0:
+		// 质心位置不考虑视差
+	default:
+		xpo = xobs[:]
+		xpoDt = xobsDt[:]
+	}
+	for i := 0; i <= 2; i++ {
+		x[i] += t * x[i+3]
+		if xpo != nil {
+			x[i] -= xpo[i]
+			x[i+3] -= xpo[i+3]
+		}
+	}
+	// 相对论光线偏折
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNogdefl == 0 {
+		deflectLight(x[:], 0, iflag&SeflgSpeed)
+	}
+	// 周年光行差
+	if iflag&SeflgTruepos == 0 && iflag&SeflgNoaberr == 0 {
+		aberrLightEx(x[:], xpo, xpoDt, dt, iflag&SeflgSpeed)
+	}
+	// ICRS转换到J2000
+	if iflag&SeflgIcrs == 0 && (getDenum(SeiSun, iflag) >= 403 || iflag&SeflgBaryctr != 0) {
+		bias(x[:], tjd, iflag, false)
+	}
+	// 保存J2000坐标，恒星黄道坐标需要
+	xxsv = x
+	// 岁差，J2000赤道转换到当日赤道
+	oe := &swed.Oec2000
+	if iflag&SeflgJ2000 == 0 {
+		precess(x[:], tjd, iflag, j2000ToJ)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(x[:], tjd, iflag, j2000ToJ)
+		}
+		oe = &swed.Oec
+	}
+	// 章动
+	if iflag&SeflgNonut == 0 {
+		nutate(x[:], iflag, false)
+	}
+	// 转换到黄道，恒星黄道坐标随后另行计算
+	if iflag&SeflgEquatorial == 0 {
+		coortrf2(x[:], x[:], oe.Seps, oe.Ceps)
+		if iflag&SeflgSpeed != 0 {
+			coortrf2(x[3:], x[3:], oe.Seps, oe.Ceps)
+		}
+		if iflag&SeflgNonut == 0 {
+			coortrf2(x[:], x[:], swed.Nut.Snut, swed.Nut.Cnut)
+			if iflag&SeflgSpeed != 0 {
+				coortrf2(x[3:], x[3:], swed.Nut.Snut, swed.Nut.Cnut)
+			}
+		}
+	}
+	// 恒星黄道坐标
+	if iflag&SeflgSidereal != 0 {
+		if swed.Sidd.SidMode&SeSidbitEclT0 != 0 {
+			// 严格算法
+			tropRa2sidLon(xxsv[:], x[:], xxsv[:], iflag)
+			if iflag&SeflgEquatorial != 0 {
+				x = xxsv
+			}
+		} else if swed.Sidd.SidMode&SeSidbitSsyPlane != 0 {
+			// 投影到太阳系不变平面
+			tropRa2sidLonSosy(xxsv[:], x[:], iflag)
+			if iflag&SeflgEquatorial != 0 {
+				x = xxsv
+			}
+		} else {
+			// 传统算法
+			cartpolSp(x[:], x[:])
+			daya, _, err := getAyanamsaWithSpeed(tjd, iflag)
+			if err != nil {
+				return xx, Err, err
+			}
+			x[0] -= daya[0] * DegToRad
+			x[3] -= daya[1] * DegToRad
+			polcartSp(x[:], x[:])
+		}
+	}
+	// 极坐标
+	if iflag&SeflgXyz == 0 {
+		cartpolSp(x[:], x[:])
+	}
+	// 弧度转换为度
+	if iflag&SeflgRadians == 0 && iflag&SeflgXyz == 0 {
+		for i := 0; i < 2; i++ {
+			x[i] *= RadToDeg
+			x[i+3] *= RadToDeg
+		}
+	}
+	xx = x
+	if iflgsave&SeflgSpeed == 0 {
+		for i := 3; i <= 5; i++ {
+			xx[i] = 0
+		}
+	}
+	// 未指定星历时不返回所选星历
+	if iflgsave&sefEphMask == 0 {
+		iflag &^= SeflgSwieph
+	}
+	iflag &^= SeflgSpeed
+	return xx, iflag, nil
+}
+
+// fixstar 计算恒星star在tjd（ET）时刻的位置，返回位置、实际使用的标志和"传统名,拜耳名"形式的恒星名称
+// 恒星按优先顺序在星表中查找，都找不到时使用内置恒星，见lookupStar
+func fixstar(star string, tjd Float64, iflag Int32) ([6]Float64, Int32, string, error) {
+	sstar, err := fixstarFormatSearchName(star)
+	if err != nil {
+		return [6]Float64{}, Err, star, err
+	}
+	stardata, err := lookupStar(star, sstar)
+	if err != nil {
+		return [6]Float64{}, Err, star, err
+	}
+	return fixstarData(&stardata, tjd, iflag)
+}
+
+// lookupStar 按优先顺序在星表中查找恒星star（搜索名sstar）
+// 星表中都找不到时使用内置恒星的记录，因此Spica等恒星同C版本一样不需要恒星文件
+func lookupStar(star, sstar string) (FixedStar, error) {
+	stardata, err := findStar(sstar)
+	if err == nil {
+		return stardata, nil
+	}
+	if _, srecord, ok := getBuiltinStar(star); ok {
+		stardata, _, err = fixstarCutString(srecord)
+	}
+	return stardata, err
+}
+
+// builtinFixstar 计算内置恒星star的位置，返回值同fixstar
+// 基于恒星的岁差只使用内置恒星，不需要恒星文件，也不受SetStarCatalogs影响
+func builtinFixstar(star string, tjd Float64, iflag Int32) ([6]Float64, Int32, string, error) {
+	_, srecord, ok := getBuiltinStar(star)
+	if !ok {
+		return [6]Float64{}, Err, star, fmt.Errorf("%s不是内置恒星", star)
+	}
+	stardata, _, err := fixstarCutString(srecord)
+	if err != nil {
+		return [6]Float64{}, Err, star, err
+	}
+	return fixstarData(&stardata, tjd, iflag)
+}
+
+// fixstarData 计算恒星记录stardata在tjd（ET）时刻的位置，返回值同fixstar
+func fixstarData(stardata *FixedStar, tjd Float64, iflag Int32) ([6]Float64, Int32, string, error) {
+	name := stardata.Starname + "," + stardata.Starbayer
+	xx, retflag, err := fixstarCalcFromStruct(stardata, tjd, iflag)
+	if err != nil {
+		return xx, Err, name, err
+	}
+	return xx, retflag, name, nil
+}
+
+// Fixstar2 计算恒星star在tjd（ET）时刻的位置，同swe_fixstar2
+// star可以是传统名（不区分大小写）、","加拜耳名、"传统名,拜耳名"、恒星文件中的序号，
+// 或以'%'结尾的传统名前缀；返回坐标数组xx[6]和"传统名,拜耳名"形式的恒星名称。
+// 计算包括自行、视差和径向速度引起的空间运动，以及岁差、章动、光行差和光线偏折，
+// 可使用与行星相同的标志，如SeflgEquatorial、SeflgTopoctr和SeflgSidereal
+func Fixstar2(star string, tjd Float64, iflag Int32) ([6]Float64, string, error) {
+	if err := initializeSwissEph(); err != nil {
+		return [6]Float64{}, star, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	xx, _, name, err := fixstar(star, tjd, iflag)
+	SetSweData(swed)
+	return xx, name, err
+}
+
+// Fixstar2UT 计算恒星star在世界时tjdUt的位置，同swe_fixstar2_ut，参数见Fixstar2
+func Fixstar2UT(star string, tjdUt Float64, iflag Int32) ([6]Float64, string, error) {
+	if err := initializeSwissEph(); err != nil {
+		return [6]Float64{}, star, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	xx, name, err := fixstarUT(star, tjdUt, iflag)
+	SetSweData(swed)
+	return xx, name, err
+}
+
+// fixstarUT 计算恒星在世界时tjdUt的位置，ΔT与实际使用的星历一致
+func fixstarUT(star string, tjdUt Float64, iflag Int32) ([6]Float64, string, error) {
+	iflag = plausIflag(iflag, -1, tjdUt)
+	epheflag := iflag & sefEphMask
+	if epheflag == 0 {
+		epheflag = SeflgSwieph
+		iflag |= SeflgSwieph
+	}
+	xx, retflag, name, err := fixstar(star, tjdUt+calcDeltat(tjdUt, iflag)/86400.0, iflag)
+	if err != nil {
+		return xx, name, err
+	}
+	// 未找到所需星历而改用其他星历时，ΔT也按实际的星历计算
+	if retflag&sefEphMask != epheflag {
+		xx, _, name, err = fixstar(star, tjdUt+calcDeltat(tjdUt, retflag)/86400.0, iflag)
+	}
+	return xx, name, err
+}
+
+// Fixstar2Mag 返回恒星star的星等和"传统名,拜耳名"形式的恒星名称，同swe_fixstar2_mag
+// 星等取自星表，star的写法见Fixstar2
+func Fixstar2Mag(star string) (Float64, string, error) {
+	if err := initializeSwissEph(); err != nil {
+		return 0, star, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	defer SetSweData(swed)
+	sstar, err := fixstarFormatSearchName(star)
+	if err != nil {
+		return 0, star, err
+	}
+	stardata, err := lookupStar(star, sstar)
+	if err != nil {
+		return 0, star, err
+	}
+	return stardata.Mag, stardata.Starname + "," + stardata.Starbayer, nil
+}
@@ -0,0 +1,184 @@
+// Gauquelin扇区：天体在周日运动中的位置，把升起到落下和落下到升起各分为18个扇区，
+// 由升起点起顺时针编号为1至36，同swe_gauquelin_sector。
+
+package ephgo
+
+import "fmt"
+
+// Gauquelin扇区的计算方法
+const (
+	GauqGeometric      = 0 // 由Placidus半弧（'G'宫位制）的宫位计算，考虑黄纬
+	GauqGeometricNoLat = 1 // 同上，黄纬取0
+	GauqCenterNoRefr   = 2 // 由圆面中心的出没时刻计算，不考虑折射
+	GauqCenter         = 3 // 由圆面中心的出没时刻计算，考虑折射
+	GauqLimbNoRefr     = 4 // 由圆面上缘的出没时刻计算，不考虑折射
+	GauqLimb           = 5 // 由圆面上缘的出没时刻计算，考虑折射
+)
+
+// GauquelinRecord 批量计算Gauquelin扇区的一条记录：时刻和观测地点
+type GauquelinRecord struct {
+	TjdUt   Float64    // 世界时儒略日
+	Geopos  [3]Float64 // 地理经度、纬度（度）和海拔（米）
+	Atpress Float64    // 气压（hPa），为0时按海拔估计
+	Attemp  Float64    // 气温（摄氏度）
+}
+
+// GauquelinSector 计算世界时tjdUt天体ipl或恒星starname的Gauquelin扇区位置，结果在1.0至37.0之间
+// starname不为空时计算恒星，忽略ipl。iflag为星历标志，可加SeflgTopoctr（只用于方法0和1）。
+// imeth为计算方法，见Gauq*：方法0和1是几何的，方法2至5由实际的出没时刻按时间比例内插，
+// atpress和attemp只用于考虑折射的方法3和5。拱极天体不升起或不落下时返回错误
+func GauquelinSector(tjdUt Float64, ipl int, starname string, iflag Int32, imeth int, geopos [3]Float64, atpress, attemp Float64) (Float64, error) {
+	if err := initializeSwissEph(); err != nil {
+		return 0, fmt.Errorf("初始化失败: %v", err)
+	}
+	swed := GetSweData()
+	defer SetSweData(swed)
+	g, err := newGauquelin(ipl, starname, iflag, imeth)
+	if err != nil {
+		return 0, err
+	}
+	return g.sector(tjdUt, geopos, atpress, attemp)
+}
+
+// GauquelinSectors 对一组记录计算同一天体或恒星的Gauquelin扇区，参数同GauquelinSector
+// 计算方法的检查、标志的设置和恒星的查找只做一次，适合大量出生记录的统计；
+// 某条记录出错时其结果为0，错误在对应的位置返回，不影响其他记录
+func GauquelinSectors(ipl int, starname string, iflag Int32, imeth int, recs []GauquelinRecord) ([]Float64, []error) {
+	dgsects := make([]Float64, len(recs))
+	errs := make([]error, len(recs))
+	if err := initializeSwissEph(); err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("初始化失败: %v", err)
+		}
+		return dgsects, errs
+	}
+	swed := GetSweData()
+	defer SetSweData(swed)
+	g, err := newGauquelin(ipl, starname, iflag, imeth)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return dgsects, errs
+	}
+	for i, r := range recs {
+		dgsects[i], errs[i] = g.sector(r.TjdUt, r.Geopos, r.Atpress, r.Attemp)
+	}
+	return dgsects, errs
+}
+
+// gauquelin 与时刻和地点无关的Gauquelin扇区参数
+type gauquelin struct {
+	ipl      int
+	star     *FixedStar // 恒星的记录，计算行星时为nil
+	iflag    Int32
+	imeth    int
+	epheflag Int32 // 出没计算的星历标志
+	risemeth Int32 // 出没计算的标志
+}
+
+// newGauquelin 检查计算方法、查找恒星并设置标志，参数见GauquelinSector
+func newGauquelin(ipl int, starname string, iflag Int32, imeth int) (*gauquelin, error) {
+	if imeth < GauqGeometric || imeth > GauqLimb {
+		return nil, fmt.Errorf("无效的Gauquelin扇区计算方法: %d", imeth)
+	}
+	// 冥王星作为小行星134340调用时按主要行星计算
+	if ipl == SeAstOffset+134340 {
+		ipl = SePluto
+	}
+	g := &gauquelin{ipl: ipl, iflag: iflag, imeth: imeth, epheflag: iflag & sefEphMask}
+	if starname != "" {
+		sstar, err := fixstarFormatSearchName(starname)
+		if err != nil {
+			return nil, err
+		}
+		stardata, err := lookupStar(starname, sstar)
+		if err != nil {
+			return nil, err
+		}
+		g.star = &stardata
+	}
+	if imeth == GauqCenterNoRefr || imeth == GauqLimbNoRefr {
+		g.risemeth |= bitNoRefraction
+	}
+	if imeth == GauqCenterNoRefr || imeth == GauqCenter {
+		g.risemeth |= bitDiscCenter
+	}
+	return g, nil
+}
+
+// sector 计算世界时tjdUt在地点geopos的Gauquelin扇区，见GauquelinSector
+func (g *gauquelin) sector(tjdUt Float64, geopos [3]Float64, atpress, attemp Float64) (Float64, error) {
+	ipl, iflag := g.ipl, g.iflag
+	// 由黄经和黄纬几何计算
+	if g.imeth == GauqGeometric || g.imeth == GauqGeometricNoLat {
+		tjdEt := tjdUt + calcDeltat(tjdUt, iflag)/86400.0
+		eps := epsiln(tjdEt, iflag) * RadToDeg
+		nutlo := nutation(tjdEt, iflag)
+		nutlo[0] *= RadToDeg
+		nutlo[1] *= RadToDeg
+		armc := degnorm(sidtime0(tjdUt, eps+nutlo[1], nutlo[0])*15 + geopos[0])
+		var x0 [6]Float64
+		var err error
+		if g.star != nil {
+			x0, _, _, err = fixstarData(g.star, tjdEt, iflag)
+		} else {
+			x0, _, err = calc(tjdEt, ipl, iflag)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if g.imeth == GauqGeometricNoLat {
+			x0[1] = 0
+		}
+		return HousePos(armc, geopos[1], eps+nutlo[1], 'G', x0[0], x0[1], 0)
+	}
+	// 由出没时刻计算
+	// next 求t以后的下一次升起或落下，found表示是否存在
+	next := func(t Float64, rs Int32) (Float64, bool, error) {
+		tret, retc, err := riseTrans(t, ipl, g.star, g.epheflag, rs|g.risemeth, geopos, atpress, attemp)
+		if retc == Err {
+			return 0, false, err
+		}
+		return tret, retc != NotAvailable, nil
+	}
+	// 下一次升起和落下
+	trise, riseFound, err := next(tjdUt, calcRise)
+	if err != nil {
+		return 0, err
+	}
+	tset, setFound, err := next(tjdUt, calcSet)
+	if err != nil {
+		return 0, err
+	}
+	aboveHorizon := false
+	switch {
+	case trise < tset && riseFound:
+		// 在地平线下，求上一次落下
+		t := tjdUt - 1.2
+		if setFound {
+			t = tset - 1.2
+		}
+		if tset, setFound, err = next(t, calcSet); err != nil {
+			return 0, err
+		}
+	case trise >= tset && setFound:
+		// 在地平线上，求上一次升起
+		aboveHorizon = true
+		t := tjdUt - 1.2
+		if riseFound {
+			t = trise - 1.2
+		}
+		if trise, riseFound, err = next(t, calcRise); err != nil {
+			return 0, err
+		}
+	}
+	// 拱极天体的出没可以用中天代替，但这并不总是可行，因此不做处理
+	if !riseFound || !setFound {
+		return 0, fmt.Errorf("未找到天体%d的出没", ipl)
+	}
+	if aboveHorizon {
+		return (tjdUt-trise)/(tset-trise)*18 + 1, nil
+	}
+	return (tjdUt-tset)/(trise-tset)*18 + 19, nil
+}
@@ -0,0 +1,2121 @@
+// 宫位计算：各种宫位制的宫头，以及上升点、天顶、宿命点等特殊点，移植自swehouse.c。
+//
+// 宫位制用一个字母表示，见HouseName。宫头数组的下标与宫位一致：cusps[1..12]，
+// Gauquelin扇区为cusps[1..36]，cusps[0]不用。特殊点数组ascmc的下标见SeAsc等常量。
+// 极圈内Placidus、Koch和Gauquelin无法计算，改用Porphyry宫头并返回错误。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// 特殊点在ascmc中的下标
+const (
+	SeAsc    = 0 // 上升点
+	SeMc     = 1 // 天顶
+	SeArmc   = 2 // 天顶的赤经（当地恒星时，度）
+	SeVertex = 3 // 宿命点
+	SeEquasc = 4 // 赤道上升点
+	SeCoasc1 = 5 // 协上升点（W. Koch）
+	SeCoasc2 = 6 // 协上升点（M. Munkasey）
+	SePolasc = 7 // 极上升点（M. Munkasey）
+	SeNascmc = 8 // 特殊点的个数
+)
+
+const (
+	housesVerySmall     = 1e-10
+	placidusIterSmall   = 1.0 / 360000.0 // Placidus迭代的收敛条件（度）
+	placidusIterMax     = 100            // Placidus迭代的最大次数
+	sunshineKeepMcSouth = false          // Sunshine宫位制中天顶在地平线以下时是否保持在南方
+	solarYear           = 365.24219893
+	armcSpeed           = (solarYear + 1) / solarYear * 360 // ARMC的速度（度/日）
+)
+
+// houses 宫位计算的中间结果，同C的struct houses
+// 速度（度/日）由asc1的导数解析地求出，interpol为真的宫位制需要由数值微分求宫头的速度
+type houses struct {
+	cusp        [37]Float64
+	cuspSpeed   [37]Float64
+	ac          Float64
+	acSpeed     Float64
+	mc          Float64
+	mcSpeed     Float64
+	vertex      Float64
+	vertexSpeed Float64
+	equasc      Float64
+	equascSpeed Float64
+	coasc1      Float64
+	coasc1Speed Float64
+	coasc2      Float64
+	coasc2Speed Float64
+	polasc      Float64
+	polascSpeed Float64
+	sundec      Float64 // 太阳赤纬，用于Sunshine宫位制
+	interpol    bool
+}
+
+// HouseResult 宫位计算的结果，包括宫头和特殊点的速度
+type HouseResult struct {
+	Cusps       []Float64   // 宫头，下标1..12，Gauquelin扇区为1..36
+	CuspSpeeds  []Float64   // 宫头的速度（度/日），下标同Cusps
+	Ascmc       [10]Float64 // 特殊点，下标见SeAsc等；Sunshine宫位制的Ascmc[9]为太阳赤纬
+	AscmcSpeeds [10]Float64 // 特殊点的速度（度/日）
+	Fallback    bool        // 所选宫位制无法计算（如极圈内的Placidus），Cusps的1..12为Porphyry宫头，长度不变
+}
+
+// 以度为单位的三角函数
+func sind(x Float64) Float64  { return math.Sin(x * DegToRad) }
+func cosd(x Float64) Float64  { return math.Cos(x * DegToRad) }
+func tand(x Float64) Float64  { return math.Tan(x * DegToRad) }
+func asind(x Float64) Float64 { return math.Asin(x) * RadToDeg }
+func acosd(x Float64) Float64 { return math.Acos(x) * RadToDeg }
+func atand(x Float64) Float64 { return math.Atan(x) * RadToDeg }
+
+// upperHsys 将宫位制字母转换为大写（'i'除外，它表示Sunshine的Makransky解法）
+func upperHsys(hsys byte) byte {
+	if hsys >= 'a' && hsys <= 'z' && hsys != 'i' {
+		return hsys - 32
+	}
+	return hsys
+}
+
+// isSunshine 判断hsys是否为Sunshine宫位制（'I'或'i'）
+func isSunshine(hsys byte) bool {
+	return hsys == 'I' || hsys == 'i'
+}
+
+// HouseName 返回宫位制hsys的名称，未知的字母返回"Placidus"
+func HouseName(hsys byte) string {
+	switch upperHsys(hsys) {
+	case 'A', 'E':
+		return "equal"
+	case 'B':
+		return "Alcabitius"
+	case 'C':
+		return "Campanus"
+	case 'D':
+		return "equal (MC)"
+	case 'F':
+		return "Carter poli-equ."
+	case 'G':
+		return "Gauquelin sectors"
+	case 'H':
+		return "horizon/azimut"
+	case 'I':
+		return "Sunshine"
+	case 'i':
+		return "Sunshine/alt."
+	case 'J':
+		return "Savard-A"
+	case 'K':
+		return "Koch"
+	case 'L':
+		return "Pullen SD"
+	case 'M':
+		return "Morinus"
+	case 'N':
+		return "equal/1=Aries"
+	case 'O':
+		return "Porphyry"
+	case 'Q':
+		return "Pullen SR"
+	case 'R':
+		return "Regiomontanus"
+	case 'S':
+		return "Sripati"
+	case 'T':
+		return "Polich/Page"
+	case 'U':
+		return "Krusinski-Pisa-Goelzer"
+	case 'V':
+		return "equal/Vehlow"
+	case 'W':
+		return "equal/ whole sign"
+	case 'X':
+		return "axial rotation system/Meridian houses"
+	case 'Y':
+		return "APC houses"
+	}
+	return "Placidus"
+}
+
+// Houses 计算世界时tjdUt、地理纬度geolat和经度geolon（度，东经、北纬为正）处的宫位
+// hsys为宫位制字母，见HouseName。返回宫头cusps（下标1..12，Gauquelin为1..36）
+// 和特殊点ascmc（下标见SeAsc等；Sunshine宫位制的ascmc[9]为太阳赤纬）。
+// 极圈内无法计算时返回Porphyry宫头和错误
+func Houses(tjdUt, geolat, geolon Float64, hsys byte) ([]Float64, [10]Float64, error) {
+	// ΔT使用默认的潮汐加速度
+	res, err := housesEx(tjdUt, 0, -1, geolat, geolon, hsys, false)
+	return res.Cusps, res.Ascmc, err
+}
+
+// HousesEx 同Houses，iflag可以包含SeflgSidereal（恒星黄道宫位，岁差由SetSidMode设定）、
+// SeflgNonut（不计章动）和SeflgRadians（结果为弧度）；ΔT与iflag中的星历一致
+func HousesEx(tjdUt Float64, iflag Int32, geolat, geolon Float64, hsys byte) ([]Float64, [10]Float64, error) {
+	res, err := housesEx(tjdUt, iflag, iflag, geolat, geolon, hsys, false)
+	return res.Cusps, res.Ascmc, err
+}
+
+// HousesEx2 同HousesEx，还计算宫头和特殊点的速度（度/日，SeflgRadians时为弧度/日）
+// 所选宫位制无法计算时返回错误，结果的Fallback为真，Cusps为Porphyry宫头
+func HousesEx2(tjdUt Float64, iflag Int32, geolat, geolon Float64, hsys byte) (HouseResult, error) {
+	return housesEx(tjdUt, iflag, iflag, geolat, geolon, hsys, true)
+}
+
+// HousesArmc 由天顶的赤经armc、地理纬度geolat和真黄赤交角eps（度）计算宫位，
+// 用于没有确定时刻的星盘（如组合盘、推运盘）。sundec为太阳赤纬（度），
+// 只用于Sunshine宫位制（'I'、'i'），其他宫位制忽略。结果同Houses
+func HousesArmc(armc, geolat, eps Float64, hsys byte, sundec Float64) ([]Float64, [10]Float64, error) {
+	res, err := housesArmc(armc, geolat, eps, hsys, sundec, false)
+	return res.Cusps, res.Ascmc, err
+}
+
+// HousesArmcEx2 同HousesArmc，还计算宫头和特殊点的速度（度/日），见HousesEx2
+func HousesArmcEx2(armc, geolat, eps Float64, hsys byte, sundec Float64) (HouseResult, error) {
+	return housesArmc(armc, geolat, eps, hsys, sundec, true)
+}
+
+// housesEx 计算世界时tjdUt的宫位，dtflag为计算ΔT所用的星历标志，speed为真时计算速度
+func housesEx(tjdUt Float64, iflag, dtflag Int32, geolat, geolon Float64, hsys byte, speed bool) (HouseResult, error) {
+	var sundec Float64
+	var errSun error
+	if isSunshine(hsys) {
+		sundec, errSun = sunDeclination(tjdUt)
+		if errSun != nil {
+			hsys = 'O'
+		}
+	}
+	swed := GetSweData()
+	tjde := tjdUt + calcDeltat(tjdUt, dtflag)/86400.0
+	sip := &swed.Sidd
+	if iflag&SeflgSidereal != 0 && !swed.AyanaIsSet {
+		setSidMode(SeSidmFaganBradley, 0, 0)
+	}
+	epsMean := epsiln(tjde, 0) * RadToDeg
+	var nutlo [2]Float64
+	if iflag&SeflgNonut == 0 {
+		nutlo = nutation(tjde, 0)
+		for i := range nutlo {
+			nutlo[i] *= RadToDeg
+		}
+	}
+	armc := degnorm(sidtime0(tjdUt, epsMean+nutlo[1], nutlo[0])*15 + geolon)
+	var res HouseResult
+	var err error
+	switch {
+	case iflag&SeflgSidereal == 0:
+		res, err = housesArmc(armc, geolat, epsMean+nutlo[1], hsys, sundec, speed)
+	case sip.SidMode&SeSidbitEclT0 != 0:
+		res, err = siderealHousesEclT0(tjde, armc, epsMean+nutlo[1], nutlo, geolat, hsys, sundec, speed)
+	case sip.SidMode&SeSidbitSsyPlane != 0:
+		res, err = siderealHousesSsypl(tjde, armc, epsMean+nutlo[1], nutlo, geolat, hsys, sundec, speed)
+	default:
+		res, err = siderealHousesTrad(tjde, iflag, armc, epsMean+nutlo[1], geolat, hsys, sundec, speed)
+	}
+	SetSweData(swed)
+	if iflag&SeflgRadians != 0 {
+		for i := 1; i < len(res.Cusps); i++ {
+			res.Cusps[i] *= DegToRad
+		}
+		for i := 1; i < len(res.CuspSpeeds); i++ {
+			res.CuspSpeeds[i] *= DegToRad
+		}
+		for i := 0; i < SeNascmc; i++ {
+			res.Ascmc[i] *= DegToRad
+			res.AscmcSpeeds[i] *= DegToRad
+		}
+	}
+	if errSun != nil {
+		res.Fallback = true
+		return res, errSun
+	}
+	return res, err
+}
+
+// sunDeclination 计算世界时tjdUt的太阳赤纬，用于Sunshine宫位制
+func sunDeclination(tjdUt Float64) (Float64, error) {
+	xp, err := CalcUT(tjdUt, SeSun, SeflgSpeed|SeflgEquatorial)
+	if err != nil {
+		return 0, fmt.Errorf("无法计算Sunshine宫位制所需的太阳赤纬，改用Porphyry宫位制: %v", err)
+	}
+	return xp[1], nil
+}
+
+// housesArmc 宫位计算的公共部分，同swe_houses_armc_ex2；speed为真时计算速度
+func housesArmc(armc, geolat, eps Float64, hsys byte, sundec Float64, speed bool) (HouseResult, error) {
+	var res HouseResult
+	ito := 12
+	if upperHsys(hsys) == 'G' {
+		ito = 36
+	}
+	armc = degnorm(armc)
+	h := houses{sundec: sundec}
+	if isSunshine(hsys) && (sundec < -24 || sundec > 24) {
+		return res, fmt.Errorf("Sunshine宫位制需要有效的太阳赤纬: %v", sundec)
+	}
+	err := calcH(armc, geolat, eps, hsys, &h)
+	// 失败时只有12个Porphyry宫头；Gauquelin扇区的长度不变，13..36为0
+	n := ito
+	if err != nil {
+		n = 12
+		res.Fallback = true
+	}
+	if speed && h.interpol {
+		h.interpolateSpeeds(armc, geolat, eps, hsys)
+	}
+	res.Cusps = make([]Float64, ito+1)
+	copy(res.Cusps[1:], h.cusp[1:n+1])
+	res.Ascmc[SeAsc] = h.ac
+	res.Ascmc[SeMc] = h.mc
+	res.Ascmc[SeArmc] = armc
+	res.Ascmc[SeVertex] = h.vertex
+	res.Ascmc[SeEquasc] = h.equasc
+	res.Ascmc[SeCoasc1] = h.coasc1
+	res.Ascmc[SeCoasc2] = h.coasc2
+	res.Ascmc[SePolasc] = h.polasc
+	if isSunshine(hsys) {
+		res.Ascmc[9] = h.sundec
+	}
+	if speed {
+		res.CuspSpeeds = make([]Float64, ito+1)
+		copy(res.CuspSpeeds[1:], h.cuspSpeed[1:n+1])
+		res.AscmcSpeeds[SeAsc] = h.acSpeed
+		res.AscmcSpeeds[SeMc] = h.mcSpeed
+		res.AscmcSpeeds[SeArmc] = armcSpeed
+		res.AscmcSpeeds[SeVertex] = h.vertexSpeed
+		res.AscmcSpeeds[SeEquasc] = h.equascSpeed
+		res.AscmcSpeeds[SeCoasc1] = h.coasc1Speed
+		res.AscmcSpeeds[SeCoasc2] = h.coasc2Speed
+		res.AscmcSpeeds[SePolasc] = h.polascSpeed
+	}
+	return res, err
+}
+
+// interpolateSpeeds 没有解析公式的宫位制由armc前后各1秒的宫头求速度
+// 上升点在其中一侧跳过180度时（极圈内），只用另一侧
+func (hsp *houses) interpolateSpeeds(armc, geolat, eps Float64, hsys byte) {
+	dt := 1.0 / 86400
+	darmc := dt * armcSpeed
+	hm1 := houses{sundec: hsp.sundec}
+	hp1 := houses{sundec: hsp.sundec}
+	if calcH(armc-darmc, geolat, eps, hsys, &hm1) != nil || calcH(armc+darmc, geolat, eps, hsys, &hp1) != nil {
+		return
+	}
+	if math.Abs(difdeg2n(hp1.ac, hsp.ac)) > 90 {
+		hp1 = *hsp
+		dt /= 2
+	} else if math.Abs(difdeg2n(hm1.ac, hsp.ac)) > 90 {
+		hm1 = *hsp
+		dt /= 2
+	}
+	for i := 1; i <= 12; i++ {
+		hsp.cuspSpeed[i] = difdeg2n(hp1.cusp[i], hm1.cusp[i]) / 2 / dt
+	}
+}
+
+// siderealHousesEclT0 投影到t0黄道的恒星黄道宫位：
+// 1. 求t0的平黄道与t的真赤道的交点（辅助春分点）和交角（辅助黄赤交角）；
+// 2. 求辅助春分点与t的春分点在赤道上的距离；
+// 3. 从armc中减去此距离，得到辅助armc；
+// 4. 用辅助armc和辅助黄赤交角计算宫位；
+// 5. 求辅助春分点与t0的春分点在t0黄道上的距离（t时刻在t0黄道上量度的岁差）；
+// 6. 从宫头中减去此距离和t0的岁差
+func siderealHousesEclT0(tjde, armc, eps Float64, nutlo [2]Float64, lat Float64, hsys byte, sundec Float64, speed bool) (HouseResult, error) {
+	var x, xvpx, x2, xnorm [6]Float64
+	sip := &swed.Sidd
+	// t0的黄赤交角
+	epst0 := epsiln(sip.T0, 0)
+	// 在t0平黄道上运动的假想天体，取其位于春分点时的直角坐标
+	x[0], x[4] = 1, 1
+	// 转换到赤道
+	coortrf(x[:], x[:], -epst0)
+	coortrf(x[3:], x[3:], -epst0)
+	// 岁差换算到tjde
+	precess(x[:], sip.T0, 0, jToJ2000)
+	precess(x[:], tjde, 0, j2000ToJ)
+	precess(x[3:], sip.T0, 0, jToJ2000)
+	precess(x[3:], tjde, 0, j2000ToJ)
+	// 转换到tjde的真赤道
+	coortrf(x[:], x[:], (eps-nutlo[1])*DegToRad)
+	coortrf(x[3:], x[3:], (eps-nutlo[1])*DegToRad)
+	cartpolSp(x[:], x[:])
+	x[0] += nutlo[0] * DegToRad
+	polcartSp(x[:], x[:])
+	coortrf(x[:], x[:], -eps*DegToRad)
+	coortrf(x[3:], x[3:], -eps*DegToRad)
+	// 辅助黄赤交角
+	crossProd(x[:], x[3:], xnorm[:])
+	rxy := xnorm[0]*xnorm[0] + xnorm[1]*xnorm[1]
+	rxyz := math.Sqrt(rxy + xnorm[2]*xnorm[2])
+	rxy = math.Sqrt(rxy)
+	epsx := math.Asin(rxy/rxyz) * RadToDeg
+	// 辅助春分点
+	if math.Abs(x[5]) < 1e-15 {
+		x[5] = 1e-15
+	}
+	fac := x[2] / x[5]
+	sgn := x[5] / math.Abs(x[5])
+	for j := 0; j <= 2; j++ {
+		xvpx[j] = (x[j] - fac*x[j+3]) * sgn
+	}
+	// 辅助春分点与tjde的春分点在赤道上的距离
+	cartpol(xvpx[:], x2[:])
+	dvpx := x2[0] * RadToDeg
+	// 辅助armc
+	armcx := degnorm(armc - dvpx)
+	res, err := housesArmc(armcx, lat, epsx, hsys, sundec, speed)
+	if res.Cusps == nil {
+		return res, err
+	}
+	cusps := res.Cusps
+	// 辅助春分点与t0的春分点在t0黄道上的距离
+	dvpxe := math.Acos(dotProdUnit(x[:], xvpx[:])) * RadToDeg
+	if tjde < sip.T0 {
+		dvpxe = -dvpxe
+	}
+	for i := 1; i < len(cusps); i++ {
+		cusps[i] = degnorm(cusps[i] - dvpxe - sip.AyanT0)
+	}
+	for i := 0; i < SeNascmc; i++ {
+		if i == SeArmc {
+			continue
+		}
+		res.Ascmc[i] = degnorm(res.Ascmc[i] - dvpxe - sip.AyanT0)
+	}
+	// 第1宫始于白羊座0度
+	if hsys == 'N' {
+		for i := 1; i < len(cusps); i++ {
+			cusps[i] = Float64(i-1) * 30
+		}
+	}
+	return res, err
+}
+
+// siderealHousesSsypl 投影到太阳系不变平面的恒星黄道宫位，
+// 方法同siderealHousesEclT0，以太阳系不变平面代替t0的黄道，
+// 最后还要减去J2000的零点在不变平面上相对于t0的岁差
+func siderealHousesSsypl(tjde, armc, eps Float64, nutlo [2]Float64, lat Float64, hsys byte, sundec Float64, speed bool) (HouseResult, error) {
+	var x, x0, xvpx, x2, xnorm [6]Float64
+	sip := &swed.Sidd
+	eps2000 := epsiln(J2000, 0)
+	// 太阳系不变平面上零点的直角坐标
+	x[0], x[4] = 1, 1
+	// 转换到J2000黄道
+	coortrf(x[:], x[:], -ssyPlaneIncl)
+	coortrf(x[3:], x[3:], -ssyPlaneIncl)
+	cartpolSp(x[:], x[:])
+	x[0] += ssyPlaneNodeE2000
+	polcartSp(x[:], x[:])
+	// 转换到J2000赤道
+	coortrf(x[:], x[:], -eps2000)
+	coortrf(x[3:], x[3:], -eps2000)
+	// 岁差换算到tjde的平赤道
+	precess(x[:], tjde, 0, j2000ToJ)
+	precess(x[3:], tjde, 0, j2000ToJ)
+	// 转换到tjde的真赤道
+	coortrf(x[:], x[:], (eps-nutlo[1])*DegToRad)
+	coortrf(x[3:], x[3:], (eps-nutlo[1])*DegToRad)
+	cartpolSp(x[:], x[:])
+	x[0] += nutlo[0] * DegToRad
+	polcartSp(x[:], x[:])
+	coortrf(x[:], x[:], -eps*DegToRad)
+	coortrf(x[3:], x[3:], -eps*DegToRad)
+	// 辅助黄赤交角
+	crossProd(x[:], x[3:], xnorm[:])
+	rxy := xnorm[0]*xnorm[0] + xnorm[1]*xnorm[1]
+	rxyz := math.Sqrt(rxy + xnorm[2]*xnorm[2])
+	rxy = math.Sqrt(rxy)
+	epsx := math.Asin(rxy/rxyz) * RadToDeg
+	// 辅助春分点
+	if math.Abs(x[5]) < 1e-15 {
+		x[5] = 1e-15
+	}
+	fac := x[2] / x[5]
+	sgn := x[5] / math.Abs(x[5])
+	for j := 0; j <= 2; j++ {
+		xvpx[j] = (x[j] - fac*x[j+3]) * sgn
+	}
+	// 辅助春分点与tjde的平春分点在赤道上的距离
+	cartpol(xvpx[:], x2[:])
+	dvpx := x2[0] * RadToDeg
+	// 辅助armc
+	armcx := degnorm(armc - dvpx)
+	res, err := housesArmc(armcx, lat, epsx, hsys, sundec, speed)
+	if res.Cusps == nil {
+		return res, err
+	}
+	cusps := res.Cusps
+	// 辅助春分点与J2000的恒星零点在不变平面上的距离（公元前5400年以后总为正）
+	dvpxe := math.Acos(dotProdUnit(x[:], xvpx[:])) * RadToDeg
+	dvpxe -= ssyPlaneNode * RadToDeg
+	// t0的零点在J2000坐标系中的位置
+	x0[0] = 1
+	if sip.T0 != J2000 {
+		precess(x0[:], sip.T0, 0, jToJ2000)
+	}
+	// 转换到J2000黄道，再到不变平面
+	coortrf(x0[:], x0[:], eps2000)
+	cartpol(x0[:], x0[:])
+	x0[0] -= ssyPlaneNodeE2000
+	polcart(x0[:], x0[:])
+	coortrf(x0[:], x0[:], ssyPlaneIncl)
+	cartpol(x0[:], x0[:])
+	x0[0] += ssyPlaneNode
+	x00 := x0[0] * RadToDeg
+	for i := 1; i < len(cusps); i++ {
+		cusps[i] = degnorm(cusps[i] - dvpxe - sip.AyanT0 - x00)
+	}
+	for i := 0; i < SeNascmc; i++ {
+		if i == SeArmc {
+			continue
+		}
+		res.Ascmc[i] = degnorm(res.Ascmc[i] - dvpxe - sip.AyanT0 - x00)
+	}
+	// 第1宫始于白羊座0度
+	if hsys == 'N' {
+		for i := 1; i < len(cusps); i++ {
+			cusps[i] = Float64(i-1) * 30
+		}
+	}
+	return res, err
+}
+
+// siderealHousesTrad 传统的恒星黄道宫位：按回归黄道计算宫位，再减去岁差（含章动）
+func siderealHousesTrad(tjde Float64, iflag Int32, armc, eps, lat Float64, hsys byte, sundec Float64, speed bool) (HouseResult, error) {
+	ay, _, err := getAyanamsaExNut(tjde, iflag)
+	if err != nil {
+		return HouseResult{}, err
+	}
+	ihs := upperHsys(hsys)
+	ihs2 := ihs
+	// 整宫制按等宫计算，然后取整到星座的起点
+	if ihs == 'W' {
+		ihs2 = 'E'
+	}
+	res, err := housesArmc(armc, lat, eps, ihs2, sundec, speed)
+	if res.Cusps == nil {
+		return res, err
+	}
+	cusps := res.Cusps
+	for i := 1; i < len(cusps); i++ {
+		cusps[i] = degnorm(cusps[i] - ay)
+		if ihs == 'W' {
+			cusps[i] -= math.Mod(cusps[i], 30)
+		}
+	}
+	// 第1宫始于白羊座0度
+	if ihs == 'N' {
+		for i := 1; i < len(cusps); i++ {
+			cusps[i] = Float64(i-1) * 30
+		}
+	}
+	for i := 0; i < SeNascmc; i++ {
+		if i == SeArmc {
+			continue
+		}
+		res.Ascmc[i] = degnorm(res.Ascmc[i] - ay)
+	}
+	return res, err
+}
+
+// apcSector APC宫位制第n宫的宫头（度）
+// ph为地理纬度，e为黄赤交角，az为armc，均为弧度
+func apcSector(n int, ph, e, az Float64) Float64 {
+	var kv, dasc Float64
+	// kv为上升点的上升差，dasc为上升点的赤纬
+	if math.Abs(ph*RadToDeg) <= 90-housesVerySmall {
+		kv = math.Atan(math.Tan(ph) * math.Tan(e) * math.Cos(az) / (1 + math.Tan(ph)*math.Tan(e)*math.Sin(az)))
+		if math.Abs(ph*RadToDeg) < housesVerySmall {
+			dasc = (90 - housesVerySmall) * DegToRad
+			if ph < 0 {
+				dasc = -dasc
+			}
+		} else {
+			dasc = math.Atan(math.Sin(kv) / math.Tan(ph))
+		}
+	}
+	// 极圈内天顶沉到地平线以下时，kv和dasc改变符号，上升点跳过180度，正合所需
+	var k int
+	isBelowHor := n < 8
+	if isBelowHor {
+		// 包括第1宫和第7宫
+		k = n - 1
+	} else {
+		k = n - 13
+	}
+	// az + π/2 + kv为上升点的赤经，π/2 ± kv为上升点的半夜弧或半昼弧；
+	// a为宫头在APC圈（过上升点、赤纬为dasc的赤纬圈）上的赤经
+	var a Float64
+	if isBelowHor {
+		a = kv + az + Pi/2 + Float64(k)*(Pi/2-kv)/3
+	} else {
+		a = kv + az + Pi/2 + Float64(k)*(Pi/2+kv)/3
+	}
+	a = radnorm(a)
+	dret := math.Atan2(math.Tan(dasc)*math.Tan(ph)*math.Sin(az)+math.Sin(a),
+		math.Cos(e)*(math.Tan(dasc)*math.Tan(ph)*math.Cos(az)+math.Cos(a))+math.Sin(e)*math.Tan(ph)*math.Sin(az-a))
+	return degnorm(dret * RadToDeg)
+}
+
+// raToEcl 求赤道上赤经为ra（度）的点沿赤经圈投影到黄道上的黄经，cose为黄赤交角的余弦
+func raToEcl(ra, cose Float64) Float64 {
+	if math.Abs(ra-90) <= housesVerySmall {
+		return 90
+	}
+	if math.Abs(ra-270) <= housesVerySmall {
+		return 270
+	}
+	l := atand(tand(ra) / cose)
+	if ra > 90 && ra <= 270 {
+		l = degnorm(l + 180)
+	}
+	return degnorm(l)
+}
+
+// calcH 由恒星时th（度）、地理纬度fi和黄赤交角ekl计算宫位制hsy的宫头和特殊点
+// 极圈内Koch、Placidus和Gauquelin无法计算，改用Porphyry宫位制并返回错误；
+// 天顶与上升点的顺序不对时（极圈内天顶在地平线以下），交换上升点与下降点
+func calcH(th, fi, ekl Float64, hsy byte, hsp *houses) error {
+	var err error
+	cose := cosd(ekl)
+	sine := sind(ekl)
+	tane := tand(ekl)
+	// 南北极
+	if math.Abs(math.Abs(fi)-90) < housesVerySmall {
+		if fi < 0 {
+			fi = -90 + housesVerySmall
+		} else {
+			fi = 90 - housesVerySmall
+		}
+	}
+	tanfi := tand(fi)
+	polar := math.Abs(fi) >= 90-ekl
+	hsp.mc = raToEcl(th, cose)
+	// 上升点：地平圈的极高为地理纬度，它与赤道相交于th + 90
+	hsp.ac = asc1(th+90, fi, sine, cose)
+	hsp.mcSpeed = ascDash(th, 0, sine, cose)
+	hsp.acSpeed = ascDash(th+90, fi, sine, cose)
+	hsp.cuspSpeed = [37]Float64{}
+	hsp.interpol = false
+	// 以下某些宫位制会修改cusp[1]和cusp[10]
+	hsp.cusp[1] = hsp.ac
+	hsp.cusp[10] = hsp.mc
+	hsp.cuspSpeed[1] = hsp.acSpeed
+	hsp.cuspSpeed[10] = hsp.mcSpeed
+	// 小写字母已不推荐使用（'i'除外），视为大写
+	hsy = upperHsys(hsy)
+	switch hsy {
+	case 'A', 'E':
+		// 等宫制
+		hsp.swapAcPolar(true)
+		for i := 2; i <= 12; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[1] + Float64(i-1)*30)
+		}
+		hsp.setCuspSpeeds(hsp.acSpeed)
+	case 'D':
+		// 等宫制，从天顶开始
+		hsp.swapAcPolar(false)
+		hsp.cusp[10] = hsp.mc
+		for i := 11; i <= 12; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[10] + Float64(i-10)*30)
+		}
+		for i := 1; i <= 9; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[10] + Float64(i+2)*30)
+		}
+		hsp.setCuspSpeeds(hsp.mcSpeed)
+	case 'C':
+		// Campanus：卯酉圈三等分，过南北点和分点的大圆与黄道的交点为宫头
+		fh1 := asind(sind(fi) / 2)
+		fh2 := asind(math.Sqrt(3.0) / 2 * sind(fi))
+		xh1, xh2 := campanusOffsets(fi)
+		hsp.cusp[11] = asc1(th+90-xh1, fh1, sine, cose)
+		hsp.cusp[12] = asc1(th+90-xh2, fh2, sine, cose)
+		hsp.cusp[2] = asc1(th+90+xh2, fh2, sine, cose)
+		hsp.cusp[3] = asc1(th+90+xh1, fh1, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(th+90-xh1, fh1, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(th+90-xh2, fh2, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(th+90+xh2, fh2, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(th+90+xh1, fh1, sine, cose)
+		if polar {
+			hsp.swapPolar(false)
+		}
+	case 'H':
+		// 地平宫位制，计算同Campanus，以地平坐标代替赤道坐标
+		if fi > 0 {
+			fi = 90 - fi
+		} else {
+			fi = -90 - fi
+		}
+		// 赤道
+		if math.Abs(math.Abs(fi)-90) < housesVerySmall {
+			if fi < 0 {
+				fi = -90 + housesVerySmall
+			} else {
+				fi = 90 - housesVerySmall
+			}
+		}
+		th = degnorm(th + 180)
+		fh1 := asind(sind(fi) / 2)
+		fh2 := asind(math.Sqrt(3.0) / 2 * sind(fi))
+		xh1, xh2 := campanusOffsets(fi)
+		hsp.cusp[11] = asc1(th+90-xh1, fh1, sine, cose)
+		hsp.cusp[12] = asc1(th+90-xh2, fh2, sine, cose)
+		hsp.cusp[1] = asc1(th+90, fi, sine, cose)
+		hsp.cusp[2] = asc1(th+90+xh2, fh2, sine, cose)
+		hsp.cusp[3] = asc1(th+90+xh1, fh1, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(th+90-xh1, fh1, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(th+90-xh2, fh2, sine, cose)
+		hsp.cuspSpeed[1] = ascDash(th+90, fi, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(th+90+xh2, fh2, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(th+90+xh1, fh1, sine, cose)
+		if math.Abs(fi) >= 90-ekl {
+			hsp.swapPolar(false)
+		}
+		for i := 1; i <= 3; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[i] + 180)
+		}
+		for i := 11; i <= 12; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[i] + 180)
+		}
+		// 恢复fi和th
+		if fi > 0 {
+			fi = 90 - fi
+		} else {
+			fi = -90 - fi
+		}
+		th = degnorm(th + 180)
+		hsp.swapAcPolar(false)
+	case 'I', 'i':
+		// Sunshine宫位制：I为Treindl解法，i为Makransky解法
+		if difdeg2n(hsp.ac, hsp.mc) < 0 {
+			hsp.ac = degnorm(hsp.ac + 180)
+			hsp.cusp[1] = hsp.ac
+			if !sunshineKeepMcSouth && hsy == 'I' {
+				hsp.mc = degnorm(hsp.mc + 180)
+				hsp.cusp[10] = hsp.mc
+			}
+		}
+		hsp.cusp[4] = degnorm(hsp.cusp[10] + 180)
+		hsp.cusp[7] = degnorm(hsp.cusp[1] + 180)
+		var ok bool
+		if hsy == 'I' {
+			ok = sunshineSolutionTreindl(th, fi, ekl, hsp)
+		} else {
+			ok = sunshineSolutionMakransky(th, fi, ekl, hsp)
+		}
+		if !ok {
+			err = fmt.Errorf("极圈内无法计算，改用Porphyry宫位制")
+			hsy = 'O'
+			hsp.porphyry()
+			break
+		}
+		hsp.interpol = true
+	case 'J':
+		// Savard-A：纬度圈2/3 fi和1/3 fi与卯酉圈的交点为第11、12宫的点
+		sinfi := sind(fi)
+		cosfi := cosd(fi)
+		var xs1, xs2 Float64
+		if math.Abs(fi) < housesVerySmall {
+			xs2 = 1 / 3.0
+			xs1 = 2 / 3.0
+		} else {
+			xs2 = sind(fi/3) / sinfi
+			xs1 = sind(2*fi/3) / sinfi
+		}
+		xs2 = asind(xs2)
+		xs1 = asind(xs1)
+		var xh1, xh2 Float64
+		if cosfi == 0 {
+			if fi > 0 {
+				xh1, xh2 = 90, 90
+			} else {
+				xh1, xh2 = 270, 270
+			}
+		} else {
+			xh1 = atand(tand(xs1) / cosfi)
+			xh2 = atand(tand(xs2) / cosfi)
+		}
+		// 极高
+		fh1 := asind(sind(fi) * sind(90-xs1))
+		fh2 := asind(sind(fi) * sind(90-xs2))
+		hsp.cusp[12] = asc1(th+90-xh2, fh2, sine, cose)
+		hsp.cusp[11] = asc1(th+90-xh1, fh1, sine, cose)
+		hsp.cusp[2] = asc1(th+90+xh2, fh2, sine, cose)
+		hsp.cusp[3] = asc1(th+90+xh1, fh1, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(th+90-xh2, fh2, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(th+90-xh1, fh1, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(th+90+xh2, fh2, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(th+90+xh1, fh1, sine, cose)
+		if polar {
+			hsp.swapPolar(false)
+		}
+	case 'K':
+		// Koch
+		if polar {
+			err = fmt.Errorf("极圈内无法计算，改用Porphyry宫位制")
+			hsp.porphyry()
+			break
+		}
+		sina := sind(hsp.mc) * sine / cosd(fi)
+		if sina > 1 {
+			sina = 1
+		}
+		if sina < -1 {
+			sina = -1
+		}
+		cosa := math.Sqrt(1 - sina*sina)
+		c := atand(tanfi / cosa)
+		ad3 := asind(sind(c)*sina) / 3.0
+		hsp.cusp[11] = asc1(th+30-2*ad3, fi, sine, cose)
+		hsp.cusp[12] = asc1(th+60-ad3, fi, sine, cose)
+		hsp.cusp[2] = asc1(th+120+ad3, fi, sine, cose)
+		hsp.cusp[3] = asc1(th+150+2*ad3, fi, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(th+30-2*ad3, fi, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(th+60-ad3, fi, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(th+120+ad3, fi, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(th+150+2*ad3, fi, sine, cose)
+	case 'L':
+		// Pullen SD（正弦差），原名Neo-Porphyry
+		acmc := hsp.swapAcPolar(true)
+		q1 := 180 - acmc
+		d := (acmc - 90) / 4.0
+		if acmc <= 30 {
+			// 象限不超过30度时，第11宫的宽度为0
+			hsp.cusp[11] = degnorm(hsp.mc + acmc/2)
+			hsp.cusp[12] = hsp.cusp[11]
+		} else {
+			hsp.cusp[11] = degnorm(hsp.mc + 30 + d)
+			hsp.cusp[12] = degnorm(hsp.mc + 60 + 3*d)
+		}
+		d = (q1 - 90) / 4.0
+		if q1 <= 30 {
+			// 象限不超过30度时，第2宫的宽度为0
+			hsp.cusp[2] = degnorm(hsp.ac + q1/2)
+			hsp.cusp[3] = hsp.cusp[2]
+		} else {
+			hsp.cusp[2] = degnorm(hsp.ac + 30 + d)
+			hsp.cusp[3] = degnorm(hsp.ac + 60 + 3*d)
+		}
+		hsp.interpol = true
+	case 'N':
+		// 整宫制，第1宫始于白羊座0度
+		hsp.swapAcPolar(false)
+		for i := 1; i <= 12; i++ {
+			hsp.cusp[i] = Float64(i-1) * 30.0
+		}
+		hsp.setCuspSpeeds(0)
+	case 'O':
+		hsp.porphyry()
+	case 'Q':
+		// Pullen SR（正弦比）
+		third := 1.0 / 3.0
+		two23 := math.Pow(2.0*2.0, third)
+		acmc := hsp.swapAcPolar(true)
+		q := acmc
+		if q > 90 {
+			q = 180 - q
+		}
+		var x, xr, xr3, xr4 Float64
+		if q < 1e-30 {
+			// 象限为0的退化情形
+			xr4 = 180
+		} else {
+			c := (180 - q) / q
+			csq := c * c
+			ccr := math.Pow(csq-c, third)
+			cqx := math.Sqrt(two23*ccr + 1.0)
+			r1 := 0.5 * cqx
+			r2 := 0.5 * math.Sqrt(-2*(1-2*c)/cqx-two23*ccr+2)
+			r := r1 + r2 - 0.5
+			x = q / (2*r + 1)
+			xr = r * x
+			xr3 = xr * r * r
+			xr4 = xr3 * r
+		}
+		if acmc > 90 {
+			hsp.cusp[11] = degnorm(hsp.mc + xr3)
+			hsp.cusp[12] = degnorm(hsp.cusp[11] + xr4)
+			hsp.cusp[2] = degnorm(hsp.ac + xr)
+			hsp.cusp[3] = degnorm(hsp.cusp[2] + x)
+		} else {
+			hsp.cusp[11] = degnorm(hsp.mc + xr)
+			hsp.cusp[12] = degnorm(hsp.cusp[11] + x)
+			hsp.cusp[2] = degnorm(hsp.ac + xr3)
+			hsp.cusp[3] = degnorm(hsp.cusp[2] + xr4)
+		}
+		hsp.interpol = true
+	case 'R':
+		// Regiomontanus
+		fh1 := atand(tanfi * 0.5)
+		fh2 := atand(tanfi * cosd(30))
+		hsp.cusp[11] = asc1(30+th, fh1, sine, cose)
+		hsp.cusp[12] = asc1(60+th, fh2, sine, cose)
+		hsp.cusp[2] = asc1(120+th, fh2, sine, cose)
+		hsp.cusp[3] = asc1(150+th, fh1, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(30+th, fh1, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(60+th, fh2, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(120+th, fh2, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(150+th, fh1, sine, cose)
+		if polar {
+			hsp.swapPolar(false)
+		}
+	case 'S':
+		// Sripati：Porphyry宫的中点为宫头
+		acmc := hsp.swapAcPolar(false)
+		q1 := 180 - acmc
+		s1 := q1 / 3.0
+		s4 := acmc / 3.0
+		hsp.cusp[1] = degnorm(hsp.ac - s4*0.5)
+		hsp.cusp[2] = degnorm(hsp.ac + s1*0.5)
+		hsp.cusp[3] = degnorm(hsp.ac + s1*1.5)
+		hsp.cusp[10] = degnorm(hsp.mc - s1*0.5)
+		hsp.cusp[11] = degnorm(hsp.mc + s4*0.5)
+		hsp.cusp[12] = degnorm(hsp.mc + s4*1.5)
+		hsp.interpol = true
+	case 'T':
+		// Polich/Page（“拓扑中心”）
+		fh1 := atand(tanfi / 3.0)
+		fh2 := atand(tanfi * 2.0 / 3.0)
+		hsp.cusp[11] = asc1(30+th, fh1, sine, cose)
+		hsp.cusp[12] = asc1(60+th, fh2, sine, cose)
+		hsp.cusp[2] = asc1(120+th, fh2, sine, cose)
+		hsp.cusp[3] = asc1(150+th, fh1, sine, cose)
+		hsp.cuspSpeed[11] = ascDash(30+th, fh1, sine, cose)
+		hsp.cuspSpeed[12] = ascDash(60+th, fh2, sine, cose)
+		hsp.cuspSpeed[2] = ascDash(120+th, fh2, sine, cose)
+		hsp.cuspSpeed[3] = ascDash(150+th, fh1, sine, cose)
+		if polar {
+			hsp.swapPolar(true)
+		}
+	case 'V':
+		// Vehlow等宫制：上升点在第1宫的中间
+		hsp.swapAcPolar(false)
+		hsp.cusp[1] = degnorm(hsp.ac - 15)
+		for i := 2; i <= 12; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[1] + Float64(i-1)*30)
+		}
+		hsp.setCuspSpeeds(hsp.acSpeed)
+	case 'W':
+		// 整宫制
+		hsp.swapAcPolar(true)
+		hsp.cusp[1] = hsp.ac - math.Mod(hsp.ac, 30)
+		for i := 2; i <= 12; i++ {
+			hsp.cusp[i] = degnorm(hsp.cusp[1] + Float64(i-1)*30)
+		}
+		// 宫头在星座的起点，不随时间连续移动
+		hsp.setCuspSpeeds(0)
+	case 'X':
+		// 子午线宫位制：赤经为armc + n * 30的黄道点
+		a := th
+		for i := 1; i <= 12; i++ {
+			j := i + 10
+			if j > 12 {
+				j -= 12
+			}
+			a = degnorm(a + 30)
+			hsp.cusp[j] = raToEcl(a, cose)
+		}
+		hsp.swapAcPolar(false)
+		hsp.interpol = true
+	case 'M':
+		// Morinus：赤道上的点armc + n * 30换算为黄道坐标
+		var x [3]Float64
+		a := th
+		for i := 1; i <= 12; i++ {
+			j := i + 10
+			if j > 12 {
+				j -= 12
+			}
+			a = degnorm(a + 30)
+			x[0] = a
+			x[1] = 0
+			cotrans(x[:], x[:], ekl)
+			hsp.cusp[j] = x[0]
+		}
+		hsp.swapAcPolar(false)
+		hsp.interpol = true
+	case 'F':
+		// Carter赤道宫位制：从上升点的赤经起，赤道每30度的赤经圈与黄道的交点为宫头
+		var x [3]Float64
+		hsp.swapAcPolar(true)
+		x[0] = hsp.ac
+		x[1] = 0
+		cotrans(x[:], x[:], -ekl)
+		a := x[0]
+		for i := 2; i <= 12; i++ {
+			if i <= 3 || i >= 10 {
+				hsp.cusp[i] = raToEcl(degnorm(a+Float64(i-1)*30), cose)
+			}
+		}
+		hsp.interpol = true
+	case 'B':
+		// Alcabitius：上升点的半昼弧和半夜弧三等分，沿赤经圈投影到黄道
+		hsp.swapAcPolar(true)
+		dek := asind(sind(hsp.ac) * sine)
+		// 南北极的情形已在前面处理
+		r := -tanfi * tand(dek)
+		// |r| > 1的情形极少发生
+		if r > 1 {
+			r = 1
+		}
+		if r < -1 {
+			r = -1
+		}
+		sda := acosd(r)
+		sna := 180 - sda
+		sd3 := sda / 3
+		sn3 := sna / 3
+		hsp.cusp[11] = asc1(degnorm(th+sd3), 0, sine, cose)
+		hsp.cusp[12] = asc1(degnorm(th+2*sd3), 0, sine, cose)
+		hsp.cusp[2] = asc1(degnorm(th+180-2*sn3), 0, sine, cose)
+		hsp.cusp[3] = asc1(degnorm(th+180-sn3), 0, sine, cose)
+		hsp.interpol = true
+	case 'G':
+		// 36个Gauquelin扇区
+		if err = hsp.gauquelin(th, fi, tanfi, sine, cose, tane, polar); err != nil {
+			hsy = 'O'
+			hsp.porphyry()
+		}
+	case 'U':
+		// Krusinski-Pisa-Goelzer：过上升点和天顶的大圆十二等分，
+		// 分点沿赤经圈投影到黄道
+		hsp.swapAcPolar(false)
+		var x [3]Float64
+		// 上升点的黄道坐标
+		x[0] = hsp.ac
+		x[1] = 0.0
+		x[2] = 1.0
+		// 转换为赤道坐标，旋转，再转换为地平坐标
+		cotrans(x[:], x[:], -ekl)
+		x[0] = x[0] - (th - 90)
+		cotrans(x[:], x[:], -(90 - fi))
+		// 保存上升点在地平圈上的经度，以便转换回来
+		krHorizonLon := x[0]
+		x[0] = x[0] - x[0]
+		// 转换到上升点—天顶大圆
+		cotrans(x[:], x[:], -90)
+		for i := 0; i < 6; i++ {
+			// 第i+1宫的点，第10宫用于检验
+			x[0] = 30.0 * Float64(i)
+			x[1] = 0.0
+			cotrans(x[:], x[:], 90)
+			x[0] = x[0] + krHorizonLon
+			cotrans(x[:], x[:], 90-fi)
+			// 宫头的赤经
+			x[0] = degnorm(x[0] + (th - 90))
+			hsp.cusp[i+1] = atand(tand(x[0]) / cosd(ekl))
+			if x[0] > 90 && x[0] <= 270 {
+				hsp.cusp[i+1] = degnorm(hsp.cusp[i+1] + 180)
+			}
+			hsp.cusp[i+1] = degnorm(hsp.cusp[i+1])
+			hsp.cusp[i+7] = degnorm(hsp.cusp[i+1] + 180)
+		}
+		// 此宫位制没有解析的速度公式，用数值微分
+		hsp.interpol = true
+	case 'Y':
+		// APC宫位制
+		for i := 1; i <= 12; i++ {
+			hsp.cusp[i] = apcSector(i, fi*DegToRad, ekl*DegToRad, th*DegToRad)
+		}
+		// apcSector在纬度90度附近给出的天顶不准确
+		hsp.cusp[10] = hsp.mc
+		hsp.cusp[4] = degnorm(hsp.mc + 180)
+		if polar {
+			hsp.swapPolar(true)
+		}
+		hsp.interpol = true
+	default:
+		// Placidus
+		if err = hsp.placidus(th, fi, tanfi, sine, cose, tane, polar); err != nil {
+			hsp.porphyry()
+		}
+	}
+	// 数值上无法计算的情形（如两极的Sunshine宫位制），改用Porphyry宫位制
+	if err == nil && hsp.hasNaN() {
+		err = fmt.Errorf("宫头无法计算，改用Porphyry宫位制")
+		hsy = 'O'
+		hsp.interpol = false
+		hsp.porphyry()
+	}
+	if hsy != 'G' && hsy != 'Y' && hsy != 'I' && hsy != 'i' {
+		hsp.cusp[4] = degnorm(hsp.cusp[10] + 180)
+		hsp.cusp[5] = degnorm(hsp.cusp[11] + 180)
+		hsp.cusp[6] = degnorm(hsp.cusp[12] + 180)
+		hsp.cusp[7] = degnorm(hsp.cusp[1] + 180)
+		hsp.cusp[8] = degnorm(hsp.cusp[2] + 180)
+		hsp.cusp[9] = degnorm(hsp.cusp[3] + 180)
+		if !hsp.interpol {
+			// 对宫的速度相同
+			for i := 4; i <= 9; i++ {
+				hsp.cuspSpeed[i] = hsp.cuspSpeed[(i+5)%12+1]
+			}
+		}
+	}
+	hsp.specialPoints(th, fi, ekl, sine, cose)
+	return err
+}
+
+// specialPoints 计算宿命点、赤道上升点、协上升点和极上升点
+func (hsp *houses) specialPoints(th, fi, ekl, sine, cose Float64) {
+	// 宿命点
+	var f Float64
+	if fi >= 0 {
+		f = 90 - fi
+	} else {
+		f = -90 - fi
+	}
+	hsp.vertex = asc1(th-90, f, sine, cose)
+	hsp.vertexSpeed = ascDash(th-90, f, sine, cose)
+	// 回归线之间宿命点的行为类似极圈内的上升点，总是把它放在西半球
+	if math.Abs(fi) <= ekl {
+		if difdeg2n(hsp.vertex, hsp.mc) > 0 {
+			hsp.vertex = degnorm(hsp.vertex + 180)
+		}
+	}
+	// 赤道上升点
+	hsp.equasc = raToEcl(degnorm(th+90), cose)
+	hsp.equascSpeed = ascDash(th+90, 0, sine, cose)
+	// 协上升点（W. Koch）
+	hsp.coasc1 = degnorm(asc1(th-90, fi, sine, cose) + 180)
+	hsp.coasc1Speed = ascDash(th-90, fi, sine, cose)
+	// 协上升点（M. Munkasey）
+	if fi >= 0 {
+		hsp.coasc2 = asc1(th+90, 90-fi, sine, cose)
+		hsp.coasc2Speed = ascDash(th+90, 90-fi, sine, cose)
+	} else {
+		hsp.coasc2 = asc1(th+90, -90-fi, sine, cose)
+		hsp.coasc2Speed = ascDash(th+90, -90-fi, sine, cose)
+	}
+	// 极上升点（M. Munkasey）
+	hsp.polasc = asc1(th-90, fi, sine, cose)
+	hsp.polascSpeed = ascDash(th-90, fi, sine, cose)
+}
+
+// swapAcPolar 上升点在天顶之前（极圈内上升点在西半地平线上）时，把上升点移到下降点；
+// setCusp为真时同时更新cusp[1]。返回第4象限（天顶到上升点）的大小
+func (hsp *houses) swapAcPolar(setCusp bool) Float64 {
+	acmc := difdeg2n(hsp.ac, hsp.mc)
+	if acmc < 0 {
+		hsp.ac = degnorm(hsp.ac + 180)
+		if setCusp {
+			hsp.cusp[1] = hsp.ac
+		}
+		acmc = difdeg2n(hsp.ac, hsp.mc)
+	}
+	return acmc
+}
+
+// swapPolar 极圈内天顶沉到地平线以下、上升点移到西半球时，上升点、天顶和宫头都加上180度，
+// 宫位按顺时针方向排列；all为假时第4至9宫的宫头不变（它们随后由对宫求出）
+func (hsp *houses) swapPolar(all bool) {
+	if difdeg2n(hsp.ac, hsp.mc) >= 0 {
+		return
+	}
+	hsp.ac = degnorm(hsp.ac + 180)
+	hsp.mc = degnorm(hsp.mc + 180)
+	for i := 1; i <= 12; i++ {
+		if !all && i >= 4 && i < 10 {
+			continue
+		}
+		hsp.cusp[i] = degnorm(hsp.cusp[i] + 180)
+	}
+}
+
+// porphyry Porphyry宫位制：天顶到上升点和上升点到天底的象限三等分
+// 也用作其他宫位制在极圈内的替代
+func (hsp *houses) porphyry() {
+	acmc := hsp.swapAcPolar(true)
+	// 从Gauquelin等宫位制转来时cusp[1]和cusp[10]可能已被改写
+	hsp.cusp[1] = hsp.ac
+	hsp.cusp[10] = hsp.mc
+	hsp.cusp[2] = degnorm(hsp.ac + (180-acmc)/3)
+	hsp.cusp[3] = degnorm(hsp.ac + (180-acmc)/3*2)
+	hsp.cusp[11] = degnorm(hsp.mc + acmc/3)
+	hsp.cusp[12] = degnorm(hsp.mc + acmc/3*2)
+	// 第1象限的增长率；C的swehouse.c中第11、12宫误以上升点的速度为基数
+	q1Speed := hsp.acSpeed - hsp.mcSpeed
+	hsp.cuspSpeed[1] = hsp.acSpeed
+	hsp.cuspSpeed[10] = hsp.mcSpeed
+	hsp.cuspSpeed[2] = hsp.acSpeed - q1Speed/3
+	hsp.cuspSpeed[3] = hsp.acSpeed - q1Speed/3*2
+	hsp.cuspSpeed[11] = hsp.mcSpeed + q1Speed/3
+	hsp.cuspSpeed[12] = hsp.mcSpeed + q1Speed/3*2
+}
+
+// hasNaN 第1至12宫的宫头中是否有NaN
+func (hsp *houses) hasNaN() bool {
+	for i := 1; i <= 12; i++ {
+		if math.IsNaN(hsp.cusp[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// setCuspSpeeds 所有宫头的速度都为v（等宫制等）
+func (hsp *houses) setCuspSpeeds(v Float64) {
+	for i := 1; i <= 12; i++ {
+		hsp.cuspSpeed[i] = v
+	}
+}
+
+// placidus Placidus宫位制：第11、12、2、3宫的宫头迭代求出，极圈内返回错误
+func (hsp *houses) placidus(th, fi, tanfi, sine, cose, tane Float64, polar bool) error {
+	if polar {
+		return fmt.Errorf("极圈内无法计算，改用Porphyry宫位制")
+	}
+	a := asind(tanfi * tane)
+	fh1 := atand(sind(a/3) / tane)
+	fh2 := atand(sind(a*2/3) / tane)
+	// 宫位、赤经相对于armc的偏移、初始极高和半弧的比例
+	for _, c := range []struct {
+		ih       int
+		ra, fh   Float64
+		num, den Float64
+	}{
+		{11, 30, fh1, 1, 3},
+		{12, 60, fh2, 1, 1.5},
+		{2, 120, fh2, 1, 1.5},
+		{3, 150, fh1, 1, 3},
+	} {
+		cusp, speed, ok := placidusCusp(degnorm(c.ra+th), c.fh, tanfi, c.num, c.den, sine, cose)
+		if !ok {
+			return fmt.Errorf("接近极圈，迭代不收敛，改用Porphyry宫位制")
+		}
+		hsp.cusp[c.ih] = cusp
+		hsp.cuspSpeed[c.ih] = speed
+	}
+	return nil
+}
+
+// gauquelin 36个Gauquelin扇区，顺时针编号，按Placidus的方法迭代，极圈内返回错误
+func (hsp *houses) gauquelin(th, fi, tanfi, sine, cose, tane Float64, polar bool) error {
+	for i := 1; i <= 36; i++ {
+		hsp.cusp[i] = 0
+	}
+	if polar {
+		return fmt.Errorf("极圈内无法计算，改用Porphyry宫位制")
+	}
+	a := asind(tanfi * tane)
+	// 第4和第2象限
+	for ih := 2; ih <= 9; ih++ {
+		ih2 := Float64(10 - ih)
+		fh1 := atand(sind(a*ih2/9) / tane)
+		rectasc := degnorm(10*ih2 + th)
+		cusp, speed, ok := placidusCusp(rectasc, fh1, tanfi, ih2, 9, sine, cose)
+		if !ok {
+			return fmt.Errorf("接近极圈，迭代不收敛，改用Porphyry宫位制")
+		}
+		hsp.cusp[ih] = cusp
+		hsp.cusp[ih+18] = degnorm(cusp + 180)
+		hsp.cuspSpeed[ih] = speed
+		hsp.cuspSpeed[ih+18] = speed
+	}
+	// 第1和第3象限
+	for ih := 29; ih <= 36; ih++ {
+		ih2 := Float64(ih - 28)
+		fh1 := atand(sind(a*ih2/9) / tane)
+		rectasc := degnorm(180 - ih2*10 + th)
+		cusp, speed, ok := placidusCusp(rectasc, fh1, tanfi, ih2, 9, sine, cose)
+		if !ok {
+			return fmt.Errorf("接近极圈，迭代不收敛，改用Porphyry宫位制")
+		}
+		hsp.cusp[ih] = cusp
+		hsp.cusp[ih-18] = degnorm(cusp + 180)
+		hsp.cuspSpeed[ih] = speed
+		hsp.cuspSpeed[ih-18] = speed
+	}
+	hsp.cusp[1] = hsp.ac
+	hsp.cusp[10] = hsp.mc
+	hsp.cusp[19] = degnorm(hsp.ac + 180)
+	hsp.cusp[28] = degnorm(hsp.mc + 180)
+	hsp.cuspSpeed[1] = hsp.acSpeed
+	hsp.cuspSpeed[10] = hsp.mcSpeed
+	hsp.cuspSpeed[19] = hsp.acSpeed
+	hsp.cuspSpeed[28] = hsp.mcSpeed
+	return nil
+}
+
+// placidusCusp 迭代求半弧宫位制的宫头，rectasc为宫头的赤经（度），fh为初始极高，
+// 宫头位于半弧的num/den处。同时返回宫头的速度（度/日），迭代不收敛时ok为假
+func placidusCusp(rectasc, fh, tanfi, num, den, sine, cose Float64) (cusp, speed Float64, ok bool) {
+	tant := tand(asind(sine * sind(asc1(rectasc, fh, sine, cose))))
+	if math.Abs(tant) < housesVerySmall {
+		return rectasc, armcSpeed, true
+	}
+	// 极高
+	f := atand(sind(asind(tanfi*tant)*num/den) / tant)
+	cusp = asc1(rectasc, f, sine, cose)
+	var cuspsv Float64
+	i := 1
+	for ; i <= placidusIterMax; i++ {
+		tant = tand(asind(sine * sind(cusp)))
+		if math.Abs(tant) < housesVerySmall {
+			return rectasc, armcSpeed, true
+		}
+		f = atand(sind(asind(tanfi*tant)*num/den) / tant)
+		cusp = asc1(rectasc, f, sine, cose)
+		if i > 1 && math.Abs(difdeg2n(cusp, cuspsv)) < placidusIterSmall {
+			break
+		}
+		cuspsv = cusp
+	}
+	return cusp, ascDash(rectasc, f, sine, cose), i < placidusIterMax
+}
+
+// campanusOffsets Campanus和地平宫位制中卯酉圈上60度和30度的点在赤道上的投影距离
+func campanusOffsets(fi Float64) (xh1, xh2 Float64) {
+	cosfi := cosd(fi)
+	if cosfi == 0 {
+		if fi > 0 {
+			return 90, 90
+		}
+		return 270, 270
+	}
+	// tan xh1 = tan 60 / cos fi，tan xh2 = tan 30 / cos fi
+	xh1 = atand(math.Sqrt(3.0) / cosfi)
+	xh2 = atand(1 / math.Sqrt(3.0) / cosfi)
+	return
+}
+
+// asc1 极高为f的大圆与赤道相交于赤经x1（度），求它与黄道的交点的黄经
+// 先把x1化为第一象限，再由asc2计算
+func asc1(x1, f, sine, cose Float64) Float64 {
+	x1 = degnorm(x1)
+	n := int(x1/90 + 1)
+	// 靠近北极或南极
+	if math.Abs(90-f) < housesVerySmall {
+		return 180
+	}
+	if math.Abs(90+f) < housesVerySmall {
+		return 0
+	}
+	var ass Float64
+	switch n {
+	case 1:
+		ass = asc2(x1, f, sine, cose)
+	case 2:
+		ass = 180 - asc2(180-x1, -f, sine, cose)
+	case 3:
+		ass = 180 + asc2(x1-180, -f, sine, cose)
+	default:
+		ass = 360 - asc2(360-x1, f, sine, cose)
+	}
+	ass = degnorm(ass)
+	// 舍入，例如fi = 0且th = 0时上升点为89.999...
+	if math.Abs(ass-90) < housesVerySmall {
+		ass = 90
+	}
+	if math.Abs(ass-180) < housesVerySmall {
+		ass = 180
+	}
+	if math.Abs(ass-270) < housesVerySmall {
+		ass = 270
+	}
+	if math.Abs(ass-360) < housesVerySmall {
+		ass = 0
+	}
+	return ass
+}
+
+// ascDash asc1对x的导数乘以armc的速度，即asc1所求黄道点的速度（度/日）
+func ascDash(x, f, sine, cose Float64) Float64 {
+	cosx := cosd(x)
+	sinx := sind(x)
+	sinx2 := sinx * sinx
+	c := cose*cosx - tand(f)*sine
+	d := sinx2 + c*c
+	// 在黄道的轴上时导数无定义
+	if d <= housesVerySmall {
+		return 0
+	}
+	return (cosx*c + cose*sinx2) / d * armcSpeed
+}
+
+// asc2 asc1在第一象限的计算，x在0至90度之间，f在-90至90度之间
+// 由球面三角的余切公式：cot c sin x = -tan f sin e + cos x cos e
+func asc2(x, f, sine, cose Float64) Float64 {
+	ass := -tand(f)*sine + cose*cosd(x)
+	if math.Abs(ass) < housesVerySmall {
+		ass = 0
+	}
+	sinx := sind(x)
+	if math.Abs(sinx) < housesVerySmall {
+		sinx = 0
+	}
+	switch {
+	case sinx == 0:
+		if ass < 0 {
+			ass = -housesVerySmall
+		} else {
+			ass = housesVerySmall
+		}
+	case ass == 0:
+		if sinx < 0 {
+			ass = -90
+		} else {
+			ass = 90
+		}
+	default:
+		ass = atand(sinx / ass)
+	}
+	if ass < 0 {
+		ass = 180 + ass
+	}
+	return ass
+}
+
+// sunshineInit Sunshine宫位制：太阳的半昼弧和半夜弧三等分，
+// xh[ih]为第ih宫的点相对于子午圈的时角偏移（度）。太阳为拱极星时返回false
+func sunshineInit(lat, dec Float64, xh []Float64) bool {
+	// 上升差：sin ad = tan dec tan lat，太阳为拱极星时接近±90
+	arg := tand(dec) * tand(lat)
+	var ad Float64
+	switch {
+	case arg >= 1:
+		ad = 90 - housesVerySmall
+	case arg <= -1:
+		ad = -90 + housesVerySmall
+	default:
+		ad = asind(arg)
+	}
+	nsa := 90 - ad
+	dsa := 90 + ad
+	xh[2] = -2 * nsa / 3
+	xh[3] = -1 * nsa / 3
+	xh[5] = 1 * nsa / 3
+	xh[6] = 2 * nsa / 3
+	xh[8] = -2 * dsa / 3
+	xh[9] = -1 * dsa / 3
+	xh[11] = 1 * dsa / 3
+	xh[12] = 2 * dsa / 3
+	return math.Abs(arg) < 1
+}
+
+// sunshineSolutionMakransky Sunshine宫位制的Makransky解法（'i'），极圈内返回false
+func sunshineSolutionMakransky(ramc, lat, ecl Float64, hsp *houses) bool {
+	var xh [13]Float64
+	dec := hsp.sundec
+	sinlat := sind(lat)
+	coslat := cosd(lat)
+	tanlat := tand(lat)
+	tandec := tand(dec)
+	sinecl := sind(ecl)
+	if !sunshineInit(lat, dec, xh[:]) {
+		return false
+	}
+	// HP：半弧上的宫位点；CP：宫位子午圈与卯酉圈的交点；
+	// MP：宫位子午圈与赤道的交点；XP：宫位圈与卯酉圈的交点
+	for ih := 1; ih <= 12; ih++ {
+		// 跳过第1、4、7、10宫
+		if (ih-1)%3 == 0 {
+			continue
+		}
+		var zd, rah, r, cu Float64
+		md := math.Abs(xh[ih])
+		if ih <= 6 {
+			rah = degnorm(ramc + 180 + xh[ih])
+		} else {
+			rah = degnorm(ramc + xh[ih])
+		}
+		// Makransky这样处理南纬
+		if lat < 0 {
+			rah = degnorm(180 + rah)
+		}
+		if md == 90 {
+			// CP为东点（或西点），HP在东点与北极之间的子午圈上
+			zd = 90.0 - atand(sinlat*tandec)
+		} else {
+			var a, c Float64
+			if md < 90 {
+				// 三角形CP、天顶、北极：tan a = cos lat tan md
+				a = atand(coslat * tand(md))
+			} else {
+				// 三角形MP、东点、CP：tan a = tan md / cos lat（lat不能为90）
+				a = atand(tand(md-90) / coslat)
+			}
+			// 三角形CP、MP、东点：b为CP到赤道的距离
+			b := atand(tanlat * cosd(md))
+			// c为HP到CP沿子午圈的距离
+			if ih <= 6 {
+				c = b + dec
+			} else {
+				c = b - dec
+			}
+			// 三角形HP、CP、XP：f为CP到XP的距离
+			f := atand(sinlat * sind(md) * tand(c))
+			// 宫位圈在卯酉圈上的天顶距
+			zd = a + f
+		}
+		pole := asind(sind(zd) * sinlat)
+		q := asind(tandec * tand(pole))
+		var w Float64
+		if ih <= 3 || ih >= 11 {
+			w = degnorm(rah - q)
+		} else {
+			w = degnorm(rah + q)
+		}
+		switch w {
+		case 90:
+			r = atand(sind(ecl) * tand(pole))
+			if ih <= 3 || ih >= 11 {
+				cu = 90 + r
+			} else {
+				cu = 90 - r
+			}
+		case 270:
+			r = atand(sinecl * tand(pole))
+			if ih <= 3 || ih >= 11 {
+				cu = 270 - r
+			} else {
+				cu = 270 + r
+			}
+		default:
+			var z Float64
+			m := atand(math.Abs(tand(pole) / cosd(w)))
+			if ih <= 3 || ih >= 11 {
+				if w > 90 && w < 270 {
+					z = m - ecl
+				} else {
+					z = m + ecl
+				}
+			} else {
+				if w > 90 && w < 270 {
+					z = m + ecl
+				} else {
+					z = m - ecl
+				}
+			}
+			if z == 90 {
+				if w < 180 {
+					cu = 90
+				} else {
+					cu = 270
+				}
+			} else {
+				// r在0至90度之间
+				r = atand(math.Abs(cosd(m) * tand(w) / cosd(z)))
+				switch {
+				case w < 90:
+					cu = r
+				case w > 90 && w < 180:
+					cu = 180 - r
+				case w > 180 && w < 270:
+					cu = 180 + r
+				default:
+					cu = 360 - r
+				}
+			}
+			if z > 90 {
+				switch {
+				case w < 90:
+					cu = 180 - r
+				case w > 90 && w < 180:
+					cu = +r
+				case w > 180 && w < 270:
+					cu = 360 - r
+				default:
+					cu = 180 + r
+				}
+			}
+			// Makransky这样处理南纬
+			if lat < 0 {
+				cu = degnorm(cu + 180)
+			}
+		}
+		hsp.cusp[ih] = cu
+	}
+	return true
+}
+
+// sunshineSolutionTreindl Sunshine宫位制的Treindl解法（'I'）
+// 宫位圈过南北点和太阳周日圈的三等分点，由极高和它与赤道的交点用asc1求宫头。
+// 宫位圈退化时返回false
+func sunshineSolutionTreindl(ramc, lat, ecl Float64, hsp *houses) bool {
+	var xh [13]Float64
+	ok := true
+	dec := hsp.sundec
+	sinlat := sind(lat)
+	coslat := cosd(lat)
+	cosdec := cosd(dec)
+	tandec := tand(dec)
+	sinecl := sind(ecl)
+	cosecl := cosd(ecl)
+	sunshineInit(lat, dec, xh[:])
+	// 天顶是否在地平线以下
+	mcdec := atand(sind(ramc) * tand(ecl))
+	mcUnderHorizon := math.Abs(lat-mcdec) > 90
+	if mcUnderHorizon && sunshineKeepMcSouth {
+		// 已交换上升点与天顶，周日圈上的偏移反号
+		for ih := 2; ih <= 12; ih++ {
+			xh[ih] = -xh[ih]
+		}
+	}
+	for ih := 1; ih <= 12; ih++ {
+		// 跳过第1、4、7、10宫
+		if (ih-1)%3 == 0 {
+			continue
+		}
+		// 偏移x在大圆上的长度
+		xhs := 2 * asind(cosdec*sind(xh[ih]/2))
+		// 三角形北极、MP0（半弧与子午圈的交点）、HP：两边为90 - dec，底为xhs
+		cosa := tandec * tand(xhs/2)
+		alph := acosd(cosa)
+		// 三角形南点、MP0、HP：两边xhs和b，夹角alpha2，求南点的角zd；
+		// 夜间一侧用北点
+		var alpha2, b Float64
+		if ih > 7 {
+			alpha2 = 180 - alph
+			b = 90 - lat + dec
+		} else {
+			alpha2 = alph
+			b = 90 - lat - dec
+		}
+		// 边的余弦定理
+		cosc := cosd(xhs)*cosd(b) + sind(xhs)*sind(b)*cosd(alpha2)
+		c := acosd(cosc)
+		if c < 1e-6 {
+			ok = false
+		}
+		// 正弦定理
+		sinzd := sind(xhs) * sind(alpha2) / sind(c)
+		zd := asind(sinzd)
+		// 宫位圈与赤道的交点rax
+		rax := atand(coslat * tand(zd))
+		// 宫位圈的极高
+		pole := asind(sinzd * sinlat)
+		var a Float64
+		if ih <= 6 {
+			pole = -pole
+			a = degnorm(rax + ramc + 180)
+		} else {
+			a = degnorm(ramc + rax)
+		}
+		hsp.cusp[ih] = asc1(a, pole, sinecl, cosecl)
+	}
+	if mcUnderHorizon && !sunshineKeepMcSouth {
+		for ih := 2; ih <= 12; ih++ {
+			if (ih-1)%3 == 0 {
+				continue
+			}
+			hsp.cusp[ih] = degnorm(hsp.cusp[ih] + 180)
+		}
+	}
+	return ok
+}
+
+// milliarcsec 0.001角秒（度）
+const milliarcsec = 1.0 / 3600000.0
+
+// HousePos 计算黄经lon、黄纬lat（度，回归黄道）的点的宫位，结果在1.0至13.0之间，
+// 如7.42表示在第7宫的42%处；Gauquelin扇区为1.0至37.0。armc、geolat、eps同HousesArmc，
+// sundec为太阳赤纬，只用于Sunshine宫位制。
+// Placidus、Koch、Regiomontanus、Campanus等以点的赤纬计算，黄纬不为0时结果也正确；
+// APC、Pullen SD/SR和Sunshine（Makransky）以外的其他宫位制用宫头按比例内插。
+// 恒星黄道仍须输入回归黄道的黄经；整宫制等依赖黄道的宫位制不适用于非传统的恒星黄道
+func HousePos(armc, geolat, eps Float64, hsys byte, lon, lat, sundec Float64) (Float64, error) {
+	sine := sind(eps)
+	cose := cosd(eps)
+	if hsys >= 'a' && hsys <= 'z' {
+		hsys -= 'a' - 'A'
+	}
+	// 点正好在宫头上时不需要计算；几个宫头重合时取最后一个
+	var dsun Float64
+	if res, err := housesArmc(armc, geolat, eps, hsys, sundec, false); err == nil {
+		for i := 12; i >= 1; i-- {
+			if math.Abs(difdeg2n(lon, res.Cusps[i])) < milliarcsec && lat == 0 {
+				return Float64(i), nil
+			}
+		}
+		switch hsys {
+		case 'I':
+			dsun = sundec
+		case 'Y':
+			// APC宫位制以上升点的赤纬代替太阳赤纬
+			xeq := []Float64{res.Ascmc[SeAsc], 0, 1}
+			cotrans(xeq, xeq, -eps)
+			dsun = xeq[1]
+		}
+	}
+	xeq := []Float64{lon, lat, 1}
+	cotrans(xeq, xeq, -eps)
+	ra := xeq[0]
+	de := xeq[1]
+	// 点在天顶和天底以东的距离，-180至180度
+	mdd := degnorm(ra - armc)
+	mdn := degnorm(mdd + 180)
+	if mdd >= 180 {
+		mdd -= 360
+	}
+	if mdn >= 180 {
+		mdn -= 360
+	}
+	var hpos Float64
+	// xp[0]为0至360度之间的宫位
+	var xp [3]Float64
+	switch hsys {
+	case 'N':
+		// 等宫制，第1宫始于白羊座0度
+		hpos = lon/30.0 + 1
+	case 'A', 'E', 'D', 'V', 'W':
+		// 等宫制、Vehlow和整宫制
+		asc := fixAscPolar(asc1(degnorm(armc+90), geolat, sine, cose), armc, eps, geolat)
+		xp[0] = degnorm(lon - asc)
+		switch hsys {
+		case 'V':
+			xp[0] = degnorm(xp[0] + 15)
+		case 'W':
+			xp[0] = degnorm(xp[0] + math.Mod(asc, 30))
+		case 'D':
+			xp[0] = degnorm(lon - raToEcl(degnorm(armc), cose) - 90)
+		}
+		// 加上0.001角秒，使宫头上的点落在该宫内
+		xp[0] = degnorm(xp[0] + milliarcsec)
+		hpos = xp[0]/30.0 + 1
+	case 'O', 'S':
+		// Porphyry和Sripati
+		asc := fixAscPolar(asc1(degnorm(armc+90), geolat, sine, cose), armc, eps, geolat)
+		mc := raToEcl(degnorm(armc), cose)
+		// 加上0.001角秒，使宫头上的点落在该宫内
+		xp[0] = degnorm(degnorm(lon-asc) + milliarcsec)
+		if xp[0] < 180 {
+			hpos = 1
+		} else {
+			hpos = 7
+			xp[0] -= 180
+		}
+		acmc := difdeg2n(asc, mc)
+		if xp[0] < 180-acmc {
+			hpos += xp[0] * 3 / (180 - acmc)
+		} else {
+			hpos += 3 + (xp[0]-180+acmc)*3/acmc
+		}
+		if hsys == 'S' {
+			hpos += 0.5
+			if hpos > 12 {
+				hpos = 1
+			}
+		}
+	case 'B':
+		// Alcabitius：上升点的半昼弧和半夜弧三等分
+		asc := fixAscPolar(asc1(degnorm(armc+90), geolat, sine, cose), armc, eps, geolat)
+		dek := asind(sind(asc) * sine)
+		r := -tand(geolat) * tand(dek)
+		sda := acosd(r)
+		sna := 180 - sda
+		if mdd > 0 {
+			if mdd < sda {
+				hpos = mdd * 90 / sda
+			} else {
+				hpos = 90 + (mdd-sda)*90/sna
+			}
+		} else {
+			if mdd > -sna {
+				hpos = 360 + mdd*90/sna
+			} else {
+				hpos = 270 + (mdd+sna)*90/sda
+			}
+		}
+		hpos = degnorm(hpos-90)/30.0 + 1
+		if hpos >= 13 {
+			hpos -= 12
+		}
+	case 'X':
+		// 子午线宫位制
+		hpos = degnorm(mdd-90)/30.0 + 1
+	case 'F':
+		// Carter赤道宫位制
+		x := []Float64{fixAscPolar(asc1(degnorm(armc+90), geolat, sine, cose), armc, eps, geolat), 0, 1}
+		cotrans(x, x, -eps)
+		hpos = degnorm(ra-x[0])/30.0 + 1
+	case 'M':
+		// Morinus
+		hpos = degnorm(raToEcl(lon, cose)-armc-90)/30.0 + 1
+	case 'K':
+		return housePosKoch(armc, geolat, eps, de, mdd)
+	case 'C':
+		// Campanus：在卯酉圈上从东点向天底量度，东点为0，天底为90，西点为180，天顶为270
+		xeq[0] = degnorm(mdd - 90)
+		cotrans(xeq, xp[:], -geolat)
+		xp[0] = degnorm(xp[0] + milliarcsec)
+		hpos = xp[0]/30.0 + 1
+	case 'J':
+		hpos = housePosSavard(geolat, xeq, mdd)
+	case 'U':
+		hpos = housePosKrusinski(armc, geolat, eps, sine, cose, xeq)
+	case 'H':
+		// 地平宫位制
+		xeq[0] = degnorm(mdd - 90)
+		cotrans(xeq, xp[:], 90-geolat)
+		xp[0] = degnorm(xp[0] + milliarcsec)
+		hpos = xp[0]/30.0 + 1
+	case 'R':
+		// Regiomontanus
+		switch {
+		case math.Abs(mdd) < housesVerySmall:
+			xp[0] = 270
+		case 180-math.Abs(mdd) < housesVerySmall:
+			xp[0] = 90
+		default:
+			geolat = clampPole(geolat, housesVerySmall)
+			de = clampPole(de, housesVerySmall)
+			xp[0] = degnorm(regiomontanusPos(geolat, de, mdd) + milliarcsec)
+		}
+		hpos = xp[0]/30.0 + 1
+	case 'I', 'Y':
+		// Sunshine（Makransky）和APC宫位制：方法相同，Sunshine用太阳赤纬，APC用上升点的赤纬
+		hpos = housePosSunshine(clampPole(geolat, milliarcsec), clampPole(de, housesVerySmall), mdd, dsun)
+	case 'T':
+		hpos = housePosTopocentric(armc, geolat, ra, de, mdd)
+	case 'P', 'G':
+		// Placidus和Gauquelin：点在其半弧上的位置
+		if 90-math.Abs(de) <= math.Abs(geolat) {
+			// 拱极区用Otto Ludwig的方法
+			if de*geolat < 0 {
+				xp[0] = degnorm(90 + mdn/2)
+			} else {
+				xp[0] = degnorm(270 + mdd/2)
+			}
+		} else {
+			sinad := tand(de) * tand(geolat)
+			ad := asind(sinad)
+			if sinad+cosd(mdd) >= 0 {
+				// 地平线以上
+				xp[0] = (mdd/(90+ad) + 3) * 90
+			} else {
+				xp[0] = (mdn/(90-ad) + 1) * 90
+			}
+			xp[0] = degnorm(xp[0] + milliarcsec)
+		}
+		if hsys == 'G' {
+			// Gauquelin扇区按顺时针方向编号
+			hpos = (360-xp[0])/10.0 + 1
+		} else {
+			hpos = xp[0]/30.0 + 1
+		}
+	default:
+		// 其他宫位制：在宫头之间按比例内插
+		res, err := housesArmc(armc, geolat, eps, hsys, sundec, false)
+		if err != nil {
+			return 0, fmt.Errorf("宫位制%c无法计算宫位: %v", hsys, err)
+		}
+		hpos = housePosInterpolate(res.Cusps, lon)
+	}
+	return hpos, nil
+}
+
+// fixAscPolar 上升点在地平线的西半部时加上180度
+func fixAscPolar(asc, armc, eps, geolat Float64) Float64 {
+	demc := atand(sind(armc) * tand(eps))
+	if geolat >= 0 && 90-geolat+demc < 0 {
+		asc = degnorm(asc + 180)
+	}
+	if geolat < 0 && -90-geolat+demc > 0 {
+		asc = degnorm(asc + 180)
+	}
+	return asc
+}
+
+// clampPole 把接近±90度的角度限制在距±90度limit处
+func clampPole(x, limit Float64) Float64 {
+	if x > 90-limit {
+		return 90 - limit
+	}
+	if x < -90+limit {
+		return -90 + limit
+	}
+	return x
+}
+
+// regiomontanusPos 赤纬de、子午距mdd的点在Regiomontanus宫位制中的位置（0至360度）
+func regiomontanusPos(geolat, de, mdd Float64) Float64 {
+	a := tand(geolat)*tand(de) + cosd(mdd)
+	x := degnorm(atand(-a / sind(mdd)))
+	if mdd < 0 {
+		x += 180
+	}
+	return degnorm(x)
+}
+
+// housePosInterpolate 黄经lon在宫头cusps（下标1..12）之间按比例内插的宫位
+// 宫头逆行（极圈内）时反方向量度
+func housePosInterpolate(cusps []Float64, lon Float64) Float64 {
+	dist := func(a, b Float64) Float64 { return degnorm(a - b) }
+	if difdeg2n(cusps[6], cusps[1]) <= 0 {
+		dist = func(a, b Float64) Float64 { return degnorm(b - a) }
+	}
+	d := dist(lon, cusps[1])
+	var c2 Float64
+	i := 1
+	for ; i <= 12; i++ {
+		if i == 12 {
+			c2 = 360
+		} else {
+			c2 = dist(cusps[i+1], cusps[1])
+		}
+		if d < c2 {
+			break
+		}
+	}
+	if i > 12 {
+		i = 12
+	}
+	c1 := dist(cusps[i], cusps[1])
+	if c2 == c1 {
+		return Float64(i)
+	}
+	return Float64(i) + (d-c1)/(c2-c1)
+}
+
+// housePosKoch Koch宫位制中的宫位，拱极区也尽量计算，但第4至9宫只出现在西半球
+func housePosKoch(armc, geolat, eps, de, mdd Float64) (Float64, error) {
+	var adp Float64
+	switch {
+	case 90-geolat < de || -90-geolat > de:
+		// 点在北拱极圈内
+		adp = 90
+	case geolat-90 > de || geolat+90 < de:
+		// 点在南拱极圈内
+		adp = -90
+	default:
+		adp = asind(tand(geolat) * tand(de))
+	}
+	admc := tand(eps) * tand(geolat) * sind(armc)
+	// 天顶为拱极点
+	if admc > 1 {
+		admc = 1
+	} else if admc < -1 {
+		admc = -1
+	}
+	admc = asind(admc)
+	samc := 90 + admc
+	if samc == 0 {
+		return 0, fmt.Errorf("拱极区无法计算Koch宫位")
+	}
+	var dfac, x Float64
+	if mdd >= 0 {
+		// 东半球
+		dfac = (mdd - adp + admc) / samc
+		x = degnorm((dfac - 1) * 90)
+	} else {
+		dfac = (mdd + 180 + adp + admc) / samc
+		x = degnorm((dfac + 1) * 90)
+	}
+	// 点的半弧比天顶的半弧长
+	if dfac > 2 || dfac < 0 {
+		return 0, fmt.Errorf("拱极区无法计算Koch宫位")
+	}
+	return degnorm(x+milliarcsec)/30.0 + 1, nil
+}
+
+// housePosSavard Savard-A宫位制中的宫位，在卯酉圈上量度
+func housePosSavard(geolat Float64, xeq []Float64, mdd Float64) Float64 {
+	var xs1, xs2 Float64
+	if math.Abs(geolat) < housesVerySmall {
+		xs2 = 1 / 3.0
+		xs1 = 2 / 3.0
+	} else {
+		sinfi := sind(geolat)
+		xs2 = sind(geolat/3) / sinfi
+		xs1 = sind(2*geolat/3) / sinfi
+	}
+	xs2 = asind(xs2)
+	xs1 = asind(xs1)
+	// 卯酉圈上从东点向下量度的宫头：第1宫0，第4宫90，第7宫180，第10宫270
+	hcusp := []Float64{0, 0, xs2, xs1, 90, 180 - xs1, 180 - xs2, 180, 180 + xs2, 180 + xs1, 270, 360 - xs1, 360 - xs2}
+	var xp [3]Float64
+	xeq[0] = degnorm(mdd - 90)
+	cotrans(xeq, xp[:], -geolat)
+	return housePosInterpolate(hcusp, xp[0])
+}
+
+// housePosKrusinski Krusinski-Pisa-Goelzer宫位制中的宫位：
+// 求过上升点和天顶的大圆与赤道的交点和倾角，在此大圆上量度点的赤经圈与它的交点
+func housePosKrusinski(armc, geolat, eps, sine, cose Float64, xeq []Float64) Float64 {
+	// 以下计算在纬度0时无定义
+	if math.Abs(geolat) < housesVerySmall {
+		if geolat >= 0 {
+			geolat = housesVerySmall
+		} else {
+			geolat = -housesVerySmall
+		}
+	}
+	asc := fixAscPolar(asc1(degnorm(armc+90), geolat, sine, cose), armc, eps, geolat)
+	// 上升点—天顶大圆与赤道的交点
+	x := []Float64{asc, 0, 1}
+	cotrans(x, x, -eps)
+	// 东点的赤经
+	raep := degnorm(armc + 90)
+	x[0] = degnorm(raep - x[0])
+	// 转换到地平坐标，得到地平圈上东点到上升点的弧
+	cotrans(x, x, -(90 - geolat))
+	tanx := tand(x[0])
+	var xtemp Float64
+	if geolat == 0 {
+		if tanx >= 0 {
+			xtemp = 90
+		} else {
+			xtemp = -90
+		}
+	} else {
+		xtemp = atand(tanx / cosd(90-geolat))
+	}
+	if x[0] > 90 && x[0] <= 270 {
+		xtemp = degnorm(xtemp + 180)
+	}
+	x[0] = degnorm(xtemp)
+	raaz := degnorm(raep - x[0])
+	// 上升点—天顶大圆对赤道的倾角
+	x[0] = degnorm(raep - raaz)
+	x[1] = 0
+	cotrans(x, x, -(90 - geolat))
+	x[1] += 90
+	cotrans(x, x, 90-geolat)
+	oblaz := x[1]
+	// 上升点在此大圆上相对于赤道交点的位置
+	xasc := []Float64{asc, 0, 1}
+	cotrans(xasc, xasc, -eps)
+	xasc[0] = degnorm(xasc[0] - raaz)
+	xtemp = atand(tand(xasc[0]) / cosd(oblaz))
+	if xasc[0] > 90 && xasc[0] <= 270 {
+		xtemp = degnorm(xtemp + 180)
+	}
+	xasc[0] = degnorm(xtemp)
+	// 点在此大圆上相对于赤道交点的位置
+	xp := degnorm(xeq[0] - raaz)
+	xtemp = atand(tand(xp) / cosd(oblaz))
+	if xp > 90 && xp <= 270 {
+		xtemp = degnorm(xtemp + 180)
+	}
+	xp = degnorm(degnorm(xtemp) - xasc[0])
+	return degnorm(xp+milliarcsec)/30.0 + 1
+}
+
+// housePosSunshine Sunshine（Makransky）和APC宫位制中的宫位，dsun为太阳（APC为上升点）的赤纬
+// 太阳为拱极点时昼弧或夜弧为0，地平线以上的点在第10宫头，以下的点在第4宫头
+func housePosSunshine(geolat, de, mdd, dsun Float64) Float64 {
+	// Regiomontanus宫位
+	x := regiomontanusPos(geolat, de, mdd)
+	// 点是否在地平线以上
+	isAboveHor := tand(de)*tand(geolat)+cosd(mdd) >= 0
+	// armc在地平线以上的高度
+	harmc := 90 - geolat
+	if geolat < 0 {
+		harmc = 90 + geolat
+	}
+	// 位置线与赤道的交点的子午距
+	isWesternHalf := false
+	darmc := degnorm(x - 270)
+	if darmc > 180 {
+		isWesternHalf = true
+		darmc = 360 - darmc
+	}
+	// 太阳的半昼弧
+	var ad Float64
+	sinad := tand(dsun) * tand(geolat)
+	switch {
+	case sinad >= 1:
+		ad = 90
+	case sinad <= -1:
+		ad = -90
+	default:
+		ad = asind(sinad)
+	}
+	sad := 90 + ad
+	san := 90 - ad
+	switch {
+	case sad == 0 && isAboveHor:
+		x = 270
+	case san == 0 && !isAboveHor:
+		x = 90
+	default:
+		sa := sad
+		if !isAboveHor {
+			dsun = -dsun
+			sa = san
+			darmc = 180 - darmc
+			isWesternHalf = !isWesternHalf
+		}
+		// 位置线从南点到赤道的长度
+		a := acosd(cosd(harmc) * cosd(darmc))
+		if a < housesVerySmall {
+			a = housesVerySmall
+		}
+		// 位置线与赤道夹角的正弦
+		sinpsi := math.Max(-1, math.Min(1, sind(harmc)/sind(a)))
+		// 位置线与太阳周日圈的交点的子午距
+		y := sind(dsun) / sinpsi
+		switch {
+		case y > 1:
+			y = 90 - housesVerySmall
+		case y < -1:
+			y = -(90 - housesVerySmall)
+		default:
+			y = asind(y)
+		}
+		d := acosd(cosd(y) / cosd(dsun))
+		if dsun < 0 {
+			d = -d
+		}
+		if geolat < 0 {
+			d = -d
+		}
+		darmc += d
+		if isWesternHalf {
+			x = 270 - darmc/sa*90
+		} else {
+			x = 270 + darmc/sa*90
+		}
+		if !isAboveHor {
+			x = degnorm(x + 180)
+		}
+	}
+	return degnorm(x+milliarcsec)/30.0 + 1
+}
+
+// housePosTopocentric Polich/Page宫位制中的宫位：二分法求点的“拓扑中心”位置线
+func housePosTopocentric(armc, geolat, ra, de, mdd Float64) Float64 {
+	fh := math.Max(-89.999, math.Min(89.999, geolat))
+	mdd = degnorm(mdd)
+	de = clampPole(de, housesVerySmall)
+	sinad := math.Max(-1, math.Min(1, tand(de)*tand(fh)))
+	isAboveHor := sinad+cosd(mdd) >= 0
+	// 地平线以下的点映射到地平线以上的对点
+	if !isAboveHor {
+		ra = degnorm(ra + 180)
+		de = -de
+		mdd = degnorm(mdd + 180)
+	}
+	// 西半球的点映射到东半球
+	if mdd > 180 {
+		ra = degnorm(armc - mdd)
+	}
+	tanfi := tand(fh)
+	ra0 := degnorm(armc + 90)
+	xp := []Float64{0, 1, 1}
+	xeq := []Float64{0, de, 1}
+	fac := 2.0
+	for nloop := 0; math.Abs(xp[1]) > 0.000001 && nloop < 1000; nloop++ {
+		if xp[1] > 0 {
+			fh = atand(tand(fh) - tanfi/fac)
+			ra0 -= 90 / fac
+		} else {
+			fh = atand(tand(fh) + tanfi/fac)
+			ra0 += 90 / fac
+		}
+		xeq[0] = degnorm(ra - ra0)
+		cotrans(xeq, xp, 90-fh)
+		fac *= 2
+	}
+	hpos := degnorm(ra0 - armc)
+	// 映射回西半球和地平线以下
+	if mdd > 180 {
+		hpos = degnorm(-hpos)
+	}
+	if !isAboveHor {
+		hpos = degnorm(hpos + 180)
+	}
+	return degnorm(hpos-90)/30 + 1
+}
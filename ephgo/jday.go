@@ -0,0 +1,108 @@
+// 分为两部分的儒略日，同SOFA/ERFA的两部分日期，避免float64儒略日的精度损失。
+//
+// float64儒略日在现代日期的分辨率约为40微秒，每次tjd + dt/86400又有舍入。
+// JulianDay把日期分为当天0时的儒略日（以.5结尾）和日内的小数部分，
+// 两者之和的分辨率约为10皮秒。日期、ΔT和JPL星历有接受JulianDay的对应函数：
+// JuldaySplit、RevjulSplit、UtcToJdSplit、JdetToUtcSplit、UtToEt、EtToUt和PlephSplit。
+// 对应的Float64函数保留原来的算法，结果与C版本一致。
+// Calc和CalcUT只接受Float64：行星和月球的计算在内部使用一个float64的ET，
+// 两部分的输入在第一次使用时就要合并，CalcUT中tjd + dt/86400的舍入（不超过20微秒）无法由此避免。
+
+package ephgo
+
+import (
+	"math"
+	"time"
+)
+
+// JulianDay 两部分的儒略日，值为Day + Frac
+// 规范化以后Day为0时的儒略日（以.5结尾），0 <= Frac < 1
+type JulianDay struct {
+	Day  Float64 // 0时的儒略日
+	Frac Float64 // 日内的时间（日）
+}
+
+// NewJulianDay 由儒略日tjd得到JulianDay
+func NewJulianDay(tjd Float64) JulianDay {
+	return JulianDayOf(tjd, 0)
+}
+
+// JulianDayOf 由任意分成两部分的儒略日d1 + d2得到规范化的JulianDay
+// 精度最高的分法是d1为日期、d2为日内的时间，例如2451544.5和0.25
+func JulianDayOf(d1, d2 Float64) JulianDay {
+	if math.Abs(d2) > math.Abs(d1) {
+		d1, d2 = d2, d1
+	}
+	// d1与不大于它的0时之差是精确的
+	day := math.Floor(d1-0.5) + 0.5
+	frac := (d1 - day) + d2
+	fl := math.Floor(frac)
+	day += fl
+	frac -= fl
+	// 舍入可能使frac等于1
+	if frac >= 1 {
+		day++
+		frac = 0
+	}
+	return JulianDay{Day: day, Frac: frac}
+}
+
+// Float64 返回儒略日Day + Frac
+func (j JulianDay) Float64() Float64 {
+	return j.Day + j.Frac
+}
+
+// AddDays 返回j加上d日，d的整数和小数部分分开相加
+func (j JulianDay) AddDays(d Float64) JulianDay {
+	di := math.Trunc(d)
+	return JulianDayOf(j.Day+di, j.Frac+(d-di))
+}
+
+// AddSeconds 返回j加上s秒
+func (j JulianDay) AddSeconds(s Float64) JulianDay {
+	q := math.Floor(s / 86400)
+	return JulianDayOf(j.Day+q, j.Frac+(s-q*86400)/86400)
+}
+
+// Sub 返回j - k（日）
+func (j JulianDay) Sub(k JulianDay) Float64 {
+	return (j.Day - k.Day) + (j.Frac - k.Frac)
+}
+
+// TimeToJulianDay 将时刻t精确地换算为JulianDay（世界时），t的时区不影响结果
+func TimeToJulianDay(t time.Time) JulianDay {
+	sec := t.Unix()
+	days := sec / 86400
+	secOfDay := sec % 86400
+	if secOfDay < 0 {
+		days--
+		secOfDay += 86400
+	}
+	ns := Float64(secOfDay)*1e9 + Float64(t.Nanosecond())
+	return JulianDay{Day: Float64(days) + jdUnixEpoch, Frac: ns / nsPerDay}
+}
+
+// Time 将j（世界时）换算为时区loc中的time.Time，loc为nil时使用UTC；时间按四舍五入取到纳秒
+func (j JulianDay) Time(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	j = JulianDayOf(j.Day, j.Frac)
+	days := int64(j.Day+0.5) - int64(jdUnixEpoch+0.5)
+	ns := int64(math.Round(j.Frac * nsPerDay))
+	return time.Unix(days*86400, ns).In(loc)
+}
+
+// secondsOfDay 将规范化的j的日内时间分为时、分、秒
+func (j JulianDay) secondsOfDay() (ihour, imin Int32, dsec Float64) {
+	secs := j.Frac * 86400
+	// 舍入可能得到86400
+	if secs >= 86400 {
+		secs = math.Nextafter(86400, 0)
+	}
+	ihour = Int32(secs / 3600)
+	secs -= Float64(ihour) * 3600
+	imin = Int32(secs / 60)
+	dsec = secs - Float64(imin)*60
+	return
+}
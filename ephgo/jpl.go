@@ -6,14 +6,12 @@ import (
 	"io"
 	"math"
 	"os"
-	"path/filepath"
-	"strings"
 )
 
 // JPL文件相关常量
 const (
-	JplRecordSize = 8144  // JPL记录大小
-	JplNCoeff     = 1018  // 系数数量
+	JplRecordSize = 8144  // JPL记录大小（DE405/DE431）
+	JplNCoeff     = 1018  // 系数数量（DE405/DE431）
 	JplMaxPlanets = 15    // 最大行星数
 )
 
@@ -36,308 +34,432 @@ type JplData struct {
 	File     *os.File
 	FileName string
 	IsOpen   bool
+
+	doReorder bool        // 文件字节序与本机不同
+	denum     Int32       // DE编号
+	ipt       [39]Int32   // 各天体系数在记录中的位置、个数和子区间数
+	irecsz    int64       // 记录长度（字节）
+	ncoeffs   int         // 每个记录的系数个数
+	nrl       int64       // 当前缓存的记录号
+	buf       []Float64   // 当前记录
+	pv        [78]Float64 // 各天体的位置和速度
+	pvsun     [6]Float64  // 质心太阳
+	pc, vc    [18]Float64 // 切比雪夫多项式及其导数
+	np, nv    int
+	twot      Float64
 }
 
 var jplData JplData
 
+// order 文件的字节序
+func (js *JplData) order() binary.ByteOrder {
+	if js.doReorder {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
 // OpenJplFile 打开JPL文件
+// fpath为星历路径（可包含多个目录），ss返回文件的起止时间和段长
 func OpenJplFile(ss []Float64, fname, fpath string) error {
 	if jplData.IsOpen {
-		CloseJplFile()
-	}
-	
-	// 构建文件路径
-	var fullPath string
-	if fpath != "" {
-		fullPath = filepath.Join(fpath, fname)
-	} else {
-		fullPath = fname
-	}
-	
-	// 打开文件
-	file, err := os.Open(fullPath)
-	if err != nil {
-		return fmt.Errorf("无法打开JPL文件 %s: %v", fullPath, err)
+		if len(ss) >= 3 {
+			ss[0] = jplData.Header.StartJD
+			ss[1] = jplData.Header.EndJD
+			ss[2] = jplData.Header.StepJD
+		}
+		return nil
 	}
-	
-	jplData.File = file
-	jplData.FileName = fullPath
-	
-	// 读取文件头
-	err = readJplHeader(&jplData.Header, file)
-	if err != nil {
-		file.Close()
-		return fmt.Errorf("读取JPL文件头失败: %v", err)
+	jplData = JplData{}
+	retc, err := jplData.open(fname, fpath)
+	if retc != Ok {
+		CloseJplFile()
+		return err
 	}
-	
 	jplData.IsOpen = true
-	
-	// 返回开始、结束和步长
+	// 插值函数的初始值
+	jplData.pc[0] = 1
+	jplData.pc[1] = 2
+	jplData.vc[1] = 1
 	if len(ss) >= 3 {
 		ss[0] = jplData.Header.StartJD
 		ss[1] = jplData.Header.EndJD
 		ss[2] = jplData.Header.StepJD
 	}
-	
 	return nil
 }
 
 // CloseJplFile 关闭JPL文件
 func CloseJplFile() {
-	if jplData.IsOpen && jplData.File != nil {
+	if jplData.File != nil {
 		jplData.File.Close()
-		jplData.IsOpen = false
 	}
+	jplData = JplData{}
 }
 
 // GetJplDenum 获取JPL DE编号
 func GetJplDenum() Int32 {
-	// 从文件名推断DE编号
-	fname := strings.ToLower(filepath.Base(jplData.FileName))
-	if strings.Contains(fname, "de405") {
-		return 405
-	} else if strings.Contains(fname, "de406") {
-		return 406
-	} else if strings.Contains(fname, "de431") {
-		return 431
-	} else if strings.Contains(fname, "de430") {
-		return 430
-	}
-	return 431 // 默认
+	return jplData.denum
 }
 
 // Pleph 计算天体位置
 // et: 儒略历力学时
 // ntarg: 目标天体
 // ncent: 中心天体
-// 返回：位置和速度数组 [x, y, z, vx, vy, vz]
+// 返回：位置和速度数组 [x, y, z, vx, vy, vz]，单位为天文单位和天文单位/日
+// ntarg为JNut时返回章动及其变化率，为JLib时返回月球天平动
 func Pleph(et Float64, ntarg, ncent int) ([6]Float64, error) {
+	return PlephSplit(NewJulianDay(et), ntarg, ncent)
+}
+
+// PlephSplit 同Pleph，输入两部分的儒略日，切比雪夫插值的时间参数不损失精度
+func PlephSplit(et JulianDay, ntarg, ncent int) ([6]Float64, error) {
 	var rrd [6]Float64
-	
-	if !jplData.IsOpen {
-		return rrd, fmt.Errorf("JPL文件未打开")
+	_, err := jplPlephSplit(et, ntarg, ncent, rrd[:])
+	return rrd, err
+}
+
+// jplPleph 计算ntarg相对于ncent的位置和速度，返回Ok、NotAvailable或BeyondEphLimits
+func jplPleph(et Float64, ntarg, ncent int, rrd []Float64) (int, error) {
+	return jplPlephSplit(NewJulianDay(et), ntarg, ncent, rrd)
+}
+
+// jplPlephSplit 同jplPleph，输入两部分的儒略日
+func jplPlephSplit(et JulianDay, ntarg, ncent int, rrd []Float64) (int, error) {
+	js := &jplData
+	var list [12]Int32
+	for i := 0; i < 6; i++ {
+		rrd[i] = 0
 	}
-	
-	// 检查时间范围
-	if et < jplData.Header.StartJD || et > jplData.Header.EndJD {
-		return rrd, fmt.Errorf("时间超出JPL文件范围: %f", et)
+	if !js.IsOpen {
+		return NotAvailable, fmt.Errorf("JPL文件未打开")
 	}
-	
-	// 计算记录位置
-	recordNum := int((et - jplData.Header.StartJD) / jplData.Header.StepJD)
-	if recordNum < 0 {
-		recordNum = 0
+	if ntarg == ncent {
+		return Ok, nil
 	}
-	
-	// 读取记录
-	record, err := readJplRecord(jplData.File, recordNum)
-	if err != nil {
-		return rrd, fmt.Errorf("读取JPL记录失败: %v", err)
+	pv := js.pv[:]
+	// 章动
+	if ntarg == JNut {
+		if js.ipt[34] <= 0 {
+			return NotAvailable, fmt.Errorf("JPL星历文件中没有章动数据")
+		}
+		list[10] = 2
+		return js.state(et, list[:], false, rrd)
 	}
-	
-	// 计算位置和速度
-	err = computePosition(record, et, ntarg, ncent, &rrd)
-	if err != nil {
-		return rrd, fmt.Errorf("计算位置失败: %v", err)
+	// 天平动
+	if ntarg == JLib {
+		if js.ipt[37] <= 0 {
+			return NotAvailable, fmt.Errorf("JPL星历文件中没有天平动数据")
+		}
+		list[11] = 2
+		if retc, err := js.state(et, list[:], false, rrd); retc != Ok {
+			return retc, err
+		}
+		copy(rrd[:6], pv[60:66])
+		return Ok, nil
 	}
-	
-	return rrd, nil
+	// 设置需要插值的天体
+	for _, n := range [2]int{ntarg, ncent} {
+		if n < JSun {
+			list[n] = 2
+		}
+		switch n {
+		case JMoon: // 月球需要地球
+			list[JEarth] = 2
+		case JEarth: // 地球需要月球
+			list[JMoon] = 2
+		case JEmb: // 地月质心需要地球
+			list[JEarth] = 2
+		}
+	}
+	if retc, err := js.state(et, list[:], true, nil); retc != Ok {
+		return retc, err
+	}
+	if ntarg == JSun || ncent == JSun {
+		copy(pv[6*JSun:6*JSun+6], js.pvsun[:])
+	}
+	if ntarg == JSbary || ncent == JSbary {
+		for i := 0; i < 6; i++ {
+			pv[i+6*JSbary] = 0
+		}
+	}
+	if ntarg == JEmb || ncent == JEmb {
+		copy(pv[6*JEmb:6*JEmb+6], pv[6*JEarth:6*JEarth+6])
+	}
+	if (ntarg == JEarth && ncent == JMoon) || (ntarg == JMoon && ncent == JEarth) {
+		for i := 0; i < 6; i++ {
+			pv[i+6*JEarth] = 0
+		}
+	} else {
+		// 文件中为地月质心和地心月球
+		if list[JEarth] == 2 {
+			for i := 0; i < 6; i++ {
+				pv[i+6*JEarth] -= pv[i+6*JMoon] / (js.Header.EMRat + 1.)
+			}
+		}
+		if list[JMoon] == 2 {
+			for i := 0; i < 6; i++ {
+				pv[i+6*JMoon] += pv[i+6*JEarth]
+			}
+		}
+	}
+	for i := 0; i < 6; i++ {
+		rrd[i] = pv[i+ntarg*6] - pv[i+ncent*6]
+	}
+	return Ok, nil
 }
 
-// readJplHeader 读取JPL文件头
-func readJplHeader(header *JplHeader, file *os.File) error {
-	// 定位到文件开始
-	_, err := file.Seek(0, io.SeekStart)
-	if err != nil {
+// readAt 从文件位置pos读取数据，按文件字节序解码
+func (js *JplData) readAt(pos int64, data interface{}) error {
+	if _, err := js.File.Seek(pos, io.SeekStart); err != nil {
 		return err
 	}
-	
-	// 读取第一个记录（文件头）
-	buf := make([]byte, JplRecordSize)
-	_, err = io.ReadFull(file, buf)
+	return binary.Read(js.File, js.order(), data)
+}
+
+// jplHeaderRaw 文件头第一个记录的内容
+type jplHeaderRaw struct {
+	Ttl   [252]byte
+	Cnam  [2400]byte
+	Ss    [3]Float64
+	Ncon  Int32
+	Au    Float64
+	Emrat Float64
+	Ipt   [36]Int32
+	Numde Int32
+	Lpt   [3]Int32
+}
+
+// open 打开JPL文件并读取文件头和常数，检查文件长度和起止时间
+func (js *JplData) open(fname, fpath string) (int, error) {
+	fp, err := sweFopen(-1, fname, fpath)
 	if err != nil {
-		return err
-	}
-	
-	// 解析头部信息
-	reader := NewByteReader(buf, binary.LittleEndian)
-	
-	// 读取基本参数
-	header.StartJD = reader.ReadFloat64()
-	header.EndJD = reader.ReadFloat64()
-	header.StepJD = reader.ReadFloat64()
-	header.NumConst = int(reader.ReadInt32())
-	header.AU = reader.ReadFloat64()
-	header.EMRat = reader.ReadFloat64()
-	
-	// 读取指针表
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 13; j++ {
-			header.IPT[i][j] = int(reader.ReadInt32())
+		return NotAvailable, err
+	}
+	js.File = fp
+	js.FileName = fname
+	// 由段长判断字节序
+	var ss [3]Float64
+	if err := js.readAt(252+2400, &ss); err != nil {
+		return NotAvailable, err
+	}
+	js.doReorder = ss[2] < 1 || ss[2] > 200
+	var h jplHeaderRaw
+	if err := js.readAt(0, &h); err != nil {
+		return NotAvailable, err
+	}
+	// 起止时间必须在-20000年与+20000年之间，段长在1到200天之间
+	if h.Ss[0] < -5583942 || h.Ss[1] > 9025909 || h.Ss[2] < 1 || h.Ss[2] > 200 {
+		return NotAvailable, fmt.Errorf("所给星历文件（%s）的格式无效", fname)
+	}
+	copy(js.ipt[:36], h.Ipt[:])
+	copy(js.ipt[36:], h.Lpt[:])
+	js.denum = h.Numde
+	// 由指针计算记录长度（单精度字数）
+	kmx := Int32(0)
+	khi := 0
+	for i := 0; i < 13; i++ {
+		if js.ipt[i*3] > kmx {
+			kmx = js.ipt[i*3]
+			khi = i + 1
 		}
 	}
-	
-	// 读取系数数量
+	if khi == 0 {
+		return NotAvailable, fmt.Errorf("所给星历文件（%s）的格式无效", fname)
+	}
+	nd := Int32(3)
+	if khi == 12 {
+		nd = 2
+	}
+	ksize := (js.ipt[khi*3-3] + nd*js.ipt[khi*3-2]*js.ipt[khi*3-1] - 1) * 2
+	// DE102文件每个记录有424个空字节
+	if ksize == 1546 {
+		ksize = 1652
+	}
+	if ksize < 1000 || ksize > 5000 {
+		return NotAvailable, fmt.Errorf("JPL星历文件的记录长度无效（%d）", ksize)
+	}
+	js.irecsz = 4 * int64(ksize)
+	js.ncoeffs = int(ksize / 2)
+	js.buf = make([]Float64, js.ncoeffs)
+	// 常数在第二个记录中
+	var cval [400]Float64
+	if err := js.readAt(js.irecsz, &cval); err != nil {
+		return NotAvailable, err
+	}
+	js.Header.StartJD = h.Ss[0]
+	js.Header.EndJD = h.Ss[1]
+	js.Header.StepJD = h.Ss[2]
+	js.Header.NumConst = int(h.Ncon)
+	js.Header.AU = h.Au
+	js.Header.EMRat = h.Emrat
 	for i := 0; i < 13; i++ {
-		header.NumCoeff[i] = int(reader.ReadInt32())
+		for k := 0; k < 3; k++ {
+			js.Header.IPT[k][i] = int(js.ipt[i*3+k])
+		}
+		js.Header.NumCoeff[i] = int(js.ipt[i*3+1])
 	}
-	
-	// 初始化常数表
-	header.Constants = make(map[string]Float64)
-	
-	return nil
-}
-
-// readJplRecord 读取JPL记录
-func readJplRecord(file *os.File, recordNum int) ([]Float64, error) {
-	// 定位到记录位置
-	offset := int64(recordNum * JplRecordSize)
-	_, err := file.Seek(offset, io.SeekStart)
-	if err != nil {
-		return nil, err
+	js.Header.Constants = make(map[string]Float64)
+	for i := 0; i < int(h.Ncon) && i < 400; i++ {
+		name := string(h.Cnam[i*6 : i*6+6])
+		for len(name) > 0 && (name[len(name)-1] == ' ' || name[len(name)-1] == 0) {
+			name = name[:len(name)-1]
+		}
+		js.Header.Constants[name] = cval[i]
 	}
-	
-	// 读取记录
-	buf := make([]byte, JplRecordSize)
-	_, err = io.ReadFull(file, buf)
+	// 检查文件长度
+	flen, err := fp.Seek(0, io.SeekEnd)
 	if err != nil {
-		return nil, err
+		return NotAvailable, err
 	}
-	
-	// 转换为Float64数组
-	reader := NewByteReader(buf, binary.LittleEndian)
-	record := make([]Float64, JplNCoeff)
-	
-	for i := 0; i < JplNCoeff; i++ {
-		record[i] = reader.ReadFloat64()
+	nseg := int64((h.Ss[1] - h.Ss[0]) / h.Ss[2])
+	var nb int64
+	for i := 0; i < 13; i++ {
+		k := int64(3)
+		if i == 11 {
+			k = 2
+		}
+		nb += int64(js.ipt[i*3+1]) * int64(js.ipt[i*3+2]) * k * nseg
 	}
-	
-	return record, nil
+	nb += 2 * nseg
+	nb *= 8
+	nb += 2 * js.irecsz
+	// 有些文件多一个记录
+	if flen != nb && flen-nb != js.irecsz {
+		return NotAvailable, fmt.Errorf("JPL星历文件 %s 已损坏：长度为 %d，应为 %d", fname, flen, nb)
+	}
+	// 检查数据段的起止时间是否与文件头一致
+	var ts [4]Float64
+	if err := js.readAt(2*js.irecsz, ts[0:2]); err != nil {
+		return NotAvailable, err
+	}
+	if err := js.readAt((nseg+2-1)*js.irecsz, ts[2:4]); err != nil {
+		return NotAvailable, err
+	}
+	if ts[0] != h.Ss[0] || ts[3] != h.Ss[1] {
+		return NotAvailable, fmt.Errorf("JPL星历文件已损坏：起止时间检查失败 %.1f != %.1f || %.1f != %.1f", ts[0], h.Ss[0], ts[3], h.Ss[1])
+	}
+	return Ok, nil
 }
 
-// computePosition 计算天体位置
-func computePosition(record []Float64, et Float64, ntarg, ncent int, rrd *[6]Float64) error {
-	var pv [15][6]Float64  // 增大数组以容纳所有JPL天体
-	var t [2]Float64
-	
-	// 计算归一化时间
-	s := et - record[0]
-	t[0] = s / record[1]
-	t[1] = 1.0
-	
-	// 计算各天体位置
-	for k := 0; k < 11; k++ {
-		// 获取切比雪夫多项式参数
-		na := jplData.Header.IPT[0][k]
-		nc := jplData.Header.IPT[1][k]
-		ns := jplData.Header.IPT[2][k]
-		
-		if na == 0 {
-			continue
-		}
-		
-		// 计算子区间
-		temp := Float64(ns) * t[0]
-		l := int(temp)
-		if l >= ns {
-			l = ns - 1
-		}
-		
-		tc := 2.0*(temp-Float64(l)) - 1.0
-		
-		// 计算切比雪夫多项式
-		err := interpolateChebyshev(record, na+l*nc*3, nc, tc, pv[k][:])
-		if err != nil {
-			return err
+// state 读取并插值JPL星历
+// list[i]为0表示不计算天体i，1只计算位置，2计算位置和速度
+// doBary为假时行星为日心坐标；章动写入nut
+// 与JPL的STATE相同，et分为午夜和日内的时间两部分
+func (js *JplData) state(et JulianDay, list []Int32, doBary bool, nut []Float64) (int, error) {
+	ss := [3]Float64{js.Header.StartJD, js.Header.EndJD, js.Header.StepJD}
+	ipt := js.ipt[:]
+	s := et.Day - .5
+	etMn := math.Floor(s)
+	etFr := s - etMn + et.Frac // 自前一个午夜起的天数
+	fl := math.Floor(etFr)
+	etMn += fl
+	etFr -= fl
+	etMn += .5 // 历元之前的午夜
+	if tjd := et.Float64(); tjd < ss[0] || tjd > ss[1] {
+		return BeyondEphLimits, fmt.Errorf("儒略日 %f 超出JPL星历范围 %.2f .. %.2f", tjd, ss[0], ss[1])
+	}
+	// 记录号和区间内的相对时间
+	nr := int64((etMn-ss[0])/ss[2]) + 2
+	if etMn == ss[1] {
+		nr-- // 星历终点，使用最后一个记录
+	}
+	t := (etMn - (Float64(nr-2)*ss[2] + ss[0]) + etFr) / ss[2]
+	if nr != js.nrl {
+		if err := js.readAt(nr*js.irecsz, js.buf); err != nil {
+			js.nrl = 0
+			return NotAvailable, fmt.Errorf("读取JPL星历出错，儒略日 %f", et.Float64())
 		}
-		
-		// 缩放速度
-		vfac := 2.0 * Float64(ns) / record[1]
-		pv[k][3] *= vfac
-		pv[k][4] *= vfac
-		pv[k][5] *= vfac
+		js.nrl = nr
 	}
-	
-	// 处理特殊情况（如月球相对于地月系质心的位置）
-	if ntarg == JMoon {
-		// 月球位置 = 地月系质心位置 + 月球相对地月系质心位置
-		for i := 0; i < 6; i++ {
-			if JEmb < 15 && JMoon < 15 {
-				pv[JMoon][i] = pv[JEmb][i] + pv[JMoon][i]
-			}
-		}
+	intv := ss[2]
+	aufac := 1. / js.Header.AU
+	// 质心太阳
+	js.interp(js.buf[ipt[30]-1:], t, intv, int(ipt[31]), 3, int(ipt[32]), 2, js.pvsun[:])
+	for i := 0; i < 6; i++ {
+		js.pvsun[i] *= aufac
 	}
-	
-	// 计算相对位置
-	if ntarg == ncent {
-		// 相对于自身
-		for i := 0; i < 6; i++ {
-			rrd[i] = 0.0
-		}
-	} else if ncent == JSbary {
-		// 相对于太阳系质心
-		for i := 0; i < 6; i++ {
-			if ntarg < 15 {
-				rrd[i] = pv[ntarg][i]
-			}
+	// 所需的各天体
+	for i := 0; i < 10; i++ {
+		if list[i] <= 0 {
+			continue
 		}
-	} else {
-		// 相对于其他天体
-		for i := 0; i < 6; i++ {
-			if ntarg < 15 && ncent < 15 {
-				rrd[i] = pv[ntarg][i] - pv[ncent][i]
+		pv := js.pv[i*6 : i*6+6]
+		js.interp(js.buf[ipt[i*3]-1:], t, intv, int(ipt[i*3+1]), 3, int(ipt[i*3+2]), int(list[i]), pv)
+		for j := 0; j < 6; j++ {
+			if i < 9 && !doBary {
+				pv[j] = pv[j]*aufac - js.pvsun[j]
+			} else {
+				pv[j] *= aufac
 			}
 		}
 	}
-	
-	return nil
+	// 章动
+	if list[10] > 0 && ipt[34] > 0 {
+		js.interp(js.buf[ipt[33]-1:], t, intv, int(ipt[34]), 2, int(ipt[35]), int(list[10]), nut)
+	}
+	// 天平动
+	if list[11] > 0 && ipt[37] > 0 {
+		js.interp(js.buf[ipt[36]-1:], t, intv, int(ipt[37]), 3, int(ipt[38]), int(list[11]), js.pv[60:])
+	}
+	return Ok, nil
 }
 
-// interpolateChebyshev 切比雪夫多项式插值
-func interpolateChebyshev(coeffs []Float64, offset, ncoeff int, x Float64, result []Float64) error {
-	// 初始化
-	for i := 0; i < 6; i++ {
-		result[i] = 0.0
+// interp 由切比雪夫系数插值位置（ifl=1）或位置和速度（ifl=2）
+// t为区间内的相对时间(0..1)，intv为区间长度，ncf为每分量系数个数，
+// ncm为分量个数，na为子区间个数
+func (js *JplData) interp(buf []Float64, t, intv Float64, ncf, ncm, na, ifl int, pv []Float64) {
+	pc := js.pc[:]
+	vc := js.vc[:]
+	var dt1 Float64
+	if t >= 0 {
+		dt1 = math.Floor(t)
+	} else {
+		dt1 = -math.Floor(-t)
+	}
+	temp := Float64(na) * t
+	ni := int(temp - dt1)
+	// 归一化的切比雪夫时间（-1 <= tc <= 1）
+	tc := (math.Mod(temp, 1.0)+dt1)*2. - 1.
+	if tc != pc[1] {
+		js.np = 2
+		js.nv = 3
+		pc[1] = tc
+		js.twot = tc + tc
+	}
+	twot := js.twot
+	if js.np < ncf {
+		for i := js.np; i < ncf; i++ {
+			pc[i] = twot*pc[i-1] - pc[i-2]
+		}
+		js.np = ncf
 	}
-	
-	if ncoeff < 2 {
-		return fmt.Errorf("系数数量太少: %d", ncoeff)
+	// 位置
+	for i := 0; i < ncm; i++ {
+		pv[i] = 0.
+		for j := ncf - 1; j >= 0; j-- {
+			pv[i] += pc[j] * buf[j+(i+ni*ncm)*ncf]
+		}
 	}
-	
-	// 计算切比雪夫多项式
-	t := make([]Float64, ncoeff)
-	t[0] = 1.0
-	t[1] = x
-	
-	for i := 2; i < ncoeff; i++ {
-		t[i] = 2.0*x*t[i-1] - t[i-2]
+	if ifl <= 1 {
+		return
 	}
-	
-	// 计算位置（x, y, z）
-	for j := 0; j < 3; j++ {
-		for i := 0; i < ncoeff; i++ {
-			result[j] += coeffs[offset+j*ncoeff+i] * t[i]
+	// 速度
+	bma := Float64(na+na) / intv
+	vc[2] = twot + twot
+	if js.nv < ncf {
+		for i := js.nv; i < ncf; i++ {
+			vc[i] = twot*vc[i-1] + pc[i-1] + pc[i-1] - vc[i-2]
 		}
+		js.nv = ncf
 	}
-	
-	// 计算速度（vx, vy, vz）
-	if ncoeff >= 2 {
-		dt := make([]Float64, ncoeff)
-		dt[0] = 0.0
-		dt[1] = 1.0
-		
-		for i := 2; i < ncoeff; i++ {
-			dt[i] = 2.0*x*dt[i-1] + 2.0*t[i-1] - dt[i-2]
-		}
-		
-		for j := 0; j < 3; j++ {
-			for i := 0; i < ncoeff; i++ {
-				result[j+3] += coeffs[offset+j*ncoeff+i] * dt[i]
-			}
+	for i := 0; i < ncm; i++ {
+		pv[i+ncm] = 0.
+		for j := ncf - 1; j >= 1; j-- {
+			pv[i+ncm] += vc[j] * buf[j+(i+ni*ncm)*ncf]
 		}
+		pv[i+ncm] *= bma
 	}
-	
-	return nil
 }
 
 // ByteReader 字节读取器
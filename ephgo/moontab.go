@@ -0,0 +1,394 @@
+// 本文件的数据表取自 swemmoon.c（Moshier月球理论，拟合DE404）。
+
+package ephgo
+
+// moonZ 与DE404拟合得到的长期项和行星摄动项系数
+var moonZ = [...]Float64{
+	-1.312045233711e+01, -1.138215912580e-03, -9.646018347184e-06, 3.146734198839e+01,
+	4.768357585780e-02, -3.421689790404e-04, -6.847070905410e+00, -5.834100476561e-03,
+	-2.905334122698e-04, -5.663161722088e+00, 5.722859298199e-03, -8.466472828815e-05,
+	-8.429817796435e+01, -2.072552484689e+02, 7.876842214863e+00, 1.836463749022e+00,
+	-1.557471855361e+01, -2.006969124724e+01, 2.152670284757e+01, -6.179946916139e+00,
+	-9.070028191196e-01, -1.270848233038e+01, -2.145589319058e+00, 1.381936399935e+01,
+	-1.999840061168e+00,
+}
+
+// moonLR 黄经和距离的周期项（D l' l F，1" .0001" 1km .0001km）
+var moonLR = [...]int16{
+	0, 0, 1, 0, 22639, 5858, -20905, -3550,
+	2, 0, -1, 0, 4586, 4383, -3699, -1109,
+	2, 0, 0, 0, 2369, 9139, -2955, -9676,
+	0, 0, 2, 0, 769, 257, -569, -9251,
+	0, 1, 0, 0, -666, -4171, 48, 8883,
+	0, 0, 0, 2, -411, -5957, -3, -1483,
+	2, 0, -2, 0, 211, 6556, 246, 1585,
+	2, -1, -1, 0, 205, 4358, -152, -1377,
+	2, 0, 1, 0, 191, 9562, -170, -7331,
+	2, -1, 0, 0, 164, 7285, -204, -5860,
+	0, 1, -1, 0, -147, -3213, -129, -6201,
+	1, 0, 0, 0, -124, -9881, 108, 7427,
+	0, 1, 1, 0, -109, -3803, 104, 7552,
+	2, 0, 0, -2, 55, 1771, 10, 3211,
+	0, 0, 1, 2, -45, -996, 0, 0,
+	0, 0, 1, -2, 39, 5333, 79, 6606,
+	4, 0, -1, 0, 38, 4298, -34, -7825,
+	0, 0, 3, 0, 36, 1238, -23, -2104,
+	4, 0, -2, 0, 30, 7726, -21, -6363,
+	2, 1, -1, 0, -28, -3971, 24, 2085,
+	2, 1, 0, 0, -24, -3582, 30, 8238,
+	1, 0, -1, 0, -18, -5847, -8, -3791,
+	1, 1, 0, 0, 17, 9545, -16, -6747,
+	2, -1, 1, 0, 14, 5303, -12, -8314,
+	2, 0, 2, 0, 14, 3797, -10, -4448,
+	4, 0, 0, 0, 13, 8991, -11, -6500,
+	2, 0, -3, 0, 13, 1941, 14, 4027,
+	0, 1, -2, 0, -9, -6791, -7, -27,
+	2, 0, -1, 2, -9, -3659, 0, 7740,
+	2, -1, -2, 0, 8, 6055, 10, 562,
+	1, 0, 1, 0, -8, -4531, 6, 3220,
+	2, -2, 0, 0, 8, 502, -9, -8845,
+	0, 1, 2, 0, -7, -6302, 5, 7509,
+	0, 2, 0, 0, -7, -4475, 1, 657,
+	2, -2, -1, 0, 7, 3712, -4, -9501,
+	2, 0, 1, -2, -6, -3832, 4, 1311,
+	2, 0, 0, 2, -5, -7416, 0, 0,
+	4, -1, -1, 0, 4, 3740, -3, -9580,
+	0, 0, 2, 2, -3, -9976, 0, 0,
+	3, 0, -1, 0, -3, -2097, 3, 2582,
+	2, 1, 1, 0, -2, -9145, 2, 6164,
+	4, -1, -2, 0, 2, 7319, -1, -8970,
+	0, 2, -1, 0, -2, -5679, -2, -1171,
+	2, 2, -1, 0, -2, -5212, 2, 3536,
+	2, 1, -2, 0, 2, 4889, 0, 1437,
+	2, -1, 0, -2, 2, 1461, 0, 6571,
+	4, 0, 1, 0, 1, 9777, -1, -4226,
+	0, 0, 4, 0, 1, 9337, -1, -1169,
+	4, -1, 0, 0, 1, 8708, -1, -5714,
+	1, 0, -2, 0, -1, -7530, -1, -7385,
+	2, 1, 0, -2, -1, -4372, 0, -1357,
+	0, 0, 2, -2, -1, -3726, -4, -4212,
+	1, 1, 1, 0, 1, 2618, 0, -9333,
+	3, 0, -2, 0, -1, -2241, 0, 8624,
+	4, 0, -3, 0, 1, 1868, 0, -5142,
+	2, -1, 2, 0, 1, 1770, 0, -8488,
+	0, 2, 1, 0, -1, -1617, 1, 1655,
+	1, 1, -1, 0, 1, 777, 0, 8512,
+	2, 0, 3, 0, 1, 595, 0, -6697,
+	2, 0, 1, 2, 0, -9902, 0, 0,
+	2, 0, -4, 0, 0, 9483, 0, 7785,
+	2, -2, 1, 0, 0, 7517, 0, -6575,
+	0, 1, -3, 0, 0, -6694, 0, -4224,
+	4, 1, -1, 0, 0, -6352, 0, 5788,
+	1, 0, 2, 0, 0, -5840, 0, 3785,
+	1, 0, 0, -2, 0, -5833, 0, -7956,
+	6, 0, -2, 0, 0, 5716, 0, -4225,
+	2, 0, -2, -2, 0, -5606, 0, 4726,
+	1, -1, 0, 0, 0, -5569, 0, 4976,
+	0, 1, 3, 0, 0, -5459, 0, 3551,
+	2, 0, -2, 2, 0, -5357, 0, 7740,
+	2, 0, -1, -2, 0, 1790, 8, 7516,
+	3, 0, 0, 0, 0, 4042, -1, -4189,
+	2, -1, -3, 0, 0, 4784, 0, 4950,
+	2, -1, 3, 0, 0, 932, 0, -585,
+	2, 0, 2, -2, 0, -4538, 0, 2840,
+	2, -1, -1, 2, 0, -4262, 0, 373,
+	0, 0, 0, 4, 0, 4203, 0, 0,
+	0, 1, 0, 2, 0, 4134, 0, -1580,
+	6, 0, -1, 0, 0, 3945, 0, -2866,
+	2, -1, 0, 2, 0, -3821, 0, 0,
+	2, -1, 1, -2, 0, -3745, 0, 2094,
+	4, 1, -2, 0, 0, -3576, 0, 2370,
+	1, 1, -2, 0, 0, 3497, 0, 3323,
+	2, -3, 0, 0, 0, 3398, 0, -4107,
+	0, 0, 3, 2, 0, -3286, 0, 0,
+	4, -2, -1, 0, 0, -3087, 0, -2790,
+	0, 1, -1, -2, 0, 3015, 0, 0,
+	4, 0, -1, -2, 0, 3009, 0, -3218,
+	2, -2, -2, 0, 0, 2942, 0, 3430,
+	6, 0, -3, 0, 0, 2925, 0, -1832,
+	2, 1, 2, 0, 0, -2902, 0, 2125,
+	4, 1, 0, 0, 0, -2891, 0, 2445,
+	4, -1, 1, 0, 0, 2825, 0, -2029,
+	3, 1, -1, 0, 0, 2737, 0, -2126,
+	0, 1, 1, 2, 0, 2634, 0, 0,
+	1, 0, 0, 2, 0, 2543, 0, 0,
+	3, 0, 0, -2, 0, -2530, 0, 2010,
+	2, 2, -2, 0, 0, -2499, 0, -1089,
+	2, -3, -1, 0, 0, 2469, 0, -1481,
+	3, -1, -1, 0, 0, -2314, 0, 2556,
+	4, 0, 2, 0, 0, 2185, 0, -1392,
+	4, 0, -1, 2, 0, -2013, 0, 0,
+	0, 2, -2, 0, 0, -1931, 0, 0,
+	2, 2, 0, 0, 0, -1858, 0, 0,
+	2, 1, -3, 0, 0, 1762, 0, 0,
+	4, 0, -2, 2, 0, -1698, 0, 0,
+	4, -2, -2, 0, 0, 1578, 0, -1083,
+	4, -2, 0, 0, 0, 1522, 0, -1281,
+	3, 1, 0, 0, 0, 1499, 0, -1077,
+	1, -1, -1, 0, 0, -1364, 0, 1141,
+	1, -3, 0, 0, 0, -1281, 0, 0,
+	6, 0, 0, 0, 0, 1261, 0, -859,
+	2, 0, 2, 2, 0, -1239, 0, 0,
+	1, -1, 1, 0, 0, -1207, 0, 1100,
+	0, 0, 5, 0, 0, 1110, 0, -589,
+	0, 3, 0, 0, 0, -1013, 0, 213,
+	4, -1, -3, 0, 0, 998, 0, 0,
+}
+
+// moonMB 黄纬的周期项（D l' l F，1" .0001"）
+var moonMB = [...]int16{
+	0, 0, 0, 1, 18461, 2387,
+	0, 0, 1, 1, 1010, 1671,
+	0, 0, 1, -1, 999, 6936,
+	2, 0, 0, -1, 623, 6524,
+	2, 0, -1, 1, 199, 4837,
+	2, 0, -1, -1, 166, 5741,
+	2, 0, 0, 1, 117, 2607,
+	0, 0, 2, 1, 61, 9120,
+	2, 0, 1, -1, 33, 3572,
+	0, 0, 2, -1, 31, 7597,
+	2, -1, 0, -1, 29, 5766,
+	2, 0, -2, -1, 15, 5663,
+	2, 0, 1, 1, 15, 1216,
+	2, 1, 0, -1, -12, -941,
+	2, -1, -1, 1, 8, 8681,
+	2, -1, 0, 1, 7, 9586,
+	2, -1, -1, -1, 7, 4346,
+	0, 1, -1, -1, -6, -7314,
+	4, 0, -1, -1, 6, 5796,
+	0, 1, 0, 1, -6, -4601,
+	0, 0, 0, 3, -6, -2965,
+	0, 1, -1, 1, -5, -6324,
+	1, 0, 0, 1, -5, -3684,
+	0, 1, 1, 1, -5, -3113,
+	0, 1, 1, -1, -5, -759,
+	0, 1, 0, -1, -4, -8396,
+	1, 0, 0, -1, -4, -8057,
+	0, 0, 3, 1, 3, 9841,
+	4, 0, 0, -1, 3, 6745,
+	4, 0, -1, 1, 2, 9985,
+	0, 0, 1, -3, 2, 7986,
+	4, 0, -2, 1, 2, 4139,
+	2, 0, 0, -3, 2, 1863,
+	2, 0, 2, -1, 2, 1462,
+	2, -1, 1, -1, 1, 7660,
+	2, 0, -2, 1, -1, -6244,
+	0, 0, 3, -1, 1, 5813,
+	2, 0, 2, 1, 1, 5198,
+	2, 0, -3, -1, 1, 5156,
+	2, 1, -1, 1, -1, -3178,
+	2, 1, 0, 1, -1, -2643,
+	4, 0, 0, 1, 1, 1919,
+	2, -1, 1, 1, 1, 1346,
+	2, -2, 0, -1, 1, 859,
+	0, 0, 1, 3, -1, -194,
+	2, 1, 1, -1, 0, -8227,
+	1, 1, 0, -1, 0, 8042,
+	1, 1, 0, 1, 0, 8026,
+	0, 1, -2, -1, 0, -7932,
+	2, 1, -1, -1, 0, -7910,
+	1, 0, 1, 1, 0, -6674,
+	2, -1, -2, -1, 0, 6502,
+	0, 1, 2, 1, 0, -6388,
+	4, 0, -2, -1, 0, 6337,
+	4, -1, -1, -1, 0, 5958,
+	1, 0, 1, -1, 0, -5889,
+	4, 0, 1, -1, 0, 4734,
+	1, 0, -1, -1, 0, -4299,
+	4, -1, 0, -1, 0, 4149,
+	2, -2, 0, 1, 0, 3835,
+	3, 0, 0, -1, 0, -3518,
+	4, -1, -1, 1, 0, 3388,
+	2, 0, -1, -3, 0, 3291,
+	2, -2, -1, 1, 0, 3147,
+	0, 1, 2, -1, 0, -3129,
+	3, 0, -1, -1, 0, -3052,
+	0, 1, -2, 1, 0, -3013,
+	2, 0, 1, -3, 0, -2912,
+	2, -2, -1, -1, 0, 2686,
+	0, 0, 4, 1, 0, 2633,
+	2, 0, -3, 1, 0, 2541,
+	2, 0, -1, 3, 0, -2448,
+	2, 1, 1, 1, 0, -2370,
+	4, -1, -2, 1, 0, 2138,
+	4, 0, 1, 1, 0, 2126,
+	3, 0, -1, 1, 0, -2059,
+	4, 1, -1, -1, 0, -1719,
+}
+
+// moonLRT 乘以T的黄经和距离周期项
+var moonLRT = [...]int16{
+	0, 1, 0, 0, 16, 7680, -1, -2302,
+	2, -1, -1, 0, -5, -1642, 3, 8245,
+	2, -1, 0, 0, -4, -1383, 5, 1395,
+	0, 1, -1, 0, 3, 7115, 3, 2654,
+	0, 1, 1, 0, 2, 7560, -2, -6396,
+	2, 1, -1, 0, 0, 7118, 0, -6068,
+	2, 1, 0, 0, 0, 6128, 0, -7754,
+	1, 1, 0, 0, 0, -4516, 0, 4194,
+	2, -2, 0, 0, 0, -4048, 0, 4970,
+	0, 2, 0, 0, 0, 3747, 0, -540,
+	2, -2, -1, 0, 0, -3707, 0, 2490,
+	2, -1, 1, 0, 0, -3649, 0, 3222,
+	0, 1, -2, 0, 0, 2438, 0, 1760,
+	2, -1, -2, 0, 0, -2165, 0, -2530,
+	0, 1, 2, 0, 0, 1923, 0, -1450,
+	0, 2, -1, 0, 0, 1292, 0, 1070,
+	2, 2, -1, 0, 0, 1271, 0, -6070,
+	4, -1, -1, 0, 0, -1098, 0, 990,
+	2, 0, 0, 0, 0, 1073, 0, -1360,
+	2, 0, -1, 0, 0, 839, 0, -630,
+	2, 1, 1, 0, 0, 734, 0, -660,
+	4, -1, -2, 0, 0, -688, 0, 480,
+	2, 1, -2, 0, 0, -630, 0, 0,
+	0, 2, 1, 0, 0, 587, 0, -590,
+	2, -1, 0, -2, 0, -540, 0, -170,
+	4, -1, 0, 0, 0, -468, 0, 390,
+	2, -2, 1, 0, 0, -378, 0, 330,
+	2, 1, 0, -2, 0, 364, 0, 0,
+	1, 1, 1, 0, 0, -317, 0, 240,
+	2, -1, 2, 0, 0, -295, 0, 210,
+	1, 1, -1, 0, 0, -270, 0, -210,
+	2, -3, 0, 0, 0, -256, 0, 310,
+	2, -3, -1, 0, 0, -187, 0, 110,
+	0, 1, -3, 0, 0, 169, 0, 110,
+	4, 1, -1, 0, 0, 158, 0, -150,
+	4, -2, -1, 0, 0, -155, 0, 140,
+	0, 0, 1, 0, 0, 155, 0, -250,
+	2, -2, -2, 0, 0, -148, 0, -170,
+}
+
+// moonBT 乘以T的黄纬周期项
+var moonBT = [...]int16{
+	2, -1, 0, -1, -7430,
+	2, 1, 0, -1, 3043,
+	2, -1, -1, 1, -2229,
+	2, -1, 0, 1, -1999,
+	2, -1, -1, -1, -1869,
+	0, 1, -1, -1, 1696,
+	0, 1, 0, 1, 1623,
+	0, 1, -1, 1, 1418,
+	0, 1, 1, 1, 1339,
+	0, 1, 1, -1, 1278,
+	0, 1, 0, -1, 1217,
+	2, -2, 0, -1, -547,
+	2, -1, 1, -1, -443,
+	2, 1, -1, 1, 331,
+	2, 1, 0, 1, 317,
+	2, 0, 0, -1, 295,
+}
+
+// moonLRT2 乘以T²的黄经和距离周期项
+var moonLRT2 = [...]int16{
+	0, 1, 0, 0, 487, -36,
+	2, -1, -1, 0, -150, 111,
+	2, -1, 0, 0, -120, 149,
+	0, 1, -1, 0, 108, 95,
+	0, 1, 1, 0, 80, -77,
+	2, 1, -1, 0, 21, -18,
+	2, 1, 0, 0, 20, -23,
+	1, 1, 0, 0, -13, 12,
+	2, -2, 0, 0, -12, 14,
+	2, -1, 1, 0, -11, 9,
+	2, -2, -1, 0, -11, 7,
+	0, 2, 0, 0, 11, 0,
+	2, -1, -2, 0, -6, -7,
+	0, 1, -2, 0, 7, 5,
+	0, 1, 2, 0, 6, -4,
+	2, 2, -1, 0, 5, -3,
+	0, 2, -1, 0, 5, 3,
+	4, -1, -1, 0, -3, 3,
+	2, 0, 0, 0, 3, -4,
+	4, -1, -2, 0, -2, 0,
+	2, 1, -2, 0, -2, 0,
+	2, -1, 0, -2, -2, 0,
+	2, 1, 1, 0, 2, -2,
+	2, 0, -1, 0, 2, 0,
+	0, 2, 1, 0, 2, 0,
+}
+
+// moonBT2 乘以T²的黄纬周期项
+var moonBT2 = [...]int16{
+	2, -1, 0, -1, -22,
+	2, 1, 0, -1, 9,
+	2, -1, 0, 1, -6,
+	2, -1, -1, 1, -6,
+	2, -1, -1, -1, -5,
+	0, 1, 0, 1, 5,
+	0, 1, -1, -1, 5,
+	0, 1, 1, 1, 4,
+	0, 1, 1, -1, 4,
+	0, 1, 0, -1, 4,
+	0, 1, -1, 1, 4,
+	2, -2, 0, -1, -2,
+}
+
+// moonMeanNodeCorr 平均月交点的修正值（度），-13100年至17200年，每100年一个值
+var moonMeanNodeCorr = [...]Float64{
+	-2.56, -2.473, -2.392347, -2.316425, -2.239639, -2.167764, -2.095100, -2.024810, -1.957622, -1.890097,
+	-1.826389, -1.763335, -1.701047, -1.643016, -1.584186, -1.527309, -1.473352, -1.418917, -1.367736, -1.317202,
+	-1.267269, -1.221121, -1.174218, -1.128862, -1.086214, -1.042998, -1.002491, -0.962635, -0.923176, -0.887191,
+	-0.850403, -0.814929, -0.782117, -0.748462, -0.717241, -0.686598, -0.656013, -0.628726, -0.600460, -0.573219,
+	-0.548634, -0.522931, -0.499285, -0.476273, -0.452978, -0.432663, -0.411386, -0.390788, -0.372825, -0.353681,
+	-0.336230, -0.319520, -0.302343, -0.287794, -0.272262, -0.257166, -0.244534, -0.230635, -0.218126, -0.206365,
+	-0.194000, -0.183876, -0.172782, -0.161877, -0.153254, -0.143371, -0.134501, -0.126552, -0.117932, -0.111199,
+	-0.103716, -0.096160, -0.090718, -0.084046, -0.078007, -0.072959, -0.067235, -0.062990, -0.058102, -0.053070,
+	-0.049786, -0.045381, -0.041317, -0.038165, -0.034501, -0.031871, -0.028844, -0.025701, -0.024018, -0.021427,
+	-0.018881, -0.017291, -0.015186, -0.013755, -0.012098, -0.010261, -0.009688, -0.008218, -0.006670, -0.005979,
+	-0.004756, -0.003991, -0.002996, -0.001974, -0.001975, -0.001213, -0.000377, -0.000356, 5.779e-05, 0.000378,
+	0.000710, 0.001092, 0.000767, 0.000985, 0.001443, 0.001069, 0.001141, 0.001321, 0.001462, 0.001695,
+	0.001319, 0.001567, 0.001873, 0.001376, 0.001336, 0.001347, 0.001330, 0.001256, 0.000813, 0.000946,
+	0.001079, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, -0.000364, -0.000452, -0.001091, -0.001159, -0.001136, -0.001798, -0.002249, -0.002622, -0.002990,
+	-0.003555, -0.004425, -0.004758, -0.005134, -0.006065, -0.006839, -0.007474, -0.008283, -0.009411, -0.010786,
+	-0.011810, -0.012989, -0.014825, -0.016426, -0.017922, -0.019774, -0.021881, -0.024194, -0.026190, -0.028440,
+	-0.031285, -0.033817, -0.036318, -0.039212, -0.042456, -0.045799, -0.048994, -0.052710, -0.056948, -0.061017,
+	-0.065181, -0.069843, -0.074922, -0.079976, -0.085052, -0.090755, -0.096840, -0.102797, -0.108939, -0.115568,
+	-0.122636, -0.129593, -0.136683, -0.144641, -0.152825, -0.161044, -0.169758, -0.178916, -0.188712, -0.198401,
+	-0.208312, -0.219395, -0.230407, -0.241577, -0.253508, -0.265640, -0.278556, -0.291330, -0.304353, -0.318815,
+	-0.332882, -0.347316, -0.362895, -0.378421, -0.395061, -0.411748, -0.428666, -0.447477, -0.465636, -0.484277,
+	-0.504600, -0.524405, -0.545533, -0.567020, -0.588404, -0.612099, -0.634965, -0.658262, -0.683866, -0.708526,
+	-0.734719, -0.761800, -0.788562, -0.818092, -0.846885, -0.876177, -0.908385, -0.939371, -0.972027, -1.006149,
+	-1.039634, -1.076135, -1.112156, -1.148490, -1.188312, -1.226761, -1.266821, -1.309156, -1.350583, -1.395223,
+	-1.440028, -1.485047, -1.534104, -1.582023, -1.631506, -1.684031, -1.735687, -1.790421, -1.846039, -1.901951,
+	-1.961872, -2.021179, -2.081987, -2.146259, -2.210031, -2.276609, -2.344904, -2.413795, -2.486559, -2.559564,
+	-2.634215, -2.712692, -2.791289, -2.872533, -2.956217, -3.040965, -3.129234, -3.218545, -3.309805, -3.404827,
+	-3.5008, -3.601, -3.7, -3.8,
+}
+
+// moonMeanApsisCorr 平均月远地点的修正值（度），-13100年至17200年，每100年一个值
+var moonMeanApsisCorr = [...]Float64{
+	7.525, 7.290, 7.057295, 6.830813, 6.611723, 6.396775, 6.189569, 5.985968, 5.788342, 5.597304,
+	5.410167, 5.229946, 5.053389, 4.882187, 4.716494, 4.553532, 4.396734, 4.243718, 4.094282, 3.950865,
+	3.810366, 3.674978, 3.543284, 3.414270, 3.290526, 3.168775, 3.050904, 2.937541, 2.826189, 2.719822,
+	2.616193, 2.515431, 2.419193, 2.323782, 2.232545, 2.143635, 2.056803, 1.974913, 1.893874, 1.816201,
+	1.741957, 1.668083, 1.598335, 1.529645, 1.463016, 1.399693, 1.336905, 1.278097, 1.220965, 1.165092,
+	1.113071, 1.060858, 1.011007, 0.963701, 0.916523, 0.872887, 0.829596, 0.788486, 0.750017, 0.711177,
+	0.675589, 0.640303, 0.605303, 0.573490, 0.541113, 0.511482, 0.483159, 0.455210, 0.430305, 0.404643,
+	0.380782, 0.358524, 0.335405, 0.315244, 0.295131, 0.275766, 0.259223, 0.241586, 0.225890, 0.210404,
+	0.194775, 0.181573, 0.167246, 0.154514, 0.143435, 0.131131, 0.121648, 0.111835, 0.102474, 0.094284,
+	0.085204, 0.078240, 0.070697, 0.063696, 0.058894, 0.052390, 0.047632, 0.043129, 0.037823, 0.034143,
+	0.029188, 0.025648, 0.021972, 0.018348, 0.017127, 0.013989, 0.011967, 0.011003, 0.007865, 0.007033,
+	0.005574, 0.004060, 0.003699, 0.002465, 0.002889, 0.002144, 0.001018, 0.001757, -9.67e-05, -0.000734,
+	-0.000392, -0.001546, -0.000863, -0.001266, -0.000933, -0.000503, -0.001304, 0.000238, -0.000507, -0.000897,
+	0.000647, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0,
+	0.0, 0.000514, 0.000683, 0.002228, 0.001974, 0.003485, 0.004280, 0.005409, 0.007468, 0.007938,
+	0.011012, 0.012525, 0.013757, 0.016757, 0.017932, 0.020780, 0.023416, 0.026386, 0.030428, 0.033512,
+	0.038789, 0.043126, 0.047778, 0.054175, 0.058891, 0.065878, 0.072345, 0.079668, 0.088238, 0.095307,
+	0.104873, 0.113533, 0.122336, 0.133205, 0.142922, 0.154871, 0.166488, 0.179234, 0.193928, 0.207262,
+	0.223089, 0.238736, 0.254907, 0.273232, 0.291085, 0.311046, 0.331025, 0.351955, 0.374422, 0.396341,
+	0.420772, 0.444867, 0.469984, 0.497448, 0.524717, 0.554752, 0.584581, 0.616272, 0.649744, 0.682947,
+	0.719405, 0.755834, 0.793780, 0.833875, 0.873893, 0.917340, 0.960429, 1.005471, 1.052384, 1.099317,
+	1.149508, 1.200130, 1.253038, 1.307672, 1.363480, 1.422592, 1.481900, 1.544111, 1.607982, 1.672954,
+	1.741025, 1.809727, 1.882038, 1.955243, 2.029956, 2.108428, 2.186805, 2.268697, 2.352071, 2.437370,
+	2.525903, 2.615415, 2.709082, 2.804198, 2.901704, 3.002606, 3.104412, 3.210406, 3.317733, 3.428386,
+	3.541634, 3.656634, 3.775988, 3.896306, 4.020480, 4.146814, 4.275356, 4.408257, 4.542282, 4.681174,
+	4.822524, 4.966424, 5.114948, 5.264973, 5.419906, 5.577056, 5.737688, 5.902347, 6.069138, 6.241065,
+	6.415155, 6.593317, 6.774853, 6.959322, 7.148845, 7.340334, 7.537156, 7.737358, 7.940882, 8.149932,
+	8.361576, 8.579150, 8.799591, 9.024378, 9.254584, 9.487362, 9.726535, 9.968784, 10.216089, 10.467716,
+	10.725293, 10.986, 11.25, 11.52,
+}
@@ -0,0 +1,322 @@
+// Moshier行星星历，移植自 swemplan.c。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Moshier行星星历的有效范围
+const (
+	moshplephStart = 625000.5
+	moshplephEnd   = 2818000.5
+)
+
+// moshTimescale Moshier行星表的时间单位（一万儒略年）
+const moshTimescale = 3652500.0
+
+// pnoint2msh 内部行星编号到Moshier行星表的映射
+var pnoint2msh = [...]int{2, 2, 0, 1, 3, 4, 5, 6, 7, 8}
+
+// moshFreqs 平黄经的频率（角秒/万儒略年），Simon等（1994）
+var moshFreqs = [9]Float64{
+	53810162868.8982,
+	21066413643.3548,
+	12959774228.3429,
+	6890507749.3988,
+	1092566037.7991,
+	439960985.5372,
+	154248119.3933,
+	78655032.0744,
+	52272245.1795,
+}
+
+// moshPhases 平黄经的初相（角秒）
+var moshPhases = [9]Float64{
+	252.25090552 * 3600.,
+	181.97980085 * 3600.,
+	100.46645683 * 3600.,
+	355.43299958 * 3600.,
+	34.35151874 * 3600.,
+	50.07744430 * 3600.,
+	314.05500511 * 3600.,
+	304.34866548 * 3600.,
+	860492.1546,
+}
+
+// moshPlanets Moshier行星表，按pnoint2msh索引
+var moshPlanets = [...]*PlantBl{
+	&mer404, &ven404, &ear404, &mar404, &jup404,
+	&sat404, &ura404, &nep404, &plu404,
+}
+
+// moshplan2 计算行星的日心J2000黄道极坐标（弧度，天文单位）
+func moshplan2(J Float64, iplm int, pobj []Float64) {
+	var ss, cc [9][24]Float64
+	plan := moshPlanets[iplm]
+	T := (J - J2000) / moshTimescale
+	// 计算所需各倍角的正弦和余弦
+	for i := 0; i < 9; i++ {
+		if j := int(plan.MaxHarmonic[i]); j > 0 {
+			sr := (mods3600(moshFreqs[i]*T) + moshPhases[i]) * STR
+			moshSscc(&ss[i], &cc[i], sr, j)
+		}
+	}
+	p := plan.ArgTbl
+	pl := plan.LonTbl
+	pb := plan.LatTbl
+	pr := plan.RadTbl
+	var sl, sb, sr Float64
+	for {
+		// 周期项的参数个数
+		np := int(p[0])
+		p = p[1:]
+		if np < 0 {
+			break
+		}
+		if np == 0 {
+			// 多项式项
+			nt := int(p[0])
+			p = p[1:]
+			cu := pl[0]
+			for ip := 1; ip <= nt; ip++ {
+				cu = cu*T + pl[ip]
+			}
+			pl = pl[nt+1:]
+			sl += mods3600(cu)
+			cu = pb[0]
+			for ip := 1; ip <= nt; ip++ {
+				cu = cu*T + pb[ip]
+			}
+			pb = pb[nt+1:]
+			sb += cu
+			cu = pr[0]
+			for ip := 1; ip <= nt; ip++ {
+				cu = cu*T + pr[ip]
+			}
+			pr = pr[nt+1:]
+			sr += cu
+			continue
+		}
+		k1 := false
+		var cv, sv Float64
+		for ip := 0; ip < np; ip++ {
+			// 谐波次数和行星
+			j := int(p[0])
+			m := int(p[1]) - 1
+			p = p[2:]
+			if j == 0 {
+				continue
+			}
+			k := j
+			if j < 0 {
+				k = -k
+			}
+			k--
+			su := ss[m][k]
+			if j < 0 {
+				su = -su
+			}
+			cu := cc[m][k]
+			if !k1 {
+				sv = su
+				cv = cu
+				k1 = true
+			} else {
+				t := su*cv + cu*sv
+				cv = cu*cv - su*sv
+				sv = t
+			}
+		}
+		// T的最高幂次
+		nt := int(p[0])
+		p = p[1:]
+		cu, su := moshPoly2(pl, nt, T)
+		pl = pl[2*nt+2:]
+		sl += cu*cv + su*sv
+		cu, su = moshPoly2(pb, nt, T)
+		pb = pb[2*nt+2:]
+		sb += cu*cv + su*sv
+		cu, su = moshPoly2(pr, nt, T)
+		pr = pr[2*nt+2:]
+		sr += cu*cv + su*sv
+	}
+	pobj[0] = STR * sl
+	pobj[1] = STR * sb
+	pobj[2] = STR*plan.Distance*sr + plan.Distance
+}
+
+// moshPoly2 计算交错存放的余弦和正弦振幅多项式
+func moshPoly2(tbl []Float64, nt int, T Float64) (cu, su Float64) {
+	cu = tbl[0]
+	su = tbl[1]
+	for ip := 1; ip <= nt; ip++ {
+		cu = cu*T + tbl[2*ip]
+		su = su*T + tbl[2*ip+1]
+	}
+	return cu, su
+}
+
+// moshSscc 准备倍角的正弦和余弦表
+func moshSscc(ss, cc *[24]Float64, arg Float64, n int) {
+	su := math.Sin(arg)
+	cu := math.Cos(arg)
+	ss[0] = su // sin(L)
+	cc[0] = cu // cos(L)
+	sv := 2.0 * su * cu
+	cv := cu*cu - su*su
+	ss[1] = sv // sin(2L)
+	cc[1] = cv
+	for i := 2; i < n; i++ {
+		s := su*cv + cu*sv
+		cv = cu*cv - su*sv
+		sv = s
+		ss[i] = sv // sin((i+1)L)
+		cc[i] = cv
+	}
+}
+
+// moshplanEcl 计算行星的日心J2000赤道直角坐标（不含速度）
+func moshplanEcl(tjd Float64, iplm int, x []Float64) {
+	moshplan2(tjd, iplm, x)
+	polcart(x, x)
+	coortrf2(x, x, -swed.Oec2000.Seps, swed.Oec2000.Ceps)
+}
+
+// moshplan 计算Moshier星历中地球和行星的日心J2000赤道直角坐标
+// ipli为内部行星编号，xpret和xeret分别返回行星和地球的位置和速度
+func moshplan(tjd Float64, ipli int, doSave bool, xpret, xeret []Float64) (int, error) {
+	var xxe, xxp [6]Float64
+	var x2 [6]Float64
+	iplm := pnoint2msh[ipli]
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SeiEarth]
+	xp := xxp[:]
+	xe := xxe[:]
+	if doSave {
+		xp = pdp.X[:]
+		xe = pedp.X[:]
+	}
+	doEarth := doSave || ipli == SeiEarth || xeret != nil
+	// 超出星历范围，边缘处为计算速度留一些余量
+	if tjd < moshplephStart-0.3 || tjd > moshplephEnd+0.3 {
+		return Err, fmt.Errorf("儒略日 %f 超出Moshier行星星历范围 %.2f .. %.2f", tjd, moshplephStart, moshplephEnd)
+	}
+	// 地球，用于地心位置
+	if doEarth {
+		if tjd == pedp.Teval && pedp.Iephe == SeflgMoseph {
+			xe = pedp.X[:]
+		} else {
+			// 地月质心
+			moshplanEcl(tjd, pnoint2msh[SeiEmb], xe)
+			embofsMosh(tjd, xe) // 地月质心 -> 地球
+			if doSave {
+				pedp.Teval = tjd
+				pedp.Xflgs = -1
+				pedp.Iephe = SeflgMoseph
+			}
+			// 再算一个位置用于求速度
+			moshplanEcl(tjd-planSpeedIntv, pnoint2msh[SeiEmb], x2[:])
+			embofsMosh(tjd-planSpeedIntv, x2[:])
+			for i := 0; i <= 2; i++ {
+				xe[i+3] = (xe[i] - x2[i]) / planSpeedIntv
+			}
+		}
+		if xeret != nil {
+			copy(xeret[:6], xe[:6])
+		}
+	}
+	if ipli == SeiEarth {
+		return Ok, nil
+	}
+	// 其他行星，已经计算过则直接返回
+	if tjd == pdp.Teval && pdp.Iephe == SeflgMoseph {
+		xp = pdp.X[:]
+	} else {
+		moshplanEcl(tjd, iplm, xp)
+		if doSave {
+			pdp.Teval = tjd
+			pdp.Xflgs = -1
+			pdp.Iephe = SeflgMoseph
+		}
+		// 再算一个位置用于求速度，此间隔对光行时改正足够
+		dt := Float64(planSpeedIntv)
+		moshplanEcl(tjd-dt, iplm, x2[:])
+		for i := 0; i <= 2; i++ {
+			xp[i+3] = (xp[i] - x2[i]) / dt
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp[:6])
+	}
+	return Ok, nil
+}
+
+// embofsMosh 将地月质心位置xemb改正为地球位置（使用简化月球理论）
+func embofsMosh(tjd Float64, xemb []Float64) {
+	var xyz [6]Float64
+	seps := swed.Oec.Seps
+	ceps := swed.Oec.Ceps
+	T := (tjd - J1900) / 36525.0
+	// 月球平近点角（MP）
+	a := degnorm(((1.44e-5*T+0.009192)*T+477198.8491)*T + 296.104608)
+	a *= DegToRad
+	smp := math.Sin(a)
+	cmp := math.Cos(a)
+	s2mp := 2.0 * smp * cmp   // sin(2MP)
+	c2mp := cmp*cmp - smp*smp // cos(2MP)
+	// 月球平距角（D）
+	a = degnorm(((1.9e-6*T-0.001436)*T+445267.1142)*T + 350.737486)
+	a = 2.0 * DegToRad * a
+	s2d := math.Sin(a)
+	c2d := math.Cos(a)
+	// 月球升交点角距（F）
+	a = degnorm(((-3.e-7*T-0.003211)*T+483202.0251)*T + 11.250889)
+	a *= DegToRad
+	sf := math.Sin(a)
+	cf := math.Cos(a)
+	s2f := 2.0 * sf * cf    // sin(2F)
+	sx := s2d*cmp - c2d*smp // sin(2D - MP)
+	cx := c2d*cmp + s2d*smp // cos(2D - MP)
+	// 月球平黄经（LP）
+	L := ((1.9e-6*T-0.001133)*T+481267.8831)*T + 270.434164
+	// 太阳平近点角（M）
+	M := degnorm(((-3.3e-6*T-1.50e-4)*T+35999.0498)*T + 358.475833)
+	// 月球黄经
+	L = L +
+		6.288750*smp +
+		1.274018*sx +
+		0.658309*s2d +
+		0.213616*s2mp -
+		0.185596*math.Sin(DegToRad*M) -
+		0.114336*s2f
+	// 月球黄纬
+	a = smp * cf
+	sx = cmp * sf
+	B := 5.128189*sf +
+		0.280606*(a+sx) + // sin(MP+F)
+		0.277693*(a-sx) + // sin(MP-F)
+		0.173238*(s2d*cf-c2d*sf) // sin(2D-F)
+	B *= DegToRad
+	// 月球视差
+	p := 0.950724 +
+		0.051818*cmp +
+		0.009531*cx +
+		0.007843*c2d +
+		0.002824*c2mp
+	p *= DegToRad
+	L = degnorm(L)
+	L *= DegToRad
+	// 距离（天文单位）
+	a = 4.263523e-5 / math.Sin(p)
+	xyz[0] = L
+	xyz[1] = B
+	xyz[2] = a
+	polcart(xyz[:], xyz[:])
+	coortrf2(xyz[:], xyz[:], -seps, ceps)
+	precess(xyz[:], tjd, 0, jToJ2000)
+	for i := 0; i <= 2; i++ {
+		xemb[i] -= xyz[i] / (EarthMoonMrat + 1.0)
+	}
+}
@@ -0,0 +1,648 @@
+// Moshier月球星历，移植自 swemmoon.c。
+// 与原程序不同，中间变量保存在moshMoon结构中而不是全局变量中。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Moshier月球星历的有效范围
+const (
+	moshluephStart = 625000.5
+	moshluephEnd   = 2818000.5
+)
+
+// moshMoon 月球理论计算过程中的中间量
+type moshMoon struct {
+	ss, cc [5][8]Float64 // 倍角的正弦和余弦表
+
+	l   Float64    // 月球黄经
+	b   Float64    // 月球黄纬
+	pol [3]Float64 // 结果：黄经、黄纬、距离
+
+	// 平根数（角秒）
+	lp, sm, mp, d, nf Float64
+	t, t2             Float64
+
+	f, g, cg, sg       Float64
+	ve, ea, ma, ju, sa Float64
+	l1, l2, l3, l4     Float64
+}
+
+// moshmoon2 计算儒略日J的月球几何位置（当天黄道极坐标，无光行时和章动）
+func moshmoon2(J Float64, pol []Float64) {
+	var m moshMoon
+	m.t = (J - J2000) / 36525.0
+	m.t2 = m.t * m.t
+	m.meanElements()
+	m.meanElementsPl()
+	m.moon1()
+	m.moon2()
+	m.moon3()
+	m.moon4()
+	copy(pol[:3], m.pol[:])
+}
+
+// moshmoon 计算Moshier月球的J2000赤道直角坐标位置和速度
+// doSave为真时结果保存在swed.Pldat[SeiMoon]中
+func moshmoon(tjd Float64, doSave bool, xpmret []Float64) (int, error) {
+	var xx, x1, x2 [6]Float64
+	pdp := &swed.Pldat[SeiMoon]
+	xpm := xx[:]
+	if doSave {
+		xpm = pdp.X[:]
+	}
+	// 允许0.2天的余量，以便真交点的计算区间仍在范围内
+	if tjd < moshluephStart-0.2 || tjd > moshluephEnd+0.2 {
+		return Err, fmt.Errorf("儒略日 %f 超出Moshier月球星历范围 %.2f .. %.2f", tjd, moshluephStart, moshluephEnd)
+	}
+	// 已经计算过
+	if tjd == pdp.Teval && pdp.Iephe == SeflgMoseph {
+		if xpmret != nil {
+			copy(xpmret[:6], pdp.X[:])
+		}
+		return Ok, nil
+	}
+	moshmoon2(tjd, xpm)
+	if doSave {
+		pdp.Teval = tjd
+		pdp.Xflgs = -1
+		pdp.Iephe = SeflgMoseph
+	}
+	// Moshier月球给出当天黄道坐标，统一转换为J2000赤道坐标
+	ecldatEqu2000(tjd, xpm)
+	// 由前后两个位置计算速度
+	t := tjd + moonSpeedIntv
+	moshmoon2(t, x1[:])
+	ecldatEqu2000(t, x1[:])
+	t = tjd - moonSpeedIntv
+	moshmoon2(t, x2[:])
+	ecldatEqu2000(t, x2[:])
+	for i := 0; i <= 2; i++ {
+		b := (x1[i] - x2[i]) / 2
+		a := (x1[i]+x2[i])/2 - xpm[i]
+		xpm[i+3] = (2*a + b) / moonSpeedIntv
+	}
+	if xpmret != nil {
+		copy(xpmret[:6], xpm[:6])
+	}
+	return Ok, nil
+}
+
+// moon1 行星摄动和长期项（第一部分）
+func (m *moshMoon) moon1() {
+	var a Float64
+	m.sscc(0, STR*m.d, 6)
+	m.sscc(1, STR*m.sm, 4)
+	m.sscc(2, STR*m.mp, 4)
+	m.sscc(3, STR*m.nf, 4)
+	m.pol[0] = 0.0
+	m.pol[1] = 0.0
+	m.pol[2] = 0.0
+	m.chewm(moonLRT2[:], 4, 2)
+	m.chewm(moonBT2[:], 4, 4)
+	m.f = 18*m.ve - 16*m.ea
+	m.g = STR * (m.f - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l = 6.367278*m.cg + 12.747036*m.sg
+	m.l1 = 23123.70*m.cg - 10570.02*m.sg
+	m.l2 = moonZ[12]*m.cg + moonZ[13]*m.sg
+	m.pol[2] += 5.01*m.cg + 2.72*m.sg
+	m.g = STR * (10.*m.ve - 3.*m.ea - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.253102*m.cg + 0.503359*m.sg
+	m.l1 += 1258.46*m.cg + 707.29*m.sg
+	m.l2 += moonZ[14]*m.cg + moonZ[15]*m.sg
+	m.g = STR * (8.*m.ve - 13.*m.ea)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.187231*m.cg - 0.127481*m.sg
+	m.l1 += -319.87*m.cg - 18.34*m.sg
+	m.l2 += moonZ[16]*m.cg + moonZ[17]*m.sg
+	a = 4.0*m.ea - 8.0*m.ma + 3.0*m.ju
+	m.g = STR * a
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.866287*m.cg + 0.248192*m.sg
+	m.l1 += 41.87*m.cg + 1053.97*m.sg
+	m.l2 += moonZ[18]*m.cg + moonZ[19]*m.sg
+	m.g = STR * (a - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.165009*m.cg + 0.044176*m.sg
+	m.l1 += 4.67*m.cg + 201.55*m.sg
+	m.g = STR * m.f
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.330401*m.cg + 0.661362*m.sg
+	m.l1 += 1202.67*m.cg - 555.59*m.sg
+	m.l2 += moonZ[20]*m.cg + moonZ[21]*m.sg
+	m.g = STR * (m.f - 2.0*m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.352185*m.cg + 0.705041*m.sg
+	m.l1 += 1283.59*m.cg - 586.43*m.sg
+	m.g = STR * (2.0*m.ju - 5.0*m.sa)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.034700*m.cg + 0.160041*m.sg
+	m.l2 += moonZ[22]*m.cg + moonZ[23]*m.sg
+	m.g = STR * (m.lp - m.nf)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.000116*m.cg + 7.063040*m.sg
+	m.l1 += 298.8 * m.sg
+	m.sg = math.Sin(STR * m.sm)
+	m.l3 = moonZ[24] * m.sg
+	m.l4 = 0
+	m.g = STR * (2.0*m.d - m.sm)
+	m.sg = math.Sin(m.g)
+	m.cg = math.Cos(m.g)
+	m.pol[2] += -0.2655 * m.cg * m.t
+	m.g = STR * (m.sm - m.mp)
+	m.pol[2] += -0.1568 * math.Cos(m.g) * m.t
+	m.g = STR * (m.sm + m.mp)
+	m.pol[2] += 0.1309 * math.Cos(m.g) * m.t
+	m.g = STR * (2.0*(m.d+m.sm) - m.mp)
+	m.sg = math.Sin(m.g)
+	m.cg = math.Cos(m.g)
+	m.pol[2] += 0.5568 * m.cg * m.t
+	m.l2 += m.pol[0]
+	m.g = STR * (2.0*m.d - m.sm - m.mp)
+	m.pol[2] += -0.1910 * math.Cos(m.g) * m.t
+	m.pol[1] *= m.t
+	m.pol[2] *= m.t
+	m.pol[0] = 0.0
+	m.chewm(moonBT[:], 4, 4)
+	m.chewm(moonLRT[:], 4, 1)
+	m.g = STR * (m.f - m.mp - m.nf - 2355767.6)
+	m.pol[1] += -1127. * math.Sin(m.g)
+	m.g = STR * (m.f - m.mp + m.nf - 235353.6)
+	m.pol[1] += -1123. * math.Sin(m.g)
+	m.g = STR * (m.ea + m.d + 51987.6)
+	m.pol[1] += 1303. * math.Sin(m.g)
+	m.g = STR * m.lp
+	m.pol[1] += 342. * math.Sin(m.g)
+	m.g = STR * (2.*m.ve - 3.*m.ea)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.343550*m.cg - 0.000276*m.sg
+	m.l1 += 105.90*m.cg + 336.53*m.sg
+	m.g = STR * (m.f - 2.*m.d)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.074668*m.cg + 0.149501*m.sg
+	m.l1 += 271.77*m.cg - 124.20*m.sg
+	m.g = STR * (m.f - 2.*m.d - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.073444*m.cg + 0.147094*m.sg
+	m.l1 += 265.24*m.cg - 121.16*m.sg
+	m.g = STR * (m.f + 2.*m.d - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.072844*m.cg + 0.145829*m.sg
+	m.l1 += 265.18*m.cg - 121.29*m.sg
+	m.g = STR * (m.f + 2.*(m.d-m.mp))
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.070201*m.cg + 0.140542*m.sg
+	m.l1 += 255.36*m.cg - 116.79*m.sg
+	m.g = STR * (m.ea + m.d - m.nf)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.288209*m.cg - 0.025901*m.sg
+	m.l1 += -63.51*m.cg - 240.14*m.sg
+	m.g = STR * (2.*m.ea - 3.*m.ju + 2.*m.d - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += 0.077865*m.cg + 0.438460*m.sg
+	m.l1 += 210.57*m.cg + 124.84*m.sg
+	m.g = STR * (m.ea - 2.*m.ma)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.216579*m.cg + 0.241702*m.sg
+	m.l1 += 197.67*m.cg + 125.23*m.sg
+	m.g = STR * (a + m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.165009*m.cg + 0.044176*m.sg
+	m.l1 += 4.67*m.cg + 201.55*m.sg
+	m.g = STR * (a + 2.*m.d - m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.133533*m.cg + 0.041116*m.sg
+	m.l1 += 6.95*m.cg + 187.07*m.sg
+	m.g = STR * (a - 2.*m.d + m.mp)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.133430*m.cg + 0.041079*m.sg
+	m.l1 += 6.28*m.cg + 169.08*m.sg
+	m.g = STR * (3.*m.ve - 4.*m.ea)
+	m.cg = math.Cos(m.g)
+	m.sg = math.Sin(m.g)
+	m.l += -0.175074*m.cg + 0.003035*m.sg
+	m.l1 += 49.17*m.cg + 150.57*m.sg
+	m.g = STR * (2.*(m.ea+m.d-m.mp) - 3.*m.ju + 213534.)
+	m.l1 += 158.4 * math.Sin(m.g)
+	m.l1 += m.pol[0]
+	a = 0.1 * m.t
+	m.pol[1] *= a
+	m.pol[2] *= a
+}
+
+// moon2 行星摄动和长期项（第二部分）
+func (m *moshMoon) moon2() {
+	m.g = STR * (2*(m.ea-m.ju+m.d) - m.mp + 648431.172)
+	m.l += 1.14307 * math.Sin(m.g)
+	m.g = STR * (m.ve - m.ea + 648035.568)
+	m.l += 0.82155 * math.Sin(m.g)
+	m.g = STR * (3*(m.ve-m.ea) + 2*m.d - m.mp + 647933.184)
+	m.l += 0.64371 * math.Sin(m.g)
+	m.g = STR * (m.ea - m.ju + 4424.04)
+	m.l += 0.63880 * math.Sin(m.g)
+	m.g = STR * (m.lp + m.mp - m.nf + 4.68)
+	m.l += 0.49331 * math.Sin(m.g)
+	m.g = STR * (m.lp - m.mp - m.nf + 4.68)
+	m.l += 0.4914 * math.Sin(m.g)
+	m.g = STR * (m.lp + m.nf + 2.52)
+	m.l += 0.36061 * math.Sin(m.g)
+	m.g = STR * (2.*m.ve - 2.*m.ea + 736.2)
+	m.l += 0.30154 * math.Sin(m.g)
+	m.g = STR * (2.*m.ea - 3.*m.ju + 2.*m.d - 2.*m.mp + 36138.2)
+	m.l += 0.28282 * math.Sin(m.g)
+	m.g = STR * (2.*m.ea - 2.*m.ju + 2.*m.d - 2.*m.mp + 311.0)
+	m.l += 0.24516 * math.Sin(m.g)
+	m.g = STR * (m.ea - m.ju - 2.*m.d + m.mp + 6275.88)
+	m.l += 0.21117 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ea-m.ma) - 846.36)
+	m.l += 0.19444 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ea-m.ju) + 1569.96)
+	m.l -= 0.18457 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ea-m.ju) - m.mp - 55.8)
+	m.l += 0.18256 * math.Sin(m.g)
+	m.g = STR * (m.ea - m.ju - 2.*m.d + 6490.08)
+	m.l += 0.16499 * math.Sin(m.g)
+	m.g = STR * (m.ea - 2.*m.ju - 212378.4)
+	m.l += 0.16427 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ve-m.ea-m.d) + m.mp + 1122.48)
+	m.l += 0.16088 * math.Sin(m.g)
+	m.g = STR * (m.ve - m.ea - m.mp + 32.04)
+	m.l -= 0.15350 * math.Sin(m.g)
+	m.g = STR * (m.ea - m.ju - m.mp + 4488.88)
+	m.l += 0.14346 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ve-m.ea+m.d) - m.mp - 8.64)
+	m.l += 0.13594 * math.Sin(m.g)
+	m.g = STR * (2.*(m.ve-m.ea-m.d) + 1319.76)
+	m.l += 0.13432 * math.Sin(m.g)
+	m.g = STR * (m.ve - m.ea - 2.*m.d + m.mp - 56.16)
+	m.l -= 0.13122 * math.Sin(m.g)
+	m.g = STR * (m.ve - m.ea + m.mp + 54.36)
+	m.l -= 0.12722 * math.Sin(m.g)
+	m.g = STR * (3.*(m.ve-m.ea) - m.mp + 433.8)
+	m.l += 0.12539 * math.Sin(m.g)
+	m.g = STR * (m.ea - m.ju + m.mp + 4002.12)
+	m.l += 0.10994 * math.Sin(m.g)
+	m.g = STR * (20.*m.ve - 21.*m.ea - 2.*m.d + m.mp - 317511.72)
+	m.l += 0.10652 * math.Sin(m.g)
+	m.g = STR * (26.*m.ve - 29.*m.ea - m.mp + 270002.52)
+	m.l += 0.10490 * math.Sin(m.g)
+	m.g = STR * (3.*m.ve - 4.*m.ea + m.d - m.mp - 322765.56)
+	m.l += 0.10386 * math.Sin(m.g)
+	m.g = STR * (m.lp + 648002.556)
+	m.b = 8.04508 * math.Sin(m.g)
+	m.g = STR * (m.ea + m.d + 996048.252)
+	m.b += 1.51021 * math.Sin(m.g)
+	m.g = STR * (m.f - m.mp + m.nf + 95554.332)
+	m.b += 0.63037 * math.Sin(m.g)
+	m.g = STR * (m.f - m.mp - m.nf + 95553.792)
+	m.b += 0.63014 * math.Sin(m.g)
+	m.g = STR * (m.lp - m.mp + 2.9)
+	m.b += 0.45587 * math.Sin(m.g)
+	m.g = STR * (m.lp + m.mp + 2.5)
+	m.b += -0.41573 * math.Sin(m.g)
+	m.g = STR * (m.lp - 2.0*m.nf + 3.2)
+	m.b += 0.32623 * math.Sin(m.g)
+	m.g = STR * (m.lp - 2.0*m.d + 2.5)
+	m.b += 0.29855 * math.Sin(m.g)
+}
+
+// moon3 T的零次幂周期项
+func (m *moshMoon) moon3() {
+	m.pol[0] = 0.0
+	m.chewm(moonLR[:], 4, 1)
+	m.chewm(moonMB[:], 4, 3)
+	m.l += (((m.l4*m.t+m.l3)*m.t+m.l2)*m.t + m.l1) * m.t * 1.0e-5
+	m.pol[0] = m.lp + m.l + 1.0e-4*m.pol[0]
+	m.pol[1] = 1.0e-4*m.pol[1] + m.b
+	m.pol[2] = 1.0e-4*m.pol[2] + 385000.52899 // 千米
+}
+
+// moon4 最终的黄道极坐标（弧度，天文单位）
+func (m *moshMoon) moon4() {
+	m.pol[2] /= Aunit / 1000
+	m.pol[0] = STR * mods3600(m.pol[0])
+	m.pol[1] = STR * m.pol[1]
+	m.b = m.pol[1]
+}
+
+// chewm 累加周期项表pt中的各项
+func (m *moshMoon) chewm(pt []int16, nangles, typflg int) {
+	var width int
+	switch typflg {
+	case 1:
+		width = nangles + 4
+	case 2, 3:
+		width = nangles + 2
+	default:
+		width = nangles + 1
+	}
+	for p := 0; p+width <= len(pt); p += width {
+		var sv, cv Float64
+		k1 := false
+		for i := 0; i < nangles; i++ {
+			j := int(pt[p+i]) // 倍角系数
+			if j == 0 {
+				continue
+			}
+			k := j
+			if j < 0 {
+				k = -k
+			}
+			su := m.ss[i][k-1]
+			cu := m.cc[i][k-1]
+			if j < 0 {
+				su = -su
+			}
+			if !k1 {
+				sv = su
+				cv = cu
+				k1 = true
+			} else {
+				ff := su*cv + cu*sv
+				cv = cu*cv - su*sv
+				sv = ff
+			}
+		}
+		q := pt[p+nangles:]
+		switch typflg {
+		case 1: // 大的黄经和距离项
+			m.pol[0] += (10000.0*Float64(q[0]) + Float64(q[1])) * sv
+			if q[3] != 0 {
+				m.pol[2] += (10000.0*Float64(q[2]) + Float64(q[3])) * cv
+			}
+		case 2: // 黄经和距离
+			m.pol[0] += Float64(q[0]) * sv
+			m.pol[2] += Float64(q[1]) * cv
+		case 3: // 大的黄纬项
+			m.pol[1] += (10000.0*Float64(q[0]) + Float64(q[1])) * sv
+		case 4: // 黄纬
+			m.pol[1] += Float64(q[0]) * sv
+		}
+	}
+}
+
+// sscc 准备倍角的正弦和余弦表
+func (m *moshMoon) sscc(k int, arg Float64, n int) {
+	su := math.Sin(arg)
+	cu := math.Cos(arg)
+	m.ss[k][0] = su // sin(L)
+	m.cc[k][0] = cu // cos(L)
+	sv := 2.0 * su * cu
+	cv := cu*cu - su*su
+	m.ss[k][1] = sv // sin(2L)
+	m.cc[k][1] = cv
+	for i := 2; i < n; i++ {
+		s := su*cv + cu*sv
+		cv = cu*cv - su*sv
+		sv = s
+		m.ss[k][i] = sv // sin((i+1)L)
+		m.cc[k][i] = cv
+	}
+}
+
+// meanElements 月球和太阳的平根数（角秒）
+func (m *moshMoon) meanElements() {
+	T := m.t
+	T2 := m.t2
+	fracT := math.Mod(T, 1)
+	z := &moonZ
+	// 太阳平近点角 l'（Laskar）
+	m.sm = mods3600(129600000.0*fracT - 3418.961646*T + 1287104.76154)
+	m.sm += ((((((((1.62e-20*T-
+		1.0390e-17)*T-
+		3.83508e-15)*T+
+		4.237343e-13)*T+
+		8.8555011e-11)*T-
+		4.77258489e-8)*T-
+		1.1297037031e-5)*T+
+		1.4732069041e-4)*T -
+		0.552891801772) * T2
+	// 月球升交点角距 F
+	m.nf = mods3600(1739232000.0*fracT + 295263.0983*T - 2.079419901760e-01*T + 335779.55755)
+	// 月球平近点角 l
+	m.mp = mods3600(1717200000.0*fracT + 715923.4728*T - 2.035946368532e-01*T + 485868.28096)
+	// 月球平距角 D
+	m.d = mods3600(1601856000.0*fracT + 1105601.4603*T + 3.962893294503e-01*T + 1072260.73512)
+	// 月球平黄经（当天平黄道和平春分点）
+	m.lp = mods3600(1731456000.0*fracT + 1108372.83264*T - 6.784914260953e-01*T + 785939.95571)
+	// 最小二乘拟合得到的高次长期项
+	m.nf += ((z[2]*T+z[1])*T + z[0]) * T2
+	m.mp += ((z[5]*T+z[4])*T + z[3]) * T2
+	m.d += ((z[8]*T+z[7])*T + z[6]) * T2
+	m.lp += ((z[11]*T+z[10])*T + z[9]) * T2
+}
+
+// meanElementsPl 行星平黄经（Laskar, Bretagnon）
+func (m *moshMoon) meanElementsPl() {
+	T := m.t
+	T2 := m.t2
+	m.ve = mods3600(210664136.4335482*T + 655127.283046)
+	m.ve += ((((((((-9.36e-023*T-
+		1.95e-20)*T+
+		6.097e-18)*T+
+		4.43201e-15)*T+
+		2.509418e-13)*T-
+		3.0622898e-10)*T-
+		2.26602516e-9)*T-
+		1.4244812531e-5)*T +
+		0.005871373088) * T2
+	m.ea = mods3600(129597742.26669231*T + 361679.214649)
+	m.ea += ((((((((-1.16e-22*T+
+		2.976e-19)*T+
+		2.8460e-17)*T-
+		1.08402e-14)*T-
+		1.226182e-12)*T+
+		1.7228268e-10)*T+
+		1.515912254e-7)*T+
+		8.863982531e-6)*T -
+		2.0199859001e-2) * T2
+	m.ma = mods3600(68905077.59284*T + 1279559.78866)
+	m.ma += (-1.043e-5*T + 9.38012e-3) * T2
+	m.ju = mods3600(10925660.428608*T + 123665.342120)
+	m.ju += (1.543273e-5*T - 3.06037836351e-1) * T2
+	m.sa = mods3600(4399609.65932*T + 180278.89694)
+	m.sa += ((4.475946e-8*T-6.874806e-5)*T + 7.56161437443e-1) * T2
+}
+
+// ecldatEqu2000 将当天黄道极坐标转换为J2000赤道直角坐标
+func ecldatEqu2000(tjd Float64, xpm []Float64) {
+	polcart(xpm, xpm)
+	coortrf2(xpm, xpm, -swed.Oec.Seps, swed.Oec.Ceps)
+	precess(xpm, tjd, 0, jToJ2000)
+}
+
+// mods3600 将角秒对360度取模
+func mods3600(x Float64) Float64 {
+	return x - 1296000.0*math.Floor(x/1296000.0)
+}
+
+// 平交点和平远地点的有效范围
+const (
+	moshndephStart = -3100015.5 // 公元前13200年8月15日，儒略历
+	moshndephEnd   = 8000016.5  // 17191年3月15日，格里高利历
+)
+
+// DE431星历的时间范围，平交点和平远地点的修正值只在此范围内有效
+const (
+	jplDe431Start = -3027215.5
+	jplDe431End   = 7930192.5
+)
+
+// corrMnodeJdT0greg 修正值表的起始时间（格里高利历公元前13100年1月1日）
+const corrMnodeJdT0greg = -3063616.5
+
+// corrMeanTable 由每世纪的修正值表线性插值
+func corrMeanTable(J Float64, tbl []Float64) Float64 {
+	if J < jplDe431Start || J > jplDe431End {
+		return 0
+	}
+	dayscty := 36524.25 // 格里高利历每世纪的天数
+	dJ := J - corrMnodeJdT0greg
+	i := int(math.Floor(dJ / dayscty))
+	dfrac := (dJ - Float64(i)*dayscty) / dayscty
+	return tbl[i] + dfrac*(tbl[i+1]-tbl[i])
+}
+
+// meanNode 计算月球平交点的当天黄道极坐标
+// 平根数由moshmoon2拟合JPL星历得到，并按DE431进行修正
+func meanNode(J Float64, pol []Float64) (int, error) {
+	var m moshMoon
+	m.t = (J - J2000) / 36525.0
+	m.t2 = m.t * m.t
+	if J < moshndephStart || J > moshndephEnd {
+		return Err, fmt.Errorf("儒略日 %f 超出平交点的计算范围 %.2f .. %.2f", J, moshndephStart, moshndephEnd)
+	}
+	m.meanElements()
+	dcor := corrMeanTable(J, moonMeanNodeCorr[:]) * 3600
+	pol[0] = mod2PI((m.lp - m.nf - dcor) * STR)
+	pol[1] = 0.0
+	pol[2] = MoonMeanDist / Aunit
+	return Ok, nil
+}
+
+// meanApog 计算月球平远地点（黑月莉莉丝）的当天黄道极坐标
+// 远地点投影到黄道上
+func meanApog(J Float64, pol []Float64) (int, error) {
+	var m moshMoon
+	m.t = (J - J2000) / 36525.0
+	m.t2 = m.t * m.t
+	if J < moshndephStart || J > moshndephEnd {
+		return Err, fmt.Errorf("儒略日 %f 超出平远地点的计算范围 %.2f .. %.2f", J, moshndephStart, moshndephEnd)
+	}
+	m.meanElements()
+	pol[0] = mod2PI((m.lp-m.mp)*STR + Pi)
+	pol[1] = 0
+	pol[2] = MoonMeanDist * (1 + MoonMeanEcc) / Aunit
+	dcor := corrMeanTable(J, moonMeanApsisCorr[:]) * DegToRad
+	pol[0] = mod2PI(pol[0] - dcor)
+	// 投影到黄道
+	node := (m.lp - m.nf) * STR
+	dcor = corrMeanTable(J, moonMeanNodeCorr[:]) * DegToRad
+	node = mod2PI(node - dcor)
+	pol[0] = mod2PI(pol[0] - node)
+	polcart(pol, pol)
+	coortrf(pol, pol, -MoonMeanIncl*DegToRad)
+	cartpol(pol, pol)
+	pol[0] = mod2PI(pol[0] + node)
+	return Ok, nil
+}
+
+// moshIntpApsides 计算插值月球远地点或近地点的几何位置（当天黄道极坐标）
+// 在月球平近点角附近迭代求距离的极值
+func moshIntpApsides(J Float64, pol []Float64, ipli int) {
+	var m moshMoon
+	var rsv [3]Float64
+	zMP := 27.55454988
+	fNF := 27.212220817 / zMP
+	fD := 29.530588835 / zMP
+	fLP := 27.321582 / zMP
+	fM := 365.2596359 / zMP
+	fVe := 224.7008001 / zMP
+	fEa := 365.2563629 / zMP
+	fMa := 686.9798519 / zMP
+	fJu := 4332.589348 / zMP
+	fSa := 10759.22722 / zMP
+	m.t = (J - J2000) / 36525.0
+	m.t2 = m.t * m.t
+	m.meanElements()
+	m.meanElementsPl()
+	sNF := mods3600(m.nf)
+	sD := mods3600(m.d)
+	sLP := mods3600(m.lp)
+	sMP := mods3600(m.mp)
+	sM := m.sm
+	sVe := m.ve
+	sEa := m.ea
+	sMa := m.ma
+	sJu := m.ju
+	sSa := m.sa
+	niter := 4
+	if ipli == SeiIntpPerg {
+		m.mp = 0.0
+		niter = 5
+	}
+	if ipli == SeiIntpApog {
+		m.mp = 648000.0
+		niter = 4
+	}
+	dd := 18000.0
+	for iii := 0; iii <= niter; iii++ {
+		dMP := sMP - m.mp
+		mLP := sLP - dMP
+		mNF := sNF - dMP
+		mD := sD - dMP
+		mMP := sMP - dMP
+		for ii := 0; ii <= 2; ii++ {
+			k := Float64(ii-1) * dd
+			m.mp = mMP + k
+			m.nf = mNF + k/fNF
+			m.d = mD + k/fD
+			m.lp = mLP + k/fLP
+			m.sm = sM + k/fM
+			m.ve = sVe + k/fVe
+			m.ea = sEa + k/fEa
+			m.ma = sMa + k/fMa
+			m.ju = sJu + k/fJu
+			m.sa = sSa + k/fSa
+			m.moon1()
+			m.moon2()
+			m.moon3()
+			m.moon4()
+			if ii == 1 {
+				copy(pol[:3], m.pol[:])
+			}
+			rsv[ii] = m.pol[2]
+		}
+		cMP := (1.5*rsv[0] - 2*rsv[1] + 0.5*rsv[2]) / (rsv[0] + rsv[2] - 2*rsv[1])
+		cMP *= dd
+		cMP = cMP - dd
+		mMP += cMP
+		m.mp = mMP
+		dd /= 10
+	}
+}
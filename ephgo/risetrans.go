@@ -0,0 +1,337 @@
+// 天体的出没时刻：在地平线上升起或落下的时刻，同swe_rise_trans中出没的部分。
+// 低纬度的太阳、月球、行星和月球交点用快速算法，其他情况逐时计算高度并二分查找。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// 出没计算的标志（rsmi）
+const (
+	calcRise          = 1     // 升起
+	calcSet           = 2     // 落下
+	bitGeoctrNoEclLat = 128   // 使用地心位置并忽略黄纬
+	bitDiscCenter     = 256   // 圆面中心的出没
+	bitNoRefraction   = 512   // 不考虑大气折射
+	bitDiscBottom     = 8192  // 圆面下缘的出没
+	bitFixedDiscSize  = 16384 // 不考虑距离对圆面大小的影响
+)
+
+// 出没计算允许的观测者海拔（米）
+const (
+	eclGeoaltMin = -500.0
+	eclGeoaltMax = 25000.0
+)
+
+// riseTrans 计算世界时tjdUt以后天体ipl或恒星star的下一次升起或落下的时刻（UT），star为nil时计算天体ipl
+// geopos为地理经度、纬度（度）和海拔（米），atpress和attemp为气压（hPa）和气温（摄氏度）。
+// 天体在一天之内不升起或不落下时返回NotAvailable
+func riseTrans(tjdUt Float64, ipl int, star *FixedStar, epheflag, rsmi Int32, geopos [3]Float64, atpress, attemp Float64) (Float64, int, error) {
+	// 快速算法对太阳在纬度65度以上、其他天体在纬度60度以上时可能漏掉出没
+	if star == nil && ipl >= SeSun && ipl <= SeTrueNode &&
+		(math.Abs(geopos[1]) <= 60 || (ipl == SeSun && math.Abs(geopos[1]) <= 65)) {
+		return riseSetFast(tjdUt, ipl, epheflag, rsmi, geopos, atpress, attemp)
+	}
+	return riseTransTrueHor(tjdUt, ipl, star, epheflag, rsmi, geopos, atpress, attemp, 0)
+}
+
+// calcUt 计算世界时tjdUt的天体位置，ΔT与iflag所指定的星历一致
+func calcUt(tjdUt Float64, ipl int, iflag Int32) ([6]Float64, error) {
+	x, _, err := calc(tjdUt+calcDeltat(tjdUt, iflag&sefEphMask)/86400.0, ipl, iflag)
+	return x, err
+}
+
+// discRadiusPlusRefr 太阳、月球等天体在地平线上出没时圆面中心的真高度之负值：
+// 视半径加上地平线上的折射refr，dd为距离（天文单位）
+func discRadiusPlusRefr(ipl int, dd Float64, rsmi Int32, refr Float64) Float64 {
+	var rdi Float64
+	if rsmi&bitFixedDiscSize != 0 {
+		switch ipl {
+		case SeSun:
+			dd = 1.0
+		case SeMoon:
+			dd = 0.00257
+		}
+	}
+	// 圆面的视半径
+	if rsmi&bitDiscCenter == 0 {
+		rdi = math.Asin(PlaDiam[ipl]/2.0/Aunit/dd) * RadToDeg
+	}
+	if rsmi&bitDiscBottom != 0 {
+		rdi = -rdi
+	}
+	if rsmi&bitNoRefraction == 0 {
+		rdi += refr
+	}
+	return rdi
+}
+
+// riseSetFast 由半昼弧估计出没时刻，再按高度的变化率迭代
+func riseSetFast(tjdUt Float64, ipl int, epheflag, rsmi Int32, geopos [3]Float64, atpress, attemp Float64) (Float64, int, error) {
+	iflag := epheflag & sefEphMask
+	tjdUt0 := tjdUt
+	nloop := 2
+	if ipl == SeMoon {
+		nloop = 4
+	}
+	facrise := 1.0
+	if rsmi&calcSet != 0 {
+		facrise = -1
+	}
+	iflagtopo := iflag | SeflgEquatorial
+	tohorFlag := Int32(SeEqu2hor)
+	if rsmi&bitGeoctrNoEclLat == 0 {
+		iflagtopo |= SeflgTopoctr
+		setTopo(geopos[0], geopos[1], geopos[2])
+	}
+	var tr Float64
+	for isSecondRun := false; ; isSecondRun = true {
+		xx, err := calcUt(tjdUt, ipl, iflagtopo)
+		if err != nil {
+			return 0, Err, err
+		}
+		// 天体的赤纬在一天之内有变化，又有大气折射，半昼弧并不精确，
+		// 但只要天体不是拱极或接近拱极就可以用
+		sda := -math.Tan(geopos[1]*DegToRad) * math.Tan(xx[1]*DegToRad)
+		switch {
+		case sda >= 1:
+			// 实际为0度，为考虑折射取10度，0会引起问题
+			sda = 10
+		case sda <= -1:
+			sda = 180
+		default:
+			sda = math.Acos(sda) * RadToDeg
+		}
+		armc := degnorm(sidtime(tjdUt)*15 + geopos[0])
+		// 天体的子午圈距离
+		md := degnorm(xx[0] - armc)
+		mdrise := degnorm(sda * facrise)
+		dmd := degnorm(md - mdrise)
+		// 避免得到次日的出没
+		if dmd > 358 {
+			dmd -= 360
+		}
+		// 出没时刻的初值
+		tr = tjdUt + dmd/360
+		// 高度为0的视天体在海拔0米处的折射
+		if atpress == 0 {
+			atpress = defaultAtpress(geopos[2])
+		}
+		_, dret := refracExtended(0.000001, 0, atpress, attemp, lapseRate, SeAppToTrue)
+		refr := dret[1] - dret[0]
+		if rsmi&bitGeoctrNoEclLat != 0 {
+			tohorFlag = SeEcl2hor
+			iflagtopo = iflag
+		}
+		for i := 0; i < nloop; i++ {
+			xx, err := calcUt(tr, ipl, iflagtopo)
+			if err != nil {
+				return 0, Err, err
+			}
+			if rsmi&bitGeoctrNoEclLat != 0 {
+				xx[1] = 0
+			}
+			rdi := discRadiusPlusRefr(ipl, xx[2], rsmi, refr)
+			xaz := azalt(tr, tohorFlag, geopos, atpress, attemp, xx[:])
+			xaz2 := azalt(tr+0.001, tohorFlag, geopos, atpress, attemp, xx[:])
+			dd := xaz2[1] - xaz[1]
+			dalt := xaz[1] + rdi
+			dt := math.Max(-0.1, math.Min(0.1, dalt/dd/1000.0))
+			tr -= dt
+		}
+		// 得到的出没早于输入时刻时求下一次出没
+		if tr >= tjdUt0 || isSecondRun {
+			break
+		}
+		tjdUt += 0.5
+	}
+	return tr, Ok, nil
+}
+
+// riseTransTrueHor 在28小时内每两小时计算一次天体上缘的视高度，插入其间的上下中天，
+// 再对高度变号的区间二分查找出没时刻；horhgt为地平线的高度（度），-100表示取地平俯角
+func riseTransTrueHor(tjdUt Float64, ipl int, star *FixedStar, epheflag, rsmi Int32, geopos [3]Float64, atpress, attemp, horhgt Float64) (Float64, int, error) {
+	const twohrs = 1.0 / 12.0
+	var xc [6]Float64
+	var tc, h [32]Float64
+	var xh [15][3]Float64
+	var tculm []Float64
+	var dd Float64
+	jmax := 14
+	doFixstar := star != nil
+	if geopos[2] < eclGeoaltMin || geopos[2] > eclGeoaltMax {
+		return 0, Err, fmt.Errorf("出没计算的观测地点须在海拔%.0f米至%.0f米之间", eclGeoaltMin, eclGeoaltMax)
+	}
+	if horhgt == -100 {
+		horhgt = 0.0001 + calcDip(geopos[2], atpress, attemp, lapseRate)
+	}
+	// 冥王星作为小行星134340调用时按主要行星计算
+	if ipl == SeAstOffset+134340 {
+		ipl = SePluto
+	}
+	// 允许SeflgNonut和SeflgTruepos以加快计算
+	iflag := epheflag & (sefEphMask | SeflgNonut | SeflgTruepos)
+	tohorFlag := Int32(SeEqu2hor)
+	if rsmi&bitGeoctrNoEclLat != 0 {
+		tohorFlag = SeEcl2hor
+	} else {
+		iflag |= SeflgEquatorial | SeflgTopoctr
+		setTopo(geopos[0], geopos[1], geopos[2])
+	}
+	if rsmi&(calcRise|calcSet) == 0 {
+		rsmi |= calcRise
+	}
+	// position 计算t时刻天体的位置，恒星只计算一次
+	position := func(t Float64) error {
+		var err error
+		if !doFixstar {
+			xc, _, err = calc(t+calcDeltat(t, epheflag)/86400.0, ipl, iflag)
+		}
+		if err == nil && rsmi&bitGeoctrNoEclLat != 0 {
+			xc[1] = 0
+		}
+		return err
+	}
+	// apparentHeight 由xc计算t时刻天体上缘（或下缘）相对地平线的真高度和视高度
+	apparentHeight := func(t Float64) [3]Float64 {
+		curdist := xc[2]
+		if rsmi&bitFixedDiscSize != 0 {
+			switch ipl {
+			case SeSun:
+				curdist = 1.0
+			case SeMoon:
+				curdist = 0.00257
+			}
+		}
+		// 圆面的视半径
+		rdi := math.Asin(dd/2/Aunit/curdist) * RadToDeg
+		// 圆面中心的真高度
+		ah := azalt(t, tohorFlag, geopos, atpress, attemp, xc[:])
+		if rsmi&bitDiscBottom != 0 {
+			ah[1] -= rdi
+		} else {
+			ah[1] += rdi
+		}
+		if rsmi&bitNoRefraction != 0 {
+			ah[1] -= horhgt
+			ah[2] = ah[1]
+			return ah
+		}
+		xr := azaltRev(t, SeHor2equ, geopos, ah[:])
+		xc[0], xc[1] = xr[0], xr[1]
+		ah = azalt(t, SeEqu2hor, geopos, atpress, attemp, xc[:])
+		ah[1] -= horhgt
+		ah[2] -= horhgt
+		return ah
+	}
+	if doFixstar {
+		var err error
+		if xc, _, _, err = fixstarData(star, tjdUt+calcDeltat(tjdUt, epheflag)/86400.0, iflag); err != nil {
+			return 0, Err, err
+		}
+	}
+	// 求tjdUt前两小时起28小时内的上下中天。高度的极大或极小值略高于或略低于地平线时需要中天；
+	// 极区的中天可能与过子午圈的时刻相差很大，月球也可能在西半天短时升起，所以不用过子午圈的时刻
+	t := tjdUt - twohrs
+	for ii := 0; ii <= jmax; ii, t = ii+1, t+twohrs {
+		tc[ii] = t
+		if err := position(t); err != nil {
+			return 0, Err, err
+		}
+		// 天体的直径（米）
+		if ii == 0 {
+			switch {
+			case doFixstar, rsmi&bitDiscCenter != 0:
+				dd = 0
+			case ipl < len(PlaDiam):
+				dd = PlaDiam[ipl]
+			case ipl > SeAstOffset:
+				dd = swed.AstDiam * 1000
+			}
+		}
+		xh[ii] = apparentHeight(t)
+		h[ii] = xh[ii][2]
+		if ii < 2 {
+			continue
+		}
+		dc := [3]Float64{xh[ii-2][1], xh[ii-1][1], xh[ii][1]}
+		if !(dc[1] > dc[0] && dc[1] > dc[2]) && !(dc[1] < dc[0] && dc[1] < dc[2]) {
+			continue
+		}
+		dt := twohrs
+		tcu := t - dt
+		tcu += findMaximum(dc[0], dc[1], dc[2], dt) + dt
+		for dt /= 3; dt > 0.0001; dt /= 3 {
+			tt := tcu - dt
+			for i := 0; i < 3; i, tt = i+1, tt+dt {
+				if err := position(tt); err != nil {
+					return 0, Err, err
+				}
+				ah := azalt(tt, tohorFlag, geopos, atpress, attemp, xc[:])
+				dc[i] = ah[1] - horhgt
+			}
+			tcu += findMaximum(dc[0], dc[1], dc[2], dt) + dt
+		}
+		tculm = append(tculm, tcu)
+	}
+	// 把中天插入高度表。极地即使没有中天也可能有出没，所以这里不能返回
+	for _, tcu := range tculm {
+		for j := 1; j <= jmax; j++ {
+			if tcu >= tc[j] {
+				continue
+			}
+			copy(tc[j+1:jmax+2], tc[j:jmax+1])
+			copy(h[j+1:jmax+2], h[j:jmax+1])
+			tc[j] = tcu
+			if err := position(tc[j]); err != nil {
+				return 0, Err, err
+			}
+			h[j] = apparentHeight(tc[j])[2]
+			jmax++
+			break
+		}
+	}
+	// 对高度变号的区间二分查找
+	for ii := 1; ii <= jmax; ii++ {
+		if h[ii-1]*h[ii] >= 0 {
+			continue
+		}
+		if h[ii-1] < h[ii] && rsmi&calcRise == 0 {
+			continue
+		}
+		if h[ii-1] > h[ii] && rsmi&calcSet == 0 {
+			continue
+		}
+		dc := [2]Float64{h[ii-1], h[ii]}
+		t2 := [2]Float64{tc[ii-1], tc[ii]}
+		for i := 0; i < 20; i++ {
+			t = (t2[0] + t2[1]) / 2
+			if err := position(t); err != nil {
+				return 0, Err, err
+			}
+			aha := apparentHeight(t)[2]
+			if aha*dc[0] <= 0 {
+				dc[1] = aha
+				t2[1] = t
+			} else {
+				dc[0] = aha
+				t2[0] = t
+			}
+		}
+		if t > tjdUt {
+			return t, Ok, nil
+		}
+	}
+	return 0, NotAvailable, fmt.Errorf("未找到天体%d的出没", ipl)
+}
+
+// findMaximum 过三个等间隔dx的点y00、y11、y2的抛物线的极值点，返回相对于中间点的偏移
+func findMaximum(y00, y11, y2, dx Float64) Float64 {
+	c := y11
+	b := (y2 - y00) / 2.0
+	a := (y2+y00)/2.0 - c
+	x := -b / 2 / a
+	return (x - 1) * dx
+}
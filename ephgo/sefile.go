@@ -0,0 +1,977 @@
+// 读取Swiss Ephemeris星历文件（*.se1），移植自 sweph.c。
+
+package ephgo
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sefNcties 每个星历文件覆盖的世纪数
+const sefNcties = 6
+
+// genFilename 生成包含儒略日tjd的星历文件名
+func genFilename(tjd Float64, ipli int) string {
+	var fname string
+	switch ipli {
+	case SeiMoon:
+		fname = "semo"
+	case SeiEmb, SeiMercury, SeiVenus, SeiMars, SeiJupiter,
+		SeiSaturn, SeiUranus, SeiNeptune, SeiPluto, SeiSunbary:
+		fname = "sepl"
+	case SeiCeres, SeiPallas, SeiJuno, SeiVesta, SeiChiron, SeiPholus:
+		fname = "seas"
+	default:
+		// 小行星或行星卫星：每个天体只有一个文件
+		if ipli > SePlmoonOffset && ipli < SeAstOffset {
+			return fmt.Sprintf("sat/sepm%d.%s", ipli, SeFileSuffix)
+		}
+		n := ipli - SeAstOffset
+		if n > 99999 {
+			return fmt.Sprintf("ast%d/s%06d.%s", n/1000, n, SeFileSuffix)
+		}
+		return fmt.Sprintf("ast%d/se%05d.%s", n/1000, n, SeFileSuffix)
+	}
+	// 1600年以后使用格里高利历
+	gregflag := SeJulCal
+	if tjd >= 2305447.5 {
+		gregflag = SeGregCal
+	}
+	jyear, _, _, _ := Revjul(tjd, gregflag)
+	// 文件起始世纪
+	icty := jyear / 100
+	if jyear < 0 && jyear%100 != 0 {
+		icty--
+	}
+	for icty%sefNcties != 0 {
+		icty--
+	}
+	if icty < 0 {
+		fname += "m"
+		icty = -icty
+	} else {
+		fname += "_"
+	}
+	return fmt.Sprintf("%s%02d.%s", fname, icty, SeFileSuffix)
+}
+
+// ephePathList 将星历路径拆分为目录列表
+func ephePathList(ephepath string) []string {
+	return strings.FieldsFunc(ephepath, func(r rune) bool {
+		return r == ';' || r == os.PathListSeparator
+	})
+}
+
+// currentEphePath 当前使用的星历路径
+func currentEphePath() string {
+	if swed.Ephepath != "" {
+		return swed.Ephepath
+	}
+	if ephePath != "" {
+		return ephePath
+	}
+	return getDefaultEphePath()
+}
+
+// sweFopen 在星历路径中查找并打开文件fname
+// ifno >= 0 时将找到的完整文件名记录在swed.Fidat[ifno].Fnam中
+func sweFopen(ifno int, fname, ephepath string) (*os.File, error) {
+	for _, dir := range ephePathList(ephepath) {
+		path := filepath.Join(dir, filepath.FromSlash(fname))
+		if dir == "." {
+			path = filepath.FromSlash(fname)
+		}
+		fp, err := os.Open(path)
+		if err == nil {
+			if ifno >= 0 {
+				swed.Fidat[ifno].Fnam = path
+			}
+			return fp, nil
+		}
+	}
+	return nil, fmt.Errorf("在路径 '%s' 中找不到星历文件 '%s'", ephepath, fname)
+}
+
+// closeEphFile 关闭星历文件ifno
+func closeEphFile(ifno int) {
+	fdp := &swed.Fidat[ifno]
+	if fdp.Fptr != nil {
+		fdp.Fptr.Close()
+		fdp.Fptr = nil
+	}
+}
+
+// freePlanets 释放行星数据，清除所有缓存的计算结果
+func freePlanets() {
+	for i := range swed.Pldat {
+		swed.Pldat[i] = PlanData{}
+	}
+	for i := range swed.Savedat {
+		swed.Savedat[i] = SavePositions{}
+	}
+	for i := range swed.Nddat {
+		swed.Nddat[i] = PlanData{}
+	}
+}
+
+// errFileDamaged 星历文件损坏的错误
+func errFileDamaged(fdp *FileData, smsg string) error {
+	return fmt.Errorf("星历文件 %s 已损坏（0%s）", fdp.Fnam, smsg)
+}
+
+// doFread 从星历文件读取count个size字节的整数，按文件字节序解码
+// fpos >= 0 时先定位到该位置
+func doFread(fdp *FileData, fpos int64, size, count int) ([]uint64, error) {
+	fp := fdp.Fptr
+	if fpos >= 0 {
+		if _, err := fp.Seek(fpos, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("星历文件 %s 已损坏（1）", fdp.Fnam)
+		}
+	}
+	buf := make([]byte, size*count)
+	if _, err := io.ReadFull(fp, buf); err != nil {
+		return nil, fmt.Errorf("星历文件 %s 已损坏（2）", fdp.Fnam)
+	}
+	bigendian := fdp.Iflg&SeiFileLitendian == 0
+	out := make([]uint64, count)
+	for i := 0; i < count; i++ {
+		b := buf[i*size : (i+1)*size]
+		var v uint64
+		for j := 0; j < size; j++ {
+			if bigendian {
+				v = v<<8 | uint64(b[j])
+			} else {
+				v = v<<8 | uint64(b[size-1-j])
+			}
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// doFreadDouble 从星历文件读取count个双精度数
+func doFreadDouble(fdp *FileData, fpos int64, count int) ([]Float64, error) {
+	v, err := doFread(fdp, fpos, 8, count)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Float64, count)
+	for i := range v {
+		out[i] = math.Float64frombits(v[i])
+	}
+	return out, nil
+}
+
+// readLine 读取以"\r\n"结尾的一行文本（相当于fgets）
+func readLine(fp *os.File) (string, bool) {
+	var sb strings.Builder
+	var c [1]byte
+	for sb.Len() < 2*AsMaxch {
+		if _, err := fp.Read(c[:]); err != nil {
+			return sb.String(), false
+		}
+		sb.WriteByte(c[0])
+		if c[0] == '\n' {
+			break
+		}
+	}
+	s := sb.String()
+	return s, strings.HasSuffix(s, "\r\n")
+}
+
+// readConst 读取星历文件ifno的文件头和常数
+func readConst(ifno int) (int, error) {
+	fdp := &swed.Fidat[ifno]
+	err := readConstFile(ifno, fdp)
+	if err != nil {
+		closeEphFile(ifno)
+		freePlanets()
+		return Err, err
+	}
+	return Ok, nil
+}
+
+func readConstFile(ifno int, fdp *FileData) error {
+	fp := fdp.Fptr
+	// 文件版本号
+	s, ok := readLine(fp)
+	if !ok {
+		return errFileDamaged(fdp, "")
+	}
+	i := strings.IndexAny(s, "0123456789")
+	if i < 0 {
+		return errFileDamaged(fdp, "a")
+	}
+	j := i
+	for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+		j++
+	}
+	fdp.Fversion, _ = strconv.Atoi(s[i:j])
+	// 文件名是否正确
+	s, ok = readLine(fp)
+	if !ok {
+		return errFileDamaged(fdp, "b")
+	}
+	s2 := strings.ToLower(filepath.Base(fdp.Fnam))
+	s = strings.ToLower(strings.TrimRight(s, "\r\n "))
+	if s2 != s {
+		return fmt.Errorf("星历文件名 '%s' 错误，应改名为 '%s'", s2, s)
+	}
+	// 版权信息
+	if _, ok = readLine(fp); !ok {
+		return errFileDamaged(fdp, "c")
+	}
+	// 单个小行星文件的轨道要素
+	var sastnam string
+	if ifno == SeiFileAnyAst {
+		s, ok = readLine(fp)
+		if !ok {
+			return errFileDamaged(fdp, "d")
+		}
+		readAsteroidElements(s, &sastnam)
+	}
+	// 字节序测试
+	var tb [4]byte
+	if _, err := io.ReadFull(fp, tb[:]); err != nil {
+		return errFileDamaged(fdp, "e")
+	}
+	be := uint32(tb[0])<<24 | uint32(tb[1])<<16 | uint32(tb[2])<<8 | uint32(tb[3])
+	le := uint32(tb[3])<<24 | uint32(tb[2])<<16 | uint32(tb[1])<<8 | uint32(tb[0])
+	switch {
+	case be == SeiFileTestEndian:
+		fdp.Iflg = SeiFileBigendian | SeiFileReord
+	case le == SeiFileTestEndian:
+		fdp.Iflg = SeiFileLitendian | SeiFileNoreord
+	default:
+		return errFileDamaged(fdp, "f")
+	}
+	// 文件长度
+	v, err := doFread(fdp, -1, 4, 1)
+	if err != nil {
+		return err
+	}
+	fpos, _ := fp.Seek(0, io.SeekCurrent)
+	flen, err := fp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errFileDamaged(fdp, "g")
+	}
+	if int64(v[0]) != flen {
+		return errFileDamaged(fdp, "h")
+	}
+	// 文件所依据的JPL星历DE编号
+	if v, err = doFread(fdp, fpos, 4, 1); err != nil {
+		return err
+	}
+	fdp.SwephDenum = Int32(v[0])
+	// 文件的起止时间
+	d, err := doFreadDouble(fdp, -1, 2)
+	if err != nil {
+		return err
+	}
+	fdp.Tfstart = d[0]
+	fdp.Tfend = d[1]
+	// 文件中的行星数量
+	if v, err = doFread(fdp, -1, 2, 1); err != nil {
+		return err
+	}
+	nplan := int(v[0])
+	nbytesIpl := 2
+	if nplan > 256 {
+		nbytesIpl = 4
+		nplan %= 256
+	}
+	if nplan < 1 || nplan > 20 {
+		return errFileDamaged(fdp, "i")
+	}
+	fdp.Npl = int16(nplan)
+	if v, err = doFread(fdp, -1, nbytesIpl, nplan); err != nil {
+		return err
+	}
+	for k := 0; k < nplan; k++ {
+		fdp.Ipl[k] = int(v[k])
+	}
+	// 小行星名称
+	if ifno == SeiFileAnyAst {
+		var nb [30]byte
+		if _, err := io.ReadFull(fp, nb[:]); err != nil {
+			return errFileDamaged(fdp, "j")
+		}
+		readAsteroidName(fdp, sastnam, nb[:])
+	}
+	// 校验CRC
+	fpos, _ = fp.Seek(0, io.SeekCurrent)
+	if v, err = doFread(fdp, -1, 4, 1); err != nil {
+		return err
+	}
+	if fpos-1 > 2*AsMaxch {
+		return errFileDamaged(fdp, "l")
+	}
+	head := make([]byte, fpos)
+	if _, err := fp.Seek(0, io.SeekStart); err != nil {
+		return errFileDamaged(fdp, "m")
+	}
+	if _, err := io.ReadFull(fp, head); err != nil {
+		return errFileDamaged(fdp, "m")
+	}
+	if crc32(head) != uint32(v[0]) {
+		return errFileDamaged(fdp, "n")
+	}
+	// 一般常数：光速、天文单位、日心引力常数、地月质量比、太阳半径
+	if d, err = doFreadDouble(fdp, fpos+4, 5); err != nil {
+		return err
+	}
+	swed.Gcdat.Clight = d[0]
+	swed.Gcdat.Aunit = d[1]
+	swed.Gcdat.Helgravconst = d[2]
+	swed.Gcdat.Ratme = d[3]
+	swed.Gcdat.Sunradius = d[4]
+	// 各行星的常数
+	for kpl := 0; kpl < nplan; kpl++ {
+		ipli := fdp.Ipl[kpl]
+		var pdp *PlanData
+		if ipli >= SePlmoonOffset {
+			pdp = &swed.Pldat[SeiAnybody]
+		} else {
+			pdp = &swed.Pldat[ipli]
+		}
+		pdp.Ibdy = ipli
+		// 行星索引在文件中的位置
+		if v, err = doFread(fdp, -1, 4, 1); err != nil {
+			return err
+		}
+		pdp.Lndx0 = Int32(v[0])
+		// 标志：日心/质心、旋转、参考椭圆
+		if v, err = doFread(fdp, -1, 1, 1); err != nil {
+			return err
+		}
+		pdp.Iflg = Int32(v[0])
+		// 每段切比雪夫系数的个数
+		if v, err = doFread(fdp, -1, 1, 1); err != nil {
+			return err
+		}
+		pdp.Ncoe = int(v[0])
+		// 归一化因子
+		if v, err = doFread(fdp, -1, 4, 1); err != nil {
+			return err
+		}
+		lng := Float64(Int32(v[0]))
+		pdp.Rmax = lng / 1000.0
+		if ipli >= SePlmoonOffset && ipli < SeAstOffset {
+			if ipli%100 == 99 || (ipli-9000)/100 == SeMars {
+				pdp.Rmax = lng / 1000000.0
+			}
+		}
+		// 起止时间、段长和轨道要素
+		if d, err = doFreadDouble(fdp, -1, 10); err != nil {
+			return err
+		}
+		pdp.Tfstart = d[0]
+		pdp.Tfend = d[1]
+		pdp.Dseg = d[2]
+		pdp.Nndx = Int32((d[1] - d[0] + 0.1) / d[2])
+		pdp.Telem = d[3]
+		pdp.Prot = d[4]
+		pdp.Dprot = d[5]
+		pdp.Qrot = d[6]
+		pdp.Dqrot = d[7]
+		pdp.Peri = d[8]
+		pdp.Dperi = d[9]
+		pdp.Segp = nil
+		pdp.Refep = nil
+		// 参考椭圆的系数
+		if pdp.Iflg&SeiFlgEllipse != 0 {
+			if pdp.Refep, err = doFreadDouble(fdp, -1, 2*pdp.Ncoe); err != nil {
+				pdp.Refep = nil
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readAsteroidElements 解析单个小行星文件中的轨道要素记录
+func readAsteroidElements(s string, sastnam *string) {
+	const lastnam = 19
+	sp := 0
+	for sp < len(s) && s[sp] == ' ' {
+		sp++
+	}
+	for sp < len(s) && s[sp] >= '0' && s[sp] <= '9' {
+		sp++
+	}
+	sp++
+	*sastnam = substr(s, 0, lastnam+sp)
+	// 保存要素，计算星等时需要
+	swed.Astelem = s
+	swed.AstH = atofPrefix(substr(s, 35+sp, len(s)))
+	swed.AstG = atofPrefix(substr(s, 42+sp, len(s)))
+	if swed.AstG == 0 {
+		swed.AstG = 0.15
+	}
+	// 直径（千米），不一定给出
+	swed.AstDiam = atofPrefix(substr(s, 51+sp, 51+sp+7))
+	if swed.AstDiam == 0 {
+		// 假设反照率为0.15，由星等估算直径
+		swed.AstDiam = 1329 / math.Sqrt(0.15) * math.Pow(10, -0.2*swed.AstH)
+	}
+}
+
+// readAsteroidName 确定单个小行星文件中的小行星名称
+func readAsteroidName(fdp *FileData, sastnam string, oldName []byte) {
+	const lastnam = 19
+	j := 4 // 旧astorb.dat中MPC编号只有4位
+	for j < len(sastnam) && sastnam[j] != ' ' && j < 10 {
+		j++
+	}
+	no, _ := strconv.Atoi(strings.TrimSpace(substr(sastnam, 0, j)))
+	var name string
+	if no == fdp.Ipl[0]-SeAstOffset || no == fdp.Ipl[0] {
+		// 要素记录来自Bowell数据库
+		name = substr(sastnam, j+1, j+1+lastnam)
+	} else {
+		// 较旧的记录结构，名称取自旧的名称字段
+		name = string(oldName)
+	}
+	if k := strings.IndexByte(name, 0); k >= 0 {
+		name = name[:k]
+	}
+	name = strings.TrimRight(name, " ")
+	if k := strings.Index(name, "  "); k >= 0 {
+		name = name[:k]
+	}
+	fdp.Astnam = name
+}
+
+// substr 返回s[i:j]，越界时截断
+func substr(s string, i, j int) string {
+	if j > len(s) {
+		j = len(s)
+	}
+	if i > j {
+		return ""
+	}
+	return s[i:j]
+}
+
+// atofPrefix 解析字符串开头的浮点数（相当于C的atof）
+func atofPrefix(s string) Float64 {
+	s = strings.TrimLeft(s, " \t")
+	end := 0
+	for end < len(s) && strings.IndexByte("+-.0123456789eE", s[end]) >= 0 {
+		end++
+	}
+	for end > 0 {
+		if f, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return f
+		}
+		end--
+	}
+	return 0
+}
+
+// getNewSegment 读取包含tjd的切比雪夫系数段
+func getNewSegment(tjd Float64, ipli, ifno int) (int, error) {
+	pdp := &swed.Pldat[ipli]
+	fdp := &swed.Fidat[ifno]
+	fail := func(err error) (int, error) {
+		closeEphFile(ifno)
+		freePlanets()
+		return Err, err
+	}
+	// 段编号
+	iseg := Int32((tjd - pdp.Tfstart) / pdp.Dseg)
+	pdp.Tseg0 = pdp.Tfstart + Float64(iseg)*pdp.Dseg
+	pdp.Tseg1 = pdp.Tseg0 + pdp.Dseg
+	// 系数在文件中的位置
+	v, err := doFread(fdp, int64(pdp.Lndx0)+int64(iseg)*3, 3, 1)
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := fdp.Fptr.Seek(int64(v[0]), io.SeekStart); err != nil {
+		return fail(err)
+	}
+	pdp.Segp = make([]Float64, pdp.Ncoe*3)
+	rmax := pdp.Rmax
+	// 读取三个坐标的系数
+	for icoord := 0; icoord < 3; icoord++ {
+		idbl := icoord * pdp.Ncoe
+		// 段头：第一位表示压缩系数的尺寸种类数
+		c, err := doFread(fdp, -1, 1, 2)
+		if err != nil {
+			return fail(err)
+		}
+		var nsize [6]int
+		nsizes := 4
+		if c[0]&128 != 0 {
+			nsizes = 6
+			c2, err := doFread(fdp, -1, 1, 2)
+			if err != nil {
+				return fail(err)
+			}
+			nsize[0] = int(c[1]) / 16
+			nsize[1] = int(c[1]) % 16
+			nsize[2] = int(c2[0]) / 16
+			nsize[3] = int(c2[0]) % 16
+			nsize[4] = int(c2[1]) / 16
+			nsize[5] = int(c2[1]) % 16
+		} else {
+			nsize[0] = int(c[0]) / 16
+			nsize[1] = int(c[0]) % 16
+			nsize[2] = int(c[1]) / 16
+			nsize[3] = int(c[1]) % 16
+		}
+		nco := 0
+		for i := 0; i < nsizes; i++ {
+			nco += nsize[i]
+		}
+		// 系数个数不能超过插值阶数+1
+		if nco > pdp.Ncoe {
+			pdp.Segp = nil
+			return Err, fmt.Errorf("星历文件 %s 错误：%d 个系数，应为 %d 个", fdp.Fnam, nco, pdp.Ncoe)
+		}
+		// 解压
+		for i := 0; i < nsizes; i++ {
+			if nsize[i] == 0 {
+				continue
+			}
+			switch {
+			case i < 4:
+				longs, err := doFread(fdp, -1, 4-i, nsize[i])
+				if err != nil {
+					return fail(err)
+				}
+				for _, l := range longs {
+					if l&1 != 0 { // 负数
+						pdp.Segp[idbl] = -(Float64((l+1)/2) / 1e+9 * rmax / 2)
+					} else {
+						pdp.Segp[idbl] = Float64(l/2) / 1e+9 * rmax / 2
+					}
+					idbl++
+				}
+			case i == 4: // 半字节压缩
+				longs, err := doFread(fdp, -1, 1, (nsize[i]+1)/2)
+				if err != nil {
+					return fail(err)
+				}
+				idbl = unpackSmall(pdp.Segp, idbl, longs, nsize[i], 2, 16, 16, rmax)
+			case i == 5: // 四分之一字节压缩
+				longs, err := doFread(fdp, -1, 1, (nsize[i]+3)/4)
+				if err != nil {
+					return fail(err)
+				}
+				idbl = unpackSmall(pdp.Segp, idbl, longs, nsize[i], 4, 64, 4, rmax)
+			}
+		}
+	}
+	return Ok, nil
+}
+
+// unpackSmall 解压每字节含n个系数的压缩数据
+// o0为第一个系数的符号位，每取一个系数后除以step
+func unpackSmall(segp []Float64, idbl int, longs []uint64, nsize, n int, o0, step uint64, rmax Float64) int {
+	j := 0
+	for m := 0; m < len(longs) && j < nsize; m++ {
+		l := longs[m]
+		for k, o := 0, o0; k < n && j < nsize; k, o = k+1, o/step {
+			if l&o != 0 {
+				segp[idbl] = -(Float64((l+o)/o/2) * rmax / 2 / 1e+9)
+			} else {
+				segp[idbl] = Float64(l/o/2) * rmax / 2 / 1e+9
+			}
+			l %= o
+			j++
+			idbl++
+		}
+	}
+	return idbl
+}
+
+// rotBack 加上参考轨道，并将切比雪夫系数旋转到J2000平春分点
+func rotBack(ipli int) {
+	// chopt.c 中使用的J2000黄赤交角
+	const seps2000 = 0.39777715572793088
+	const ceps2000 = 0.91748206215761929
+	pdp := &swed.Pldat[ipli]
+	nco := pdp.Ncoe
+	t := pdp.Tseg0 + pdp.Dseg/2
+	chcfx := pdp.Segp[0:nco]
+	chcfy := pdp.Segp[nco : 2*nco]
+	chcfz := pdp.Segp[2*nco : 3*nco]
+	tdiff := (t - pdp.Telem) / 365250.0
+	var qav, pav Float64
+	if ipli == SeiMoon {
+		dn := pdp.Prot + tdiff*pdp.Dprot
+		i := int(dn / TwoPi)
+		dn -= Float64(i) * TwoPi
+		qav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Cos(dn)
+		pav = (pdp.Qrot + tdiff*pdp.Dqrot) * math.Sin(dn)
+	} else {
+		qav = pdp.Qrot + tdiff*pdp.Dqrot
+		pav = pdp.Prot + tdiff*pdp.Dprot
+	}
+	x := make([][3]Float64, nco)
+	for i := 0; i < nco; i++ {
+		x[i] = [3]Float64{chcfx[i], chcfy[i], chcfz[i]}
+	}
+	if pdp.Iflg&SeiFlgEllipse != 0 {
+		refepx := pdp.Refep[0:nco]
+		refepy := pdp.Refep[nco : 2*nco]
+		omtild := pdp.Peri + tdiff*pdp.Dperi
+		i := int(omtild / TwoPi)
+		omtild -= Float64(i) * TwoPi
+		com := math.Cos(omtild)
+		som := math.Sin(omtild)
+		// 加上参考轨道
+		for i := 0; i < nco; i++ {
+			x[i][0] = chcfx[i] + com*refepx[i] - som*refepy[i]
+			x[i][1] = chcfy[i] + com*refepy[i] + som*refepx[i]
+		}
+	}
+	// 构造右手正交坐标系：第一轴指向经度起点，第三轴沿角动量方向
+	cosih2 := 1.0 / (1.0 + qav*qav + pav*pav)
+	// 轨道极
+	uiz := [3]Float64{2.0 * pav * cosih2, -2.0 * qav * cosih2, (1.0 - qav*qav - pav*pav) * cosih2}
+	// 经度起点方向
+	uix := [3]Float64{(1.0 + qav*qav - pav*pav) * cosih2, 2.0 * qav * pav * cosih2, -2.0 * pav * cosih2}
+	// 轨道面内与经度起点正交的方向
+	uiy := [3]Float64{2.0 * qav * pav * cosih2, (1.0 - qav*qav + pav*pav) * cosih2, 2.0 * qav * cosih2}
+	// 旋转到空间中的实际取向
+	for i := 0; i < nco; i++ {
+		xrot := x[i][0]*uix[0] + x[i][1]*uiy[0] + x[i][2]*uiz[0]
+		yrot := x[i][0]*uix[1] + x[i][1]*uiy[1] + x[i][2]*uiz[1]
+		zrot := x[i][0]*uix[2] + x[i][1]*uiy[2] + x[i][2]*uiz[2]
+		if math.Abs(xrot)+math.Abs(yrot)+math.Abs(zrot) >= 1e-14 {
+			pdp.Neval = i
+		}
+		x[i] = [3]Float64{xrot, yrot, zrot}
+		if ipli == SeiMoon {
+			// 旋转到J2000赤道
+			x[i][1] = ceps2000*yrot - seps2000*zrot
+			x[i][2] = seps2000*yrot + ceps2000*zrot
+		}
+	}
+	for i := 0; i < nco; i++ {
+		chcfx[i] = x[i][0]
+		chcfy[i] = x[i][1]
+		chcfz[i] = x[i][2]
+	}
+}
+
+// sweph 由星历文件计算天体的J2000赤道直角坐标（质心或日心，取决于文件）
+// ipli为内部行星编号，ifno为文件编号，xsunb为质心太阳（用于日心小行星）
+func sweph(tjd Float64, ipli, ifno int, iflag Int32, xsunb []Float64, doSave bool, xpret []Float64) (int, error) {
+	var xx [6]Float64
+	ipl := ipli
+	if ipli > SePlmoonOffset {
+		ipl = SeiAnybody
+	}
+	pdp := &swed.Pldat[ipl]
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	fdp := &swed.Fidat[ifno]
+	xp := xx[:]
+	if doSave {
+		xp = pdp.X[:]
+	}
+	// 已经计算过，且不需要重新计算速度
+	speedf1 := pdp.Xflgs & SeflgSpeed
+	speedf2 := iflag & SeflgSpeed
+	if tjd == pdp.Teval && pdp.Iephe == SeflgSwieph &&
+		(speedf2 == 0 || speedf1 != 0) && ipl < SeiAnybody {
+		if xpret != nil {
+			copy(xpret[:6], pdp.X[:])
+		}
+		return Ok, nil
+	}
+	// 找到正确的星历文件
+	if fdp.Fptr != nil {
+		// 超出文件范围或换了小行星，关闭旧文件
+		if tjd < fdp.Tfstart || tjd > fdp.Tfend ||
+			(ipl == SeiAnybody && ipli != pdp.Ibdy) {
+			closeEphFile(ifno)
+			pdp.Refep = nil
+			pdp.Segp = nil
+		}
+	}
+	fname := genFilename(tjd, ipli)
+	if fdp.Fptr == nil {
+		fp, err := sweFopen(ifno, fname, currentEphePath())
+		if err != nil {
+			return NotAvailable, err
+		}
+		fdp.Fptr = fp
+		if retc, err := readConst(ifno); retc != Ok {
+			return retc, err
+		}
+		// 月球星历决定ΔT所用的潮汐加速度
+		if ifno == SeiFileMoon {
+			setTidAccForEphe(0, fdp.SwephDenum)
+		}
+	}
+	// 第一个和最后一个文件不一定覆盖完整的时间段
+	if tjd < fdp.Tfstart || tjd > fdp.Tfend {
+		var s string
+		switch {
+		case ipli > SeAstOffset:
+			s = fmt.Sprintf("小行星 %d（%s）：", ipli-SeAstOffset, filepath.Base(fname))
+		case ipli > SeiPluto:
+			s = fmt.Sprintf("小行星星历文件（%s）：", filepath.Base(fname))
+		case ipli != SeiMoon:
+			s = fmt.Sprintf("行星星历文件（%s）：", filepath.Base(fname))
+		default:
+			s = fmt.Sprintf("月球星历文件（%s）：", filepath.Base(fname))
+		}
+		if tjd < fdp.Tfstart {
+			return NotAvailable, fmt.Errorf("%s儒略日 %f 小于下限 %f", s, tjd, fdp.Tfstart)
+		}
+		return NotAvailable, fmt.Errorf("%s儒略日 %f 大于上限 %f", s, tjd, fdp.Tfend)
+	}
+	// 需要时读取新的系数段
+	if pdp.Segp == nil || tjd < pdp.Tseg0 || tjd > pdp.Tseg1 {
+		if retc, err := getNewSegment(tjd, ipl, ifno); retc != Ok {
+			return retc, err
+		}
+		// 将系数旋转回赤道坐标，必要时加上参考轨道
+		if pdp.Iflg&SeiFlgRotate != 0 {
+			rotBack(ipl)
+		} else {
+			pdp.Neval = pdp.Ncoe
+		}
+	}
+	// 计算tjd处的切比雪夫多项式
+	t := (tjd - pdp.Tseg0) / pdp.Dseg
+	t = t*2 - 1
+	// 光行时改正需要保存位置的速度
+	needSpeed := doSave || iflag&SeflgSpeed != 0
+	for i := 0; i <= 2; i++ {
+		coef := pdp.Segp[i*pdp.Ncoe:]
+		xp[i] = echeb(t, coef, pdp.Neval)
+		if needSpeed {
+			xp[i+3] = edcheb(t, coef, pdp.Neval) / pdp.Dseg * 2
+		} else {
+			xp[i+3] = 0
+		}
+	}
+	// 文件中只有日心和质心地月质心，质心太阳须由两者相减得到
+	if ipl == SeiSunbary && pdp.Iflg&SeiFlgEmbhel != 0 {
+		var xemb [6]Float64
+		// 强制重新计算地月质心
+		tsv := pedp.Teval
+		pedp.Teval = 0
+		retc, err := sweph(tjd, SeiEmb, ifno, iflag|SeflgSpeed, nil, false, xemb[:])
+		if retc != Ok {
+			return retc, err
+		}
+		pedp.Teval = tsv
+		for i := 0; i <= 2; i++ {
+			xp[i] = xemb[i] - xp[i]
+		}
+		if needSpeed {
+			for i := 3; i <= 5; i++ {
+				xp[i] = xemb[i] - xp[i]
+			}
+		}
+	}
+	// 小行星为日心坐标，转换为质心坐标
+	if xsunb != nil && iflag&(SeflgJpleph|SeflgSwieph) != 0 && ipl >= SeiAnybody {
+		for i := 0; i <= 2; i++ {
+			xp[i] += xsunb[i]
+		}
+		if needSpeed {
+			for i := 3; i <= 5; i++ {
+				xp[i] += xsunb[i]
+			}
+		}
+	}
+	if doSave {
+		pdp.Teval = tjd
+		pdp.Xflgs = -1 // 需重新计算光行时等
+		if ifno == SeiFilePlanet || ifno == SeiFileMoon {
+			pdp.Iephe = SeflgSwieph
+		} else {
+			pdp.Iephe = psdp.Iephe
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp[:6])
+	}
+	return Ok, nil
+}
+
+// sweplan 由星历文件计算行星、质心太阳、质心地球和地心月球
+// 结果为J2000赤道直角坐标；xpret、xperet、xpsret、xpmret可以为nil
+func sweplan(tjd Float64, ipli, ifno int, iflag Int32, doSave bool, xpret, xperet, xpsret, xpmret []Float64) (int, error) {
+	var xxp, xxm, xxs, xxe [6]Float64
+	pdp := &swed.Pldat[ipli]
+	pebdp := &swed.Pldat[SeiEmb]
+	psbdp := &swed.Pldat[SeiSunbary]
+	pmdp := &swed.Pldat[SeiMoon]
+	xp, xpe, xps, xpm := xxp[:], xxe[:], xxs[:], xxm[:]
+	if doSave {
+		xp, xpe, xps, xpm = pdp.X[:], pebdp.X[:], psbdp.X[:], pmdp.X[:]
+	}
+	// 文件中部分行星为日心坐标，需要质心太阳
+	doSunbary := doSave || ipli == SeiSunbary || pdp.Iflg&SeiFlgHelio != 0 ||
+		xpsret != nil || iflag&SeflgHelctr != 0
+	doEarth := doSave || ipli == SeiEarth || xperet != nil
+	if ipli == SeiMoon {
+		doEarth = true
+		doSunbary = true
+	}
+	doMoon := doSave || ipli == SeiMoon || ipli == SeiEarth || xperet != nil || xpmret != nil
+	speedf2 := iflag & SeflgSpeed
+	cached := func(p *PlanData) bool {
+		return tjd == p.Teval && p.Iephe == SeflgSwieph &&
+			(speedf2 == 0 || p.Xflgs&SeflgSpeed != 0)
+	}
+	// 质心太阳
+	if doSunbary {
+		if cached(psbdp) {
+			copy(xps, psbdp.X[:])
+		} else if retc, err := sweph(tjd, SeiSunbary, SeiFilePlanet, iflag, nil, doSave, xps); retc != Ok {
+			return retc, err
+		}
+		if xpsret != nil {
+			copy(xpsret[:6], xps)
+		}
+	}
+	// 月球
+	if doMoon {
+		if cached(pmdp) {
+			copy(xpm, pmdp.X[:])
+		} else {
+			retc, err := sweph(tjd, SeiMoon, SeiFileMoon, iflag, nil, doSave, xpm)
+			if retc == Err {
+				return retc, err
+			}
+			// 没有月球星历文件时使用Moshier月球
+			if swed.Fidat[SeiFileMoon].Fptr == nil {
+				if retc, err := moshmoon(tjd, doSave, xpm); retc != Ok {
+					return retc, err
+				}
+			}
+		}
+		if xpmret != nil {
+			copy(xpmret[:6], xpm)
+		}
+	}
+	// 质心地球
+	if doEarth {
+		if cached(pebdp) {
+			copy(xpe, pebdp.X[:])
+		} else {
+			if retc, err := sweph(tjd, SeiEmb, SeiFilePlanet, iflag, nil, doSave, xpe); retc != Ok {
+				return retc, err
+			}
+			// 由地月质心和月球得到地球
+			embofs(xpe, xpm)
+			if doSave || iflag&SeflgSpeed != 0 {
+				embofs(xpe[3:], xpm[3:])
+			}
+		}
+		if xperet != nil {
+			copy(xperet[:6], xpe)
+		}
+	}
+	// SeiSun与SeiEarth编号相同，质心太阳只能以SeiSunbary取得
+	switch ipli {
+	case SeiMoon:
+		copy(xp, xpm)
+	case SeiEarth:
+		copy(xp, xpe)
+	default:
+		// 行星
+		if cached(pdp) {
+			copy(xp, pdp.X[:])
+			return Ok, nil
+		}
+		if retc, err := sweph(tjd, ipli, ifno, iflag, nil, doSave, xp); retc != Ok {
+			return retc, err
+		}
+		// 日心行星转换为质心
+		if pdp.Iflg&SeiFlgHelio != 0 {
+			for i := 0; i <= 2; i++ {
+				xp[i] += xps[i]
+			}
+			if doSave || iflag&SeflgSpeed != 0 {
+				for i := 3; i <= 5; i++ {
+					xp[i] += xps[i]
+				}
+			}
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp)
+	}
+	return Ok, nil
+}
+
+// swemoon 由星历文件计算地心月球的J2000赤道直角坐标
+func swemoon(tjd Float64, iflag Int32, doSave bool, xpret []Float64) (int, error) {
+	var xx [6]Float64
+	pdp := &swed.Pldat[SeiMoon]
+	xp := xx[:]
+	if doSave {
+		xp = pdp.X[:]
+	}
+	// 已经计算过，且不需要重新计算速度
+	speedf1 := pdp.Xflgs & SeflgSpeed
+	speedf2 := iflag & SeflgSpeed
+	if tjd == pdp.Teval && pdp.Iephe == SeflgSwieph && (speedf2 == 0 || speedf1 != 0) {
+		xp = pdp.X[:]
+	} else {
+		if retc, err := sweph(tjd, SeiMoon, SeiFileMoon, iflag, nil, doSave, xp); retc != Ok {
+			return retc, err
+		}
+		if doSave {
+			pdp.Teval = tjd
+			pdp.Xflgs = -1
+			pdp.Iephe = SeflgSwieph
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp[:6])
+	}
+	return Ok, nil
+}
+
+// embofs 由地月质心和地心月球计算地球（位置或速度矢量）
+func embofs(xemb, xmoon []Float64) {
+	for i := 0; i <= 2; i++ {
+		xemb[i] -= xmoon[i] / (EarthMoonMrat + 1.0)
+	}
+}
+
+// getDenum 返回计算所用星历的JPL DE编号
+func getDenum(ipli int, iflag Int32) Int32 {
+	if iflag&SeflgMoseph != 0 {
+		return 403
+	}
+	if iflag&SeflgJpleph != 0 {
+		if swed.Jpldenum > 0 {
+			return swed.Jpldenum
+		}
+		return SeDeNumber
+	}
+	var fdp *FileData
+	switch {
+	case ipli > SePlmoonOffset:
+		fdp = &swed.Fidat[SeiFileAnyAst]
+	case ipli == SeiChiron, ipli == SeiPholus, ipli == SeiCeres,
+		ipli == SeiPallas, ipli == SeiJuno, ipli == SeiVesta:
+		fdp = &swed.Fidat[SeiFileMainAst]
+	case ipli == SeiMoon:
+		fdp = &swed.Fidat[SeiFileMoon]
+	default:
+		fdp = &swed.Fidat[SeiFilePlanet]
+	}
+	if fdp.SwephDenum != 0 {
+		return fdp.SwephDenum
+	}
+	return SeDeNumber
+}
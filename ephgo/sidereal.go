@@ -0,0 +1,547 @@
+package ephgo
+
+import "math"
+
+// 太阳系不变平面在J2000黄道上的升交点和倾角
+const (
+	ssyPlaneNodeE2000 = 107.582569 * DegToRad
+	ssyPlaneNode      = 107.58883388 * DegToRad // 在不变平面上量度
+	ssyPlaneIncl      = 1.578701 * DegToRad
+)
+
+// ayanamsa 预定义岁差的参考历元、该历元的岁差值、历元是否为UT，
+// 以及定义该岁差时所用的岁差模型（0表示无需修正，-1表示未知）
+var ayanamsa = [SeNsidmPredef]AyaInit{
+	{2433282.42346, 24.042044444, false, SemodPrecNewcomb},           // 0: Fagan/Bradley
+	{2435553.5, 23.250182778 - 0.004658035, false, SemodPrecIau1976}, // 1: Lahiri
+	{1721057.5, 0, true, 0},                                  // 2: De Luce
+	{J1900, 360 - 338.98556, false, SemodPrecNewcomb},        // 3: Raman
+	{J1900, 360 - 341.33904, false, -1},                      // 4: Usha/Shashi
+	{J1900, 360 - 337.636111, false, SemodPrecNewcomb},       // 5: Krishnamurti
+	{J1900, 360 - 333.0369024, false, 0},                     // 6: Djwhal Khul
+	{J1900, 360 - 338.917778, false, -1},                     // 7: Yukteshwar
+	{J1900, 360 - 338.634444, false, -1},                     // 8: J.N. Bhasin
+	{1684532.5, -5.66667, true, -1},                          // 9: Babylonian/Kugler 1
+	{1684532.5, -4.26667, true, -1},                          // 10: Babylonian/Kugler 2
+	{1684532.5, -3.41667, true, -1},                          // 11: Babylonian/Kugler 3
+	{1684532.5, -4.46667, true, -1},                          // 12: Babylonian/Huber
+	{1673941, -5.079167, true, -1},                           // 13: Babylonian/Eta Piscium
+	{1684532.5, -4.44138598, true, 0},                        // 14: Babylonian/Aldebaran = 15 Tau
+	{1674484.0, -9.33333, true, -1},                          // 15: Hipparchos
+	{1927135.8747793, 0, true, -1},                           // 16: Sassanian
+	{0, 0, false, 0},                                         // 17: 银心 = 0 Sag
+	{J2000, 0, false, 0},                                     // 18: J2000
+	{J1900, 0, false, 0},                                     // 19: J1900
+	{B1950, 0, false, 0},                                     // 20: B1950
+	{1903396.8128654, 0, true, 0},                            // 21: Suryasiddhanta
+	{1903396.8128654, -0.21463395, true, 0},                  // 22: Suryasiddhanta，平太阳
+	{1903396.7895321, 0, true, 0},                            // 23: Aryabhata
+	{1903396.7895321, -0.23763238, true, 0},                  // 24: Aryabhata，平太阳
+	{1903396.8128654, -0.79167046, true, 0},                  // 25: SS Revati
+	{1903396.8128654, 2.11070444, true, 0},                   // 26: SS Citra
+	{0, 0, false, 0},                                         // 27: True Citra
+	{0, 0, false, 0},                                         // 28: True Revati
+	{0, 0, false, 0},                                         // 29: True Pushya
+	{0, 0, false, 0},                                         // 30: 银心（Gil Brand）
+	{0, 0, false, 0},                                         // 31: 银道（IAU 1958）
+	{0, 0, false, 0},                                         // 32: 银道（真）
+	{0, 0, false, 0},                                         // 33: 银道，Mula中点
+	{2451079.734892000, 30, false, 0},                        // 34: Skydram (Mardyks)
+	{0, 0, false, 0},                                         // 35: True Mula
+	{0, 0, false, 0},                                         // 36: Dhruva/银心/Mula（Wilhelm）
+	{1911797.740782065, 0, true, 0},                          // 37: Aryabhata 522
+	{1721057.5, -3.2, true, -1},                              // 38: Babylonian/Britton
+	{0, 0, false, 0},                                         // 39: "Vedic"/Sheoran
+	{0, 0, false, 0},                                         // 40: Cochrane（银心 = 0 Cap）
+	{2451544.5, 25.0, true, 0},                               // 41: 银道（Fiorenza）
+	{1775845.5, -2.9422, true, -1},                           // 42: Vettius Valens
+	{J1900, 22.44597222, false, SemodPrecNewcomb},            // 43: Lahiri 1940
+	{1825235.2458513028, 0, false, 0},                        // 44: Lahiri VP285
+	{1827424.752255678, 0, false, 0},                         // 45: Krishnamurti-Senthilathiban
+	{2435553.5, 23.25 - 0.00464207, false, SemodPrecNewcomb}, // 46: Lahiri ICRC
+}
+
+// ayanamsaName 预定义岁差的名称
+var ayanamsaName = [SeNsidmPredef]string{
+	"Fagan/Bradley",
+	"Lahiri",
+	"De Luce",
+	"Raman",
+	"Usha/Shashi",
+	"Krishnamurti",
+	"Djwhal Khul",
+	"Yukteshwar",
+	"J.N. Bhasin",
+	"Babylonian/Kugler 1",
+	"Babylonian/Kugler 2",
+	"Babylonian/Kugler 3",
+	"Babylonian/Huber",
+	"Babylonian/Eta Piscium",
+	"Babylonian/Aldebaran = 15 Tau",
+	"Hipparchos",
+	"Sassanian",
+	"Galact. Center = 0 Sag",
+	"J2000",
+	"J1900",
+	"B1950",
+	"Suryasiddhanta",
+	"Suryasiddhanta, mean Sun",
+	"Aryabhata",
+	"Aryabhata, mean Sun",
+	"SS Revati",
+	"SS Citra",
+	"True Citra",
+	"True Revati",
+	"True Pushya (PVRN Rao)",
+	"Galactic Center (Gil Brand)",
+	"Galactic Equator (IAU1958)",
+	"Galactic Equator",
+	"Galactic Equator mid-Mula",
+	"Skydram (Mardyks)",
+	"True Mula (Chandra Hari)",
+	"Dhruva/Gal.Center/Mula (Wilhelm)",
+	"Aryabhata 522",
+	"Babylonian/Britton",
+	"\"Vedic\"/Sheoran",
+	"Cochrane (Gal.Center = 0 Cap)",
+	"Galactic Equator (Fiorenza)",
+	"Vettius Valens",
+	"Lahiri 1940",
+	"Lahiri VP285",
+	"Krishnamurti-Senthilathiban",
+	"Lahiri ICRC",
+}
+
+// GetAyanamsaName 获取岁差名称
+// sidMode可包含SeSidbit*附加位；非预定义岁差（如SeSidmUser）返回空字符串
+func GetAyanamsaName(sidMode Int32) string {
+	sidMode %= SeSidbits
+	if sidMode >= 0 && sidMode < SeNsidmPredef {
+		return ayanamsaName[sidMode]
+	}
+	return ""
+}
+
+// GetAyanamsa 计算历元tjdEt（TT）的岁差（度），不含章动
+// 未调用SetSidMode时使用Fagan/Bradley
+func GetAyanamsa(tjdEt Float64) Float64 {
+	swed := GetSweData()
+	daya, _, _ := getAyanamsaEx(tjdEt, guessEpheFlag())
+	SetSweData(swed)
+	return daya
+}
+
+// GetAyanamsaUT 计算世界时tjdUt的岁差（度），不含章动
+func GetAyanamsaUT(tjdUt Float64) Float64 {
+	swed := GetSweData()
+	daya, _, _ := getAyanamsaEx(tjdUt+calcDeltat(tjdUt, guessEpheFlag())/86400.0, 0)
+	SetSweData(swed)
+	return daya
+}
+
+// GetAyanamsaEx 计算历元tjdEt（TT）的岁差（度）
+// iflag: 星历标志；除非指定SeflgNonut，结果包含黄经章动
+func GetAyanamsaEx(tjdEt Float64, iflag Int32) (Float64, error) {
+	swed := GetSweData()
+	daya, _, err := getAyanamsaExNut(tjdEt, iflag)
+	SetSweData(swed)
+	return daya, err
+}
+
+// GetAyanamsaExUT 计算世界时tjdUt的岁差（度），标志同GetAyanamsaEx
+func GetAyanamsaExUT(tjdUt Float64, iflag Int32) (Float64, error) {
+	swed := GetSweData()
+	if iflag&sefEphMask == 0 {
+		iflag |= SeflgSwieph
+	}
+	daya, _, err := getAyanamsaExNut(tjdUt+calcDeltat(tjdUt, iflag)/86400.0, iflag)
+	SetSweData(swed)
+	return daya, err
+}
+
+// getAyanamsaExNut 计算岁差，除非指定SeflgNonut，加上黄经章动
+func getAyanamsaExNut(tjdEt Float64, iflag Int32) (Float64, Int32, error) {
+	daya, retflag, err := getAyanamsaEx(tjdEt, iflag)
+	if err != nil {
+		return 0, Err, err
+	}
+	if iflag&SeflgNonut == 0 {
+		var nutlo [2]Float64
+		if tjdEt == swed.Nut.Tnut {
+			nutlo = swed.Nut.Nutlo
+		} else {
+			nutlo = nutation(tjdEt, iflag)
+		}
+		daya += nutlo[0] * RadToDeg
+		// 去掉getAyanamsaEx内部加上的标志
+		retflag &^= SeflgNonut
+	}
+	return daya, retflag, nil
+}
+
+// isStarAyanamsa 岁差是否由恒星或银道的实际位置定义
+func isStarAyanamsa(sidMode Int32) bool {
+	switch sidMode {
+	case SeSidmTrueCitra, SeSidmTrueRevati, SeSidmTruePushya, SeSidmTrueSheoran,
+		SeSidmTrueMula, SeSidmGalcent0Sag, SeSidmGalcentCochrane, SeSidmGalcentRgilbrand,
+		SeSidmGalcentMulaWilhelm, SeSidmGalequIau1958, SeSidmGalequTrue, SeSidmGalequMula:
+		return true
+	}
+	return false
+}
+
+// SetSidMode 设置恒星黄道模式（岁差）
+// sidMode: 岁差编号SeSidm*，可与SeSidbit*按位或
+// t0, ayanT0: 仅用于SeSidmUser，参考历元（默认为TT，加SeSidbitUserUt时为UT）及该历元的岁差（度）
+func SetSidMode(sidMode Int32, t0, ayanT0 Float64) {
+	swed := GetSweData()
+	setSidMode(sidMode, t0, ayanT0)
+	SetSweData(swed)
+}
+
+// setSidMode 设置恒星黄道模式，见SetSidMode
+func setSidMode(sidMode Int32, t0, ayanT0 Float64) {
+	sip := &swed.Sidd
+	if sidMode < 0 {
+		sidMode = 0
+	}
+	sip.SidMode = sidMode
+	if sidMode >= SeSidbits {
+		sidMode %= SeSidbits
+	}
+	// 标准春分点：位置总是相对于t0的黄道
+	if sidMode == SeSidmJ2000 || sidMode == SeSidmJ1900 || sidMode == SeSidmB1950 || sidMode == SeSidmGalalignMardyks {
+		sip.SidMode = sidMode | SeSidbitEclT0
+	}
+	// 基于恒星位置的岁差不使用附加位
+	if isStarAyanamsa(sidMode) {
+		sip.SidMode = sidMode
+	}
+	// 只允许预定义岁差和用户自定义岁差
+	if sidMode >= SeNsidmPredef && sidMode != SeSidmUser {
+		sidMode = SeSidmFaganBradley
+		sip.SidMode = sidMode
+	}
+	swed.AyanaIsSet = true
+	if sidMode == SeSidmUser {
+		sip.T0 = t0
+		sip.AyanT0 = ayanT0
+		sip.T0IsUT = sip.SidMode&SeSidbitUserUt != 0
+	} else {
+		sip.T0 = ayanamsa[sidMode].T0
+		sip.AyanT0 = ayanamsa[sidMode].AyanT0
+		sip.T0IsUT = ayanamsa[sidMode].T0IsUT
+	}
+	// 试验功能：使用定义该岁差时的原始岁差模型，并选用相应的章动模型
+	if sidMode < SeNsidmPredef && sip.SidMode&SeSidbitPrecOrig != 0 && ayanamsa[sidMode].PrecOffset > 0 {
+		precOffset := Int32(ayanamsa[sidMode].PrecOffset)
+		swed.AstroModels[SeModelPrecLongterm] = precOffset
+		swed.AstroModels[SeModelPrecShortterm] = precOffset
+		switch precOffset {
+		case SemodPrecNewcomb:
+			swed.AstroModels[SeModelNut] = SemodNutWoolard
+		case SemodPrecIau1976:
+			swed.AstroModels[SeModelNut] = SemodNutIau1980
+		}
+	}
+	forceAppPosEtc()
+}
+
+// forceAppPosEtc 使所有保存的位置失效，下次调用时重新计算
+func forceAppPosEtc() {
+	for i := range swed.Pldat {
+		swed.Pldat[i].Xflgs = -1
+	}
+	for i := range swed.Nddat {
+		swed.Nddat[i].Xflgs = -1
+	}
+	for i := range swed.Savedat {
+		swed.Savedat[i].Tsave = 0
+		swed.Savedat[i].Iflgsave = -1
+	}
+}
+
+// sidT0 返回岁差参考历元t0（TT），ΔT与iflag中的星历一致
+func sidT0(iflag Int32) Float64 {
+	t0 := swed.Sidd.T0
+	if swed.Sidd.T0IsUT {
+		t0 += calcDeltat(t0, iflag) / 86400.0
+	}
+	return t0
+}
+
+// getAyaCorrection 岁差若是用其他岁差模型定义的，计算其修正值（度）
+// 这样该岁差可以和我们的标准岁差模型一起使用，行星的恒星黄道位置保持不变，
+// 但岁差本身的值随所用的岁差模型而变
+func getAyaCorrection(iflag Int32) Float64 {
+	var x [6]Float64
+	sip := &swed.Sidd
+	precModel := swed.AstroModels[SeModelPrecLongterm]
+	precModelShort := swed.AstroModels[SeModelPrecShortterm]
+	sidMode := sip.SidMode % SeSidbits
+	if sip.T0 == J2000 {
+		return 0
+	}
+	if sip.SidMode&SeSidbitNoPrecOffset != 0 {
+		return 0
+	}
+	var precOffset Int32
+	if sidMode < SeNsidmPredef {
+		precOffset = Int32(ayanamsa[sidMode].PrecOffset)
+	}
+	if precOffset < 0 {
+		precOffset = 0
+	}
+	if precModel == precOffset {
+		return 0
+	}
+	t0 := sidT0(iflag)
+	// t0时刻的春分点，直角坐标
+	x[0] = 1
+	precess(x[:], t0, 0, jToJ2000)
+	swed.AstroModels[SeModelPrecLongterm] = precOffset
+	swed.AstroModels[SeModelPrecShortterm] = precOffset
+	precess(x[:], t0, 0, j2000ToJ)
+	swed.AstroModels[SeModelPrecLongterm] = precModel
+	swed.AstroModels[SeModelPrecShortterm] = precModelShort
+	// 转换到黄道极坐标
+	eps := epsiln(t0, 0)
+	coortrf(x[:], x[:], eps)
+	cartpol(x[:], x[:])
+	corr := x[0] * RadToDeg
+	// 取接近0的有符号值
+	if corr > 350 {
+		corr -= 360
+	}
+	return corr
+}
+
+// getStarAyanamsa 计算基于恒星、银心或银道位置的岁差
+func getStarAyanamsa(sidMode Int32, tjdEt Float64, iflag, otherflag Int32) (Float64, Int32, error) {
+	// 基于银道与黄道交点的岁差需要不含光行差和光线偏折的银极位置
+	iflagGalequ := iflag | SeflgTruepos
+	// 真恒星岁差允许以下标志，恒星仍位于规定的位置
+	iflagTrue := iflag | otherflag&(SeflgTruepos|SeflgNoaberr|SeflgNogdefl)
+	var star string
+	var offset Float64
+	switch sidMode {
+	case SeSidmTrueCitra:
+		star, offset = "Spica", 180
+	case SeSidmTrueRevati:
+		star, offset = ",zePsc", 359.8333333333
+	case SeSidmTruePushya:
+		star, offset = ",deCnc", 106 // Asellus Australis
+	case SeSidmTrueSheoran:
+		star, offset = ",deCnc", 103.49264221625
+	case SeSidmTrueMula:
+		star, offset = ",laSco", 240
+	case SeSidmGalcent0Sag:
+		star, offset = ",SgrA*", 240
+	case SeSidmGalcentCochrane:
+		star, offset = ",SgrA*", 270
+	case SeSidmGalcentRgilbrand:
+		star, offset = ",SgrA*", 210+90*0.3819660113
+	case SeSidmGalcentMulaWilhelm:
+		// 银心在黄道上的极投影（赤经对应的黄经）位于Mula中点
+		x, retflag, _, err := builtinFixstar(",SgrA*", tjdEt, iflagTrue|SeflgEquatorial)
+		if err != nil {
+			return 0, Err, err
+		}
+		eps := epsiln(tjdEt, iflag) * RadToDeg
+		return degnorm(armcToMc(x[0], eps) - 246.6666666667), retflag & sefEphMask, nil
+	case SeSidmGalequIau1958:
+		star, offset, iflagTrue = ",GP1958", 150, iflagGalequ
+	case SeSidmGalequTrue:
+		star, offset, iflagTrue = ",GPol", 150, iflagGalequ
+	case SeSidmGalequMula:
+		star, offset, iflagTrue = ",GPol", 150+6.6666666667, iflagGalequ
+	}
+	x, retflag, _, err := builtinFixstar(star, tjdEt, iflagTrue)
+	if err != nil {
+		return 0, Err, err
+	}
+	return degnorm(x[0] - offset), retflag & sefEphMask, nil
+}
+
+// armcToMc 由赤经armc和黄赤交角eps（度）求黄经
+func armcToMc(armc, eps Float64) Float64 {
+	const verySmall = 1e-10
+	if math.Abs(armc-90) <= verySmall {
+		return 90
+	}
+	if math.Abs(armc-270) <= verySmall {
+		return 270
+	}
+	mc := math.Atan(math.Tan(armc*DegToRad)/math.Cos(eps*DegToRad)) * RadToDeg
+	if armc > 90 && armc <= 270 {
+		mc = degnorm(mc + 180)
+	}
+	return mc
+}
+
+// getAyanamsaEx 计算历元tjdEt（TT）的岁差（度），不含章动
+// 返回实际使用的星历标志和SeflgNonut
+func getAyanamsaEx(tjdEt Float64, iflag Int32) (Float64, Int32, error) {
+	var x [6]Float64
+	sip := &swed.Sidd
+	iflag = plausIflag(iflag, -1, tjdEt)
+	otherflag := iflag &^ sefEphMask
+	iflag &= sefEphMask
+	iflag |= SeflgNonut
+	if !swed.AyanaIsSet {
+		setSidMode(SeSidmFaganBradley, 0, 0)
+	}
+	sidMode := sip.SidMode % SeSidbits
+	if isStarAyanamsa(sidMode) {
+		return getStarAyanamsa(sidMode, tjdEt, iflag, otherflag)
+	}
+	if sip.SidMode&SeSidbitEclDate == 0 {
+		// 1999年实现的原始方法，至今仍为默认方法：
+		// 把tjd的春分点岁差换算到t0，岁差为该点在t0黄道上的黄经加上初始值
+		x[0] = 1
+		if tjdEt != J2000 {
+			precess(x[:], tjdEt, 0, jToJ2000)
+		}
+		t0 := sidT0(iflag)
+		precess(x[:], t0, 0, j2000ToJ)
+		// 转换到t0的黄道
+		eps := epsiln(t0, 0)
+		coortrf(x[:], x[:], eps)
+		cartpol(x[:], x[:])
+		// 加上岁差初始值
+		x[0] = -x[0]*RadToDeg + sip.AyanT0
+	} else {
+		// 2020年增加的方法：在当天黄道上量度岁差
+		x[0] = degnorm(sip.AyanT0) * DegToRad
+		x[1] = 0
+		x[2] = 1
+		t0 := sidT0(iflag)
+		eps := epsiln(t0, 0)
+		// t0春分点的赤道直角坐标
+		polcart(x[:], x[:])
+		coortrf(x[:], x[:], -eps)
+		// 岁差换算到J2000，再到当天
+		if t0 != J2000 {
+			precess(x[:], t0, 0, jToJ2000)
+		}
+		precess(x[:], tjdEt, 0, j2000ToJ)
+		// 当天的黄道极坐标
+		eps = epsiln(tjdEt, 0)
+		coortrf(x[:], x[:], eps)
+		cartpol(x[:], x[:])
+		x[0] = degnorm(x[0] * RadToDeg)
+	}
+	corr := getAyaCorrection(iflag)
+	return degnorm(x[0] - corr), iflag, nil
+}
+
+// getAyanamsaWithSpeed 计算岁差及其速度（度/日）
+func getAyanamsaWithSpeed(tjdEt Float64, iflag Int32) ([2]Float64, Int32, error) {
+	const tintv = 0.001
+	var daya [2]Float64
+	dayaT2, _, err := getAyanamsaEx(tjdEt-tintv, iflag)
+	if err != nil {
+		return daya, Err, err
+	}
+	d, retflag, err := getAyanamsaEx(tjdEt, iflag)
+	if err != nil {
+		return daya, Err, err
+	}
+	daya[0] = d
+	daya[1] = (d - dayaT2) / tintv
+	return daya, retflag, nil
+}
+
+// tropRa2sidLon 将J2000赤道直角坐标xin转换为相对于t0黄道的恒星黄道直角坐标xout，
+// 以及相对于t0赤道的赤道直角坐标xoutr
+func tropRa2sidLon(xin, xout, xoutr []Float64, iflag Int32) {
+	var x [6]Float64
+	var oectmp Epsilon
+	sip := &swed.Sidd
+	copy(x[:], xin[:6])
+	if sip.T0 != J2000 {
+		precess(x[:], sip.T0, 0, j2000ToJ)
+		precess(x[3:], sip.T0, 0, j2000ToJ) // 速度
+	}
+	copy(xoutr[:6], x[:])
+	calcEpsilon(sip.T0, iflag, &oectmp)
+	coortrf2(x[:], x[:], oectmp.Seps, oectmp.Ceps)
+	if iflag&SeflgSpeed != 0 {
+		coortrf2(x[3:], x[3:], oectmp.Seps, oectmp.Ceps)
+	}
+	cartpolSp(x[:], x[:])
+	// 减去t0的岁差
+	corr := getAyaCorrection(iflag)
+	x[0] -= sip.AyanT0 * DegToRad
+	x[0] = radnorm(x[0] + corr*DegToRad)
+	polcartSp(x[:], xout)
+}
+
+// tropRa2sidLonSosy 将J2000赤道直角坐标xin转换为投影到太阳系不变平面的恒星黄道直角坐标xout
+func tropRa2sidLonSosy(xin, xout []Float64, iflag Int32) {
+	var x, x0 [6]Float64
+	sip := &swed.Sidd
+	oe := &swed.Oec2000
+	copy(x[:], xin[:6])
+	// 行星转换到J2000黄道
+	coortrf2(x[:], x[:], oe.Seps, oe.Ceps)
+	if iflag&SeflgSpeed != 0 {
+		coortrf2(x[3:], x[3:], oe.Seps, oe.Ceps)
+	}
+	cartpolSp(x[:], x[:])
+	// 转换到太阳系不变平面
+	x[0] -= ssyPlaneNodeE2000
+	polcartSp(x[:], x[:])
+	coortrf(x[:], x[:], ssyPlaneIncl)
+	coortrf(x[3:], x[3:], ssyPlaneIncl)
+	cartpolSp(x[:], x[:])
+	// t0的春分点在J2000坐标系中的位置
+	x0[0] = 1
+	if sip.T0 != J2000 {
+		precess(x0[:], sip.T0, 0, jToJ2000)
+	}
+	coortrf2(x0[:], x0[:], oe.Seps, oe.Ceps)
+	cartpol(x0[:], x0[:])
+	x0[0] -= ssyPlaneNodeE2000
+	polcart(x0[:], x0[:])
+	coortrf(x0[:], x0[:], ssyPlaneIncl)
+	cartpol(x0[:], x0[:])
+	// 从t0的春分点量起
+	x[0] -= x0[0]
+	x[0] *= RadToDeg
+	// 减去t0的岁差
+	corr := getAyaCorrection(iflag)
+	x[0] -= sip.AyanT0
+	x[0] = degnorm(x[0]+corr) * DegToRad
+	polcartSp(x[:], xout)
+}
+
+// sidNdReturn 将交点或拱点的保存区xr转换为恒星黄道坐标，xr为弧度
+// 严格算法先把当天赤道坐标x换算到J2000，再投影到t0的黄道或太阳系不变平面；
+// 传统算法直接从黄经中减去岁差
+func sidNdReturn(tjd Float64, iflag Int32, xr, x []Float64) error {
+	if swed.Sidd.SidMode&(SeSidbitEclT0|SeSidbitSsyPlane) != 0 {
+		precess(x, tjd, iflag, jToJ2000)
+		if iflag&SeflgSpeed != 0 {
+			precessSpeed(x, tjd, iflag, jToJ2000)
+		}
+		if swed.Sidd.SidMode&SeSidbitEclT0 != 0 {
+			tropRa2sidLon(x, xr[6:], xr[18:], iflag)
+		} else {
+			tropRa2sidLonSosy(x, xr[6:], iflag)
+		}
+		cartpolSp(xr[6:], xr)
+		cartpolSp(xr[18:], xr[12:])
+		return nil
+	}
+	cartpolSp(xr[6:], xr)
+	daya, _, err := getAyanamsaWithSpeed(tjd, iflag)
+	if err != nil {
+		return err
+	}
+	xr[0] -= daya[0] * DegToRad
+	xr[3] -= daya[1] * DegToRad
+	polcartSp(xr, xr[6:])
+	cartpolSp(xr[18:], xr[12:])
+	return nil
+}
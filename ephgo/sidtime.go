@@ -0,0 +1,262 @@
+// 格林尼治恒星时，移植自 swephlib.c 的 swe_sidtime 和 swe_sidtime0。
+//
+// 模型由SetAstroModels选择（SemodSidt*）：IAU 1976、IAU 2006（Capitaine等2003）、
+// IERS Conventions 2010（基于地球自转角ERA）以及默认的长期模型，
+// 后者在1850至2050年间与IERS 2010相同，此外由平地球的黄经推算。
+
+package ephgo
+
+import "math"
+
+// 长期恒星时模型只在以下时间之外使用，并减去与IERS 2010之差以保持连续
+const (
+	sidtLtermT0   = 2396758.5              // 1850年1月1日
+	sidtLtermT1   = 2469807.5              // 2050年1月1日
+	sidtLtermOfs0 = 0.000378172 / 15.0     // 小时
+	sidtLtermOfs1 = 0.001385646 / 15.0     // 小时
+	sidtNterm     = 33                     // 非多项式部分的项数
+	sidtNarg      = 14                     // 每项的基本幅角数
+	sidtAunitDays = Aunit / Clight / 86400 // 日地光行时（日）
+)
+
+// stcf IERS 2010恒星时非多项式部分的系数（微角秒）：正弦和余弦
+var stcf = [sidtNterm * 2]Float64{
+	2640.96, -0.39,
+	63.52, -0.02,
+	11.75, 0.01,
+	11.21, 0.01,
+	-4.55, 0.00,
+	2.02, 0.00,
+	1.98, 0.00,
+	-1.72, 0.00,
+	-1.41, -0.01,
+	-1.26, -0.01,
+	-0.63, 0.00,
+	-0.63, 0.00,
+	0.46, 0.00,
+	0.45, 0.00,
+	0.36, 0.00,
+	-0.24, -0.12,
+	0.32, 0.00,
+	0.28, 0.00,
+	0.27, 0.00,
+	0.26, 0.00,
+	-0.21, 0.00,
+	0.19, 0.00,
+	0.18, 0.00,
+	-0.10, 0.05,
+	0.15, 0.00,
+	-0.14, 0.00,
+	0.14, 0.00,
+	-0.14, 0.00,
+	0.14, 0.00,
+	0.13, 0.00,
+	-0.11, 0.00,
+	0.11, 0.00,
+	0.11, 0.00,
+}
+
+// stfarg 非多项式部分各项的幅角系数：
+// l l' F D Om L_Me L_Ve L_E L_Ma L_J L_Sa L_U L_Ne p_A
+var stfarg = [sidtNterm * sidtNarg]int{
+	0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, -2, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, -2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, -2, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, 2, -2, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, 2, -2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 4, -4, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 1, -1, 1, 0, -8, 12, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 2, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, -2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, -2, 2, -3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, -2, 2, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 8, -13, 0, 0, 0, 0, 0, -1,
+	0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	2, 0, -2, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, -2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 1, 2, -2, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, 0, -2, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 4, -2, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 2, -2, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, -2, 0, -3, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, 0, -2, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+}
+
+// sidtimeLongTerm 长期恒星时（小时）
+// 按Simon等（1994）的平地球黄经，用默认岁差模型换算到当天平春分点后求恒星时；
+// 2003年1月1日与IERS Conventions 2010的定义完全一致
+func sidtimeLongTerm(tjdUt, eps, nut Float64) Float64 {
+	tjdEt := tjdUt + calcDeltat(tjdUt, -1)/86400.0
+	t := (tjdEt - J2000) / 365250.0
+	t2 := t * t
+	t3 := t * t2
+	// J2000平地球的黄经
+	dlon := 100.46645683 + (1295977422.83429*t-2.04411*t2-0.00523*t3)/3600.0
+	// 日地光行时
+	dlon = degnorm(dlon - sidtAunitDays*360.0/365.2425)
+	xs := []Float64{dlon * DegToRad, 0, 1, 0, 0, 0}
+	// 到J2000平赤道，直角坐标
+	eps2000 := epsiln(J2000+calcDeltat(J2000, -1)/86400.0, 0) * RadToDeg
+	polcart(xs, xs)
+	coortrf(xs, xs, -eps2000*DegToRad)
+	// 岁差到当天平春分点
+	precess(xs, tjdEt, 0, j2000ToJ)
+	// 到当天平黄道
+	epsDate := epsiln(tjdEt, 0) * RadToDeg
+	nutlo := nutation(tjdEt, 0)
+	epsTrue := epsDate + nutlo[1]*RadToDeg
+	dpsi := nutlo[0] * RadToDeg
+	coortrf(xs, xs, epsDate*DegToRad)
+	cartpol(xs, xs)
+	xs[0] *= RadToDeg
+	dhour := math.Mod(tjdUt-0.5, 1) * 360
+	// 平恒星时到真恒星时（nut不为0时）
+	if eps == 0 {
+		xs[0] += dpsi * math.Cos(epsTrue*DegToRad)
+	} else {
+		xs[0] += nut * math.Cos(eps*DegToRad)
+	}
+	// 加上时角
+	xs[0] = degnorm(xs[0] + dhour)
+	return xs[0] / 15
+}
+
+// sidtimeNonPolynomialPart IERS 2010恒星时的非多项式部分（度），tt为J2000起算的TT儒略世纪数
+func sidtimeNonPolynomialPart(tt Float64) Float64 {
+	var delm [sidtNarg]Float64
+	// 月球平近点角
+	delm[0] = radnorm(2.35555598 + 8328.6914269554*tt)
+	// 太阳平近点角
+	delm[1] = radnorm(6.24006013 + 628.301955*tt)
+	// 月球平升交角距
+	delm[2] = radnorm(1.627905234 + 8433.466158131*tt)
+	// 月日平角距
+	delm[3] = radnorm(5.198466741 + 7771.3771468121*tt)
+	// 月球升交点平黄经
+	delm[4] = radnorm(2.18243920 - 33.757045*tt)
+	// 水星至海王星的平黄经（Souchay等1999）
+	delm[5] = radnorm(4.402608842 + 2608.7903141574*tt)
+	delm[6] = radnorm(3.176146697 + 1021.3285546211*tt)
+	delm[7] = radnorm(1.753470314 + 628.3075849991*tt)
+	delm[8] = radnorm(6.203480913 + 334.0612426700*tt)
+	delm[9] = radnorm(0.599546497 + 52.9690962641*tt)
+	delm[10] = radnorm(0.874016757 + 21.3299104960*tt)
+	delm[11] = radnorm(5.481293871 + 7.4781598567*tt)
+	delm[12] = radnorm(5.321159000 + 3.8127774000*tt)
+	// 黄经总岁差
+	delm[13] = (0.02438175 + 0.00000538691*tt) * tt
+	dadd := -0.87 * math.Sin(delm[4]) * tt
+	for i := 0; i < sidtNterm; i++ {
+		var darg Float64
+		for j := 0; j < sidtNarg; j++ {
+			darg += Float64(stfarg[i*sidtNarg+j]) * delm[j]
+		}
+		dadd += stcf[i*2]*math.Sin(darg) + stcf[i*2+1]*math.Cos(darg)
+	}
+	return dadd / (3600.0 * 1000000.0)
+}
+
+// sidtime0 格林尼治视恒星时（小时），eps为真黄赤交角，nut为黄经章动（度）
+func sidtime0(tjd, eps, nut Float64) Float64 {
+	sidtModel := swed.AstroModels[SeModelSidt]
+	if sidtModel == 0 {
+		sidtModel = SemodSidtDefault
+	}
+	if sidtModel == SemodSidtLongterm && (tjd <= sidtLtermT0 || tjd >= sidtLtermT1) {
+		gmst := sidtimeLongTerm(tjd, eps, nut)
+		if tjd <= sidtLtermT0 {
+			gmst -= sidtLtermOfs0
+		} else {
+			gmst -= sidtLtermOfs1
+		}
+		if gmst >= 24 {
+			gmst -= 24
+		}
+		if gmst < 0 {
+			gmst += 24
+		}
+		return gmst
+	}
+	// 世界时0时的儒略日和当天的秒数
+	jd0 := math.Floor(tjd)
+	secs := tjd - jd0
+	if secs < 0.5 {
+		jd0 -= 0.5
+		secs += 0.5
+	} else {
+		jd0 += 0.5
+		secs -= 0.5
+	}
+	secs *= 86400.0
+	tu := (jd0 - J2000) / 36525.0 // J2000起算的UT1儒略世纪数
+	var gmst Float64
+	switch sidtModel {
+	case SemodSidtIersConv2010, SemodSidtLongterm:
+		// 基于地球自转角和IAU 2006岁差的格林尼治恒星时
+		jdrel := tjd - J2000
+		tt := (tjd + calcDeltat(tjd, -1)/86400.0 - J2000) / 36525.0
+		gmst = degnorm((0.7790572732640 + 1.00273781191135448*jdrel) * 360)
+		gmst += (0.014506 + tt*(4612.156534+tt*(1.3915817+tt*(-0.00000044+tt*(-0.000029956+tt*-0.0000000368))))) / 3600.0
+		gmst = degnorm(gmst + sidtimeNonPolynomialPart(tt))
+		gmst = gmst / 15.0 * 3600.0
+	case SemodSidtIau2006:
+		tt := (jd0 + calcDeltat(jd0, -1)/86400.0 - J2000) / 36525.0 // J2000起算的TT儒略世纪数
+		gmst = (((-0.000000002454*tt-0.00000199708)*tt-0.0000002926)*tt+0.092772110)*tt*tt + 307.4771013*(tt-tu) + 8640184.79447825*tu + 24110.5493771
+		// 每恒星日的平太阳日数，求导时可以认为UT1约等于TT
+		msday := 1 + ((((-0.000000012270*tt-0.00000798832)*tt-0.0000008778)*tt+0.185544220)*tt+8640184.79447825)/(86400.*36525.)
+		gmst += msday * secs
+	default:
+		// IAU 1976：世界时0时的格林尼治平恒星时
+		gmst = ((-6.2e-6*tu+9.3104e-2)*tu+8640184.812866)*tu + 24110.54841
+		// 每恒星日的平太阳日数，1986年为1.00273790934
+		msday := 1.0 + ((-1.86e-5*tu+0.186208)*tu+8640184.812866)/(86400.*36525.)
+		gmst += msday * secs
+	}
+	// 分点差：平恒星时到视恒星时
+	eqeq := 240.0 * nut * math.Cos(eps*DegToRad)
+	gmst += eqeq
+	// 对一个恒星日取模
+	gmst -= 86400.0 * math.Floor(gmst/86400.0)
+	return gmst / 3600
+}
+
+// Sidtime0 计算世界时tjdUt的格林尼治视恒星时（小时）
+// eps为真黄赤交角，nut为黄经章动（度），由此计算分点差；nut为0时得到平恒星时
+// （长期模型在1850年以前和2050年以后eps为0时自行计算章动）。
+// 模型由SetAstroModels选择，见SemodSidt*
+func Sidtime0(tjdUt, eps, nut Float64) Float64 {
+	swed := GetSweData()
+	tsid := sidtime0(tjdUt, eps, nut)
+	SetSweData(swed)
+	return tsid
+}
+
+// sidtime 格林尼治视恒星时（小时），黄赤交角和章动按当前模型计算
+func sidtime(tjdUt Float64) Float64 {
+	// ΔT使用默认的潮汐加速度
+	tjde := tjdUt + calcDeltat(tjdUt, -1)/86400.0
+	eps := epsiln(tjde, 0) * RadToDeg
+	nutlo := nutation(tjde, 0)
+	return sidtime0(tjdUt, eps+nutlo[1]*RadToDeg, nutlo[0]*RadToDeg)
+}
+
+// Sidtime 计算世界时tjdUt的格林尼治视恒星时（小时），包括分点差
+// 黄赤交角和章动按当前的岁差和章动模型计算；当地恒星时为其加上地理经度/15
+func Sidtime(tjdUt Float64) Float64 {
+	swed := GetSweData()
+	tsid := sidtime(tjdUt)
+	SetSweData(swed)
+	return tsid
+}
@@ -0,0 +1,154 @@
+// 恒星文件：读取星历路径中的sefstars.txt（或旧格式的fixstars.cat），
+// 按传统名、拜耳名和序号建立索引，同sweph.c中的load_all_fixed_stars和search_star_in_list。
+
+package ephgo
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 恒星文件
+const (
+	SeStarfile    = "sefstars.txt" // 恒星文件
+	SeStarfileOld = "fixstars.cat" // 旧格式的恒星文件，自行和视差的单位不同
+)
+
+// resetFixstars 丢弃已读取的恒星，下次需要时重新读取
+func resetFixstars() {
+	if swed.Fixfp != nil {
+		swed.Fixfp.Close()
+		swed.Fixfp = nil
+	}
+	swed.IsOldStarfile = false
+	swed.NFixstarsReal = 0
+	swed.NFixstarsNamed = 0
+	swed.NFixstarsRecords = 0
+	swed.FixedStars = nil
+	swed.FixstarIndex = nil
+}
+
+// loadFixstars 读取恒星文件的全部记录，已读取时直接返回
+// 有传统名的恒星以小写、去掉空格的传统名为搜索关键字保存一条记录；
+// 每颗恒星另以","加拜耳名为关键字保存一条记录，与上一行拜耳名相同的别名只保存一次。
+// 记录按关键字排序，拜耳名在前（序号即拜耳名记录的下标加1），传统名在后
+func loadFixstars() error {
+	if swed.NFixstarsRecords > 0 {
+		return nil
+	}
+	if swed.Fixfp == nil {
+		fp, err := sweFopen(SeiFileFixstar, SeStarfile, currentEphePath())
+		if err != nil {
+			fp, err = sweFopen(SeiFileFixstar, SeStarfileOld, currentEphePath())
+			if err != nil {
+				return fmt.Errorf("找不到恒星文件%s", SeStarfile)
+			}
+			swed.IsOldStarfile = true
+		}
+		swed.Fixfp = fp
+	}
+	if _, err := swed.Fixfp.Seek(0, 0); err != nil {
+		return fmt.Errorf("读取恒星文件失败: %v", err)
+	}
+	var named, bayer []FixedStar
+	lastStarbayer := ""
+	scanner := bufio.NewScanner(swed.Fixfp)
+	for scanner.Scan() {
+		s := scanner.Text()
+		// 跳过注释和空行
+		if s == "" || s[0] == '#' || s[0] == '\r' {
+			continue
+		}
+		stardata, _, err := fixstarCutString(s)
+		if err != nil {
+			return err
+		}
+		if stardata.Starname != "" {
+			stardata.Skey = strings.ToLower(strings.ReplaceAll(stardata.Starname, " ", ""))
+			named = append(named, stardata)
+		}
+		// 同一颗恒星的别名在相邻的行中，拜耳名相同
+		if stardata.Starbayer == lastStarbayer {
+			continue
+		}
+		stardata.Skey = "," + strings.ReplaceAll(stardata.Starbayer, " ", "")
+		lastStarbayer = stardata.Starbayer
+		bayer = append(bayer, stardata)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取恒星文件失败: %v", err)
+	}
+	sort.SliceStable(bayer, func(i, j int) bool { return bayer[i].Skey < bayer[j].Skey })
+	sort.SliceStable(named, func(i, j int) bool { return named[i].Skey < named[j].Skey })
+	swed.FixedStars = append(bayer, named...)
+	swed.FixstarIndex = make(map[string]int, len(swed.FixedStars))
+	for i := len(swed.FixedStars) - 1; i >= 0; i-- {
+		swed.FixstarIndex[swed.FixedStars[i].Skey] = i
+	}
+	swed.NFixstarsReal = len(bayer)
+	swed.NFixstarsNamed = len(named)
+	swed.NFixstarsRecords = len(swed.FixedStars)
+	return nil
+}
+
+// LoadFixstars 读取星历路径中的恒星文件，返回恒星的数量
+// 恒星函数在第一次需要时会自动读取，此函数用于预先读取和检查文件；
+// 改变星历路径或调用Close后重新读取
+func LoadFixstars() (int, error) {
+	swed := GetSweData()
+	err := loadFixstars()
+	n := swed.NFixstarsReal
+	SetSweData(swed)
+	return n, err
+}
+
+// fixstarFormatSearchName 将恒星名称转换为搜索关键字：去掉空格，逗号前的传统名转为小写，
+// 逗号后的拜耳名保持大小写
+func fixstarFormatSearchName(star string) (string, error) {
+	sstar := strings.ReplaceAll(star, " ", "")
+	if sstar == "" {
+		return "", fmt.Errorf("恒星名称为空")
+	}
+	if i := strings.IndexByte(sstar, ','); i >= 0 {
+		return strings.ToLower(sstar[:i]) + sstar[i:], nil
+	}
+	return strings.ToLower(sstar), nil
+}
+
+// searchStarInList 在已读取的恒星中查找搜索关键字sstar
+// sstar可以是传统名、","加拜耳名、"传统名,拜耳名"（按拜耳名查找）、序号，
+// 或以'%'结尾的传统名前缀（返回按字母顺序的第一个）
+func searchStarInList(sstar string) (FixedStar, error) {
+	var stardata FixedStar
+	// 序号
+	if sstar[0] >= '0' && sstar[0] <= '9' {
+		starNr := int(atofPrefix(sstar))
+		if starNr < 1 || starNr > swed.NFixstarsReal {
+			return stardata, fmt.Errorf("恒星序号%d超出范围1至%d", starNr, swed.NFixstarsReal)
+		}
+		return swed.FixedStars[starNr-1], nil
+	}
+	if i := strings.IndexByte(sstar, ','); i >= 0 {
+		// 拜耳名
+		sstar = sstar[i:]
+	} else if i := strings.IndexByte(sstar, '%'); i >= 0 {
+		// 传统名前缀
+		if i != len(sstar)-1 {
+			return stardata, fmt.Errorf("无效的恒星搜索名%s", sstar)
+		}
+		prefix := sstar[:i]
+		for _, s := range swed.FixedStars[swed.NFixstarsReal:] {
+			if strings.HasPrefix(s.Skey, prefix) {
+				return s, nil
+			}
+		}
+		return stardata, fmt.Errorf("没有与%s匹配的恒星", sstar)
+	}
+	i, ok := swed.FixstarIndex[sstar]
+	if !ok {
+		return stardata, fmt.Errorf("未找到恒星%s", sstar)
+	}
+	return swed.FixedStars[i], nil
+}
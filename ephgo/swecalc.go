@@ -0,0 +1,1027 @@
+// 天体位置的主计算流程，移植自 sweph.c 的 swecalc 及相关函数。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// 小天体星历的有效范围
+const (
+	chironStart = 1967601.5 // 675年1月1日
+	chironEnd   = 3419437.5 // 4650年1月1日
+	pholusStart = 640648.5  // 公元前2958年1月1日，儒略历
+	pholusEnd   = 4390617.5 // 7309年1月1日
+)
+
+// nutflag 上次计算章动时的标志
+var nutflag Int32
+
+// jplhorflag 上次计算黄赤交角和章动时的JPL Horizons模式标志
+var jplhorflag Int32
+
+// calcEpsilon 计算历元tjd的黄赤交角
+func calcEpsilon(tjd Float64, iflag Int32, e *Epsilon) {
+	e.Teps = tjd
+	e.Eps = epsiln(tjd, iflag)
+	e.Seps = math.Sin(e.Eps)
+	e.Ceps = math.Cos(e.Eps)
+}
+
+// checkEcliptic 计算J2000和历元tjd的黄赤交角（如果尚未计算）
+func checkEcliptic(tjd Float64, iflag Int32) {
+	// JPL Horizons模式改变后黄赤交角和章动都要重新计算
+	if f := iflag & (SeflgJplhor | SeflgJplhorApprox); f != jplhorflag {
+		swed.Oec2000.Teps = 0
+		swed.Oec.Teps = 0
+		swed.Nut.Tnut = 0
+		jplhorflag = f
+	}
+	if swed.Oec2000.Teps != J2000 {
+		calcEpsilon(J2000, iflag, &swed.Oec2000)
+	}
+	if tjd == J2000 {
+		swed.Oec = swed.Oec2000
+		return
+	}
+	if swed.Oec.Teps != tjd || tjd == 0 {
+		calcEpsilon(tjd, iflag, &swed.Oec)
+	}
+}
+
+// checkNutation 计算章动（如果需要且尚未计算）
+// 如果上次计算后打开了速度标志，则重新计算
+func checkNutation(tjd Float64, iflag Int32) {
+	speedf1 := nutflag & SeflgSpeed
+	speedf2 := iflag & SeflgSpeed
+	if iflag&SeflgNonut == 0 &&
+		(tjd != swed.Nut.Tnut || tjd == 0 || (speedf1 == 0 && speedf2 != 0)) {
+		setNut(&swed.Nut, tjd, iflag, &swed.Oec)
+		nutflag = iflag
+		if iflag&SeflgSpeed != 0 {
+			// 行星速度需要章动的变化率
+			setNut(&swed.Nutv, tjd-nutSpeedIntv, iflag, &swed.Oec)
+		}
+	}
+}
+
+// setNut 计算历元tjd的章动及章动矩阵
+func setNut(nu *Nut, tjd Float64, iflag Int32, oe *Epsilon) {
+	nu.Nutlo = nutation(tjd, iflag)
+	nu.Tnut = tjd
+	nu.Snut = math.Sin(nu.Nutlo[1])
+	nu.Cnut = math.Cos(nu.Nutlo[1])
+	nutMatrix(nu, oe)
+}
+
+// plausIflag 修正矛盾的标志并补全不完整的标志
+// JPL Horizons模式只用于JPL星历；未能加载EOP文件时SeflgJplhor改为SeflgJplhorApprox
+func plausIflag(iflag Int32, ipl int, tjd Float64) Int32 {
+	// JPL Horizons模式与近似模式只能选其一
+	if iflag&SeflgJplhor != 0 {
+		iflag &^= SeflgJplhorApprox
+	}
+	// 站心坐标时关闭日心和质心标志
+	if iflag&SeflgTopoctr != 0 {
+		iflag &^= SeflgHelctr | SeflgBaryctr
+	}
+	// 质心和日心标志互斥
+	if iflag&SeflgBaryctr != 0 {
+		iflag &^= SeflgHelctr
+	}
+	if iflag&SeflgHelctr != 0 {
+		iflag &^= SeflgBaryctr
+	}
+	// 日心和质心位置不做光行差和引力偏折改正
+	if iflag&(SeflgHelctr|SeflgBaryctr) != 0 {
+		iflag |= SeflgNoaberr | SeflgNogdefl
+	}
+	// 无岁差时也不做章动
+	if iflag&SeflgJ2000 != 0 {
+		iflag |= SeflgNonut
+	}
+	// 恒星黄道坐标不做章动，也不用JPL Horizons模式
+	if iflag&SeflgSidereal != 0 {
+		iflag |= SeflgNonut
+		iflag &^= SeflgJplhor | SeflgJplhorApprox
+	}
+	// 几何位置不做光行差和引力偏折改正
+	if iflag&SeflgTruepos != 0 {
+		iflag |= SeflgNogdefl | SeflgNoaberr
+	}
+	var epheflag Int32
+	if iflag&SeflgMoseph != 0 {
+		epheflag = SeflgMoseph
+	}
+	if iflag&SeflgSwieph != 0 {
+		epheflag = SeflgSwieph
+	}
+	if iflag&SeflgJpleph != 0 {
+		epheflag = SeflgJpleph
+	}
+	if epheflag == 0 {
+		epheflag = SeflgSwieph
+	}
+	iflag = (iflag &^ sefEphMask) | epheflag
+	// JPL Horizons模式只用于JPL星历
+	if epheflag&SeflgJpleph == 0 {
+		iflag &^= SeflgJplhor | SeflgJplhorApprox
+	}
+	// 交点和拱点没有JPL Horizons模式
+	if ipl >= SeMeanNode && ipl <= SeOscuApog || ipl == SeIntpApog || ipl == SeIntpPerg {
+		iflag &^= SeflgJplhor | SeflgJplhorApprox
+	}
+	if iflag&SeflgJplhor != 0 && swed.EopDpsiLoaded <= 0 {
+		iflag &^= SeflgJplhor
+		iflag |= SeflgJplhorApprox
+	}
+	// JPL Horizons模式的坐标相对于ICRS
+	if iflag&SeflgJplhor != 0 {
+		iflag |= SeflgIcrs
+	}
+	if iflag&SeflgJplhorApprox != 0 && jplhoraModel() == SemodJplhora2 {
+		iflag |= SeflgIcrs
+	}
+	return iflag
+}
+
+// openJplFileSwe 打开JPL星历文件；默认的DE431不存在时改用DE406
+func openJplFileSwe(ss []Float64) (int, error) {
+	fname := swed.Jplfnam
+	if fname == "" {
+		fname = SeFnameDft
+	}
+	fpath := currentEphePath()
+	err := OpenJplFile(ss, fname, fpath)
+	if err != nil && fname == SeFnameDft {
+		if OpenJplFile(ss, SeFnameDft2, fpath) == nil {
+			swed.Jplfnam = SeFnameDft2
+			err = nil
+		}
+	}
+	if err != nil {
+		return NotAvailable, err
+	}
+	swed.Jpldenum = GetJplDenum()
+	swed.JplFileIsOpen = true
+	setTidAccForEphe(0, swed.Jpldenum)
+	// JPL Horizons模式需要EOP文件中的dpsi和deps；文件不存在时不报错
+	if swed.Jpldenum >= 403 {
+		loadEop()
+	}
+	return Ok, nil
+}
+
+// closeJplFileSwe 关闭JPL星历文件
+func closeJplFileSwe() {
+	CloseJplFile()
+	swed.JplFileIsOpen = false
+}
+
+// jplplan 由JPL星历计算行星、质心地球和质心太阳的J2000赤道直角坐标
+// 月球为地心坐标，其他天体为质心坐标
+func jplplan(tjd Float64, ipli int, iflag Int32, doSave bool, xpret, xperet, xpsret []Float64) (int, error) {
+	var xxp, xxe, xxs [6]Float64
+	var ss [3]Float64
+	ictr := JSbary
+	pdp := &swed.Pldat[ipli]
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	xp, xpe, xps := xxp[:], xxe[:], xxs[:]
+	if doSave {
+		xp, xpe, xps = pdp.X[:], pedp.X[:], psdp.X[:]
+	}
+	doEarth := doSave || ipli == SeiEarth || xperet != nil || ipli == SeiMoon
+	doSunbary := doSave || ipli == SeiSunbary || xpsret != nil || ipli == SeiMoon
+	if ipli == SeiMoon {
+		ictr = JEarth
+	}
+	// 打开星历文件
+	if !swed.JplFileIsOpen {
+		if retc, err := openJplFileSwe(ss[:]); retc != Ok {
+			return retc, err
+		}
+	}
+	// pleph 计算并在需要时保存
+	pleph := func(p *PlanData, ntarg, ncent int, x []Float64) (int, error) {
+		retc, err := jplPleph(tjd, ntarg, ncent, x)
+		if doSave {
+			p.Teval = tjd
+			p.Xflgs = -1 // 需要重新计算光行时等
+			p.Iephe = SeflgJpleph
+		}
+		if retc != Ok {
+			closeJplFileSwe()
+		}
+		return retc, err
+	}
+	// 质心地球
+	if doEarth {
+		if tjd != pedp.Teval || tjd == 0 {
+			if retc, err := pleph(pedp, JEarth, JSbary, xpe); retc != Ok {
+				return retc, err
+			}
+		} else {
+			xpe = pedp.X[:]
+		}
+		if xperet != nil {
+			copy(xperet[:6], xpe[:6])
+		}
+	}
+	// 质心太阳
+	if doSunbary {
+		if tjd != psdp.Teval || tjd == 0 {
+			if retc, err := pleph(psdp, JSun, JSbary, xps); retc != Ok {
+				return retc, err
+			}
+		} else {
+			xps = psdp.X[:]
+		}
+		if xpsret != nil {
+			copy(xpsret[:6], xps[:6])
+		}
+	}
+	switch {
+	case ipli == SeiEarth:
+		copy(xp[:6], xpe[:6])
+	case ipli == SeiSunbary:
+		copy(xp[:6], xps[:6])
+	case tjd == pdp.Teval && pdp.Iephe == SeflgJpleph:
+		// 已经计算过
+		xp = pdp.X[:]
+	default:
+		if retc, err := pleph(pdp, pnoint2jpl[ipli], ictr, xp); retc != Ok {
+			return retc, err
+		}
+	}
+	if xpret != nil {
+		copy(xpret[:6], xp[:6])
+	}
+	return Ok, nil
+}
+
+// mainPlanet 由所选星历计算主要行星（如果该时刻尚未计算），并转换为视位置
+// 所需星历不可用时依次改用瑞士星历和Moshier星历
+// 结果保存在swed.Pldat[ipli].Xreturn中，实际所用的标志保存在Xflgs中
+func mainPlanet(tjd Float64, ipli int, epheflag, iflag Int32) (int, error) {
+	var retc int
+	var err error
+	// appPos 地心坐标、光行时等
+	appPos := func() (int, error) {
+		if ipli == SeiSun {
+			return appPosEtcSun(iflag)
+		}
+		return appPosEtcPlan(ipli, iflag)
+	}
+	inMoshRange := tjd > moshplephStart && tjd < moshplephEnd
+	if epheflag == SeflgJpleph {
+		retc, err = jplplan(tjd, ipli, iflag, true, nil, nil, nil)
+		if retc == Err {
+			return Err, err
+		}
+		if retc == Ok {
+			retc, err = appPos()
+			if retc == Err {
+				return Err, err
+			}
+		}
+		switch {
+		case retc == NotAvailable:
+			// JPL星历文件不存在
+			iflag = (iflag &^ SeflgJpleph) | SeflgSwieph
+			epheflag = SeflgSwieph
+		case retc == BeyondEphLimits && inMoshRange:
+			iflag = (iflag &^ SeflgJpleph) | SeflgMoseph
+			epheflag = SeflgMoseph
+		case retc == BeyondEphLimits:
+			return Err, err
+		}
+	}
+	if epheflag == SeflgSwieph {
+		// 质心行星（以及地球、太阳和月球）
+		retc, err = sweplan(tjd, ipli, SeiFilePlanet, iflag, true, nil, nil, nil, nil)
+		if retc == Err {
+			return Err, err
+		}
+		if retc == Ok {
+			retc, err = appPos()
+			if retc == Err {
+				return Err, err
+			}
+		}
+		// 星历文件不存在，改用Moshier星历
+		if retc == NotAvailable {
+			if !inMoshRange {
+				return Err, err
+			}
+			iflag = (iflag &^ SeflgSwieph) | SeflgMoseph
+			epheflag = SeflgMoseph
+		}
+	}
+	if epheflag == SeflgMoseph {
+		if retc, err = moshplan(tjd, ipli, true, nil, nil); retc == Err {
+			return Err, err
+		}
+		if retc, err = appPos(); retc == Err {
+			return Err, err
+		}
+	}
+	return Ok, nil
+}
+
+// mainPlanetBary 由所选星历计算主要行星的质心位置xp，以及地球xe和质心太阳xs
+// 所需星历不可用时依次改用瑞士星历和Moshier星历；Moshier星历的太阳位置为零
+func mainPlanetBary(tjd Float64, ipli int, epheflag, iflag Int32, doSave bool, xp, xe, xs []Float64) (int, error) {
+	if epheflag == SeflgJpleph {
+		retc, err := jplplan(tjd, ipli, iflag, doSave, xp, xe, xs)
+		if retc == Err || retc == BeyondEphLimits {
+			return retc, err
+		}
+		if retc != NotAvailable {
+			return Ok, nil
+		}
+		// JPL星历文件不存在
+		iflag = (iflag &^ SeflgJpleph) | SeflgSwieph
+		epheflag = SeflgSwieph
+	}
+	if epheflag == SeflgSwieph {
+		retc, err := sweplan(tjd, ipli, SeiFilePlanet, iflag, doSave, xp, xe, xs, nil)
+		if retc == Err {
+			return Err, err
+		}
+		if retc != NotAvailable {
+			return Ok, nil
+		}
+		// 星历文件不存在，改用Moshier星历
+		if tjd <= moshplephStart || tjd >= moshplephEnd {
+			return Err, err
+		}
+		epheflag = SeflgMoseph
+	}
+	if epheflag == SeflgMoseph {
+		if retc, err := moshplan(tjd, ipli, doSave, xp, xe); retc == Err {
+			return Err, err
+		}
+		for i := 0; i <= 5; i++ {
+			xs[i] = 0
+		}
+	}
+	return Ok, nil
+}
+
+// swecalc 计算天体ipl的位置，结果（24个值）写入x：
+// 黄道极坐标、黄道直角坐标、赤道极坐标、赤道直角坐标，各含速度
+// 返回实际使用的标志
+func swecalc(tjd Float64, ipl int, iflag Int32, x []Float64) (Int32, error) {
+	var xp []Float64
+	pedp := &swed.Pldat[SeiEarth]
+	psdp := &swed.Pldat[SeiSunbary]
+	fail := func(err error) (Int32, error) {
+		for i := 0; i < 24; i++ {
+			x[i] = 0
+		}
+		return Err, err
+	}
+	iflag = plausIflag(iflag, ipl, tjd)
+	epheflag := iflag & sefEphMask
+	// Moshier星历不支持质心坐标
+	if iflag&SeflgBaryctr != 0 && epheflag == SeflgMoseph {
+		return fail(fmt.Errorf("Moshier星历不支持质心坐标"))
+	}
+	// 交点和拱点只有地心位置
+	if ipl >= SeMeanNode && ipl <= SeOscuApog || ipl == SeIntpApog || ipl == SeIntpPerg {
+		if iflag&(SeflgHelctr|SeflgBaryctr) != 0 {
+			return fail(fmt.Errorf("%s的日心或质心位置没有意义", GetPlanetName(ipl)))
+		}
+	}
+	// 未设置恒星黄道模式时默认为Fagan/Bradley
+	if iflag&SeflgSidereal != 0 && !swed.AyanaIsSet {
+		setSidMode(SeSidmFaganBradley, 0, 0)
+	}
+	// J2000和当天的黄赤交角，章动
+	checkEcliptic(tjd, iflag)
+	checkNutation(tjd, iflag)
+	switch {
+	case ipl == SeEclNut:
+		// 黄赤交角和章动
+		x[0] = swed.Oec.Eps + swed.Nut.Nutlo[1] // 真黄赤交角
+		x[1] = swed.Oec.Eps                     // 平黄赤交角
+		x[2] = swed.Nut.Nutlo[0]                // 黄经章动
+		x[3] = swed.Nut.Nutlo[1]                // 交角章动
+		for i := 0; i <= 3; i++ {
+			x[i] *= RadToDeg
+		}
+		return iflag, nil
+	case ipl == SeMoon:
+		pdp := &swed.Pldat[SeiMoon]
+		xp = pdp.Xreturn[:]
+		var retc int
+		var err error
+		if epheflag == SeflgJpleph {
+			retc, err = jplplan(tjd, SeiMoon, iflag, true, nil, nil, nil)
+			switch {
+			case retc == Err:
+				return fail(err)
+			case retc == NotAvailable:
+				iflag = (iflag &^ SeflgJpleph) | SeflgSwieph
+				epheflag = SeflgSwieph
+			case retc == BeyondEphLimits && tjd > moshluephStart && tjd < moshluephEnd:
+				iflag = (iflag &^ SeflgJpleph) | SeflgMoseph
+				epheflag = SeflgMoseph
+			case retc == BeyondEphLimits:
+				return fail(err)
+			}
+		}
+		if epheflag == SeflgSwieph {
+			retc, err = sweplan(tjd, SeiMoon, SeiFileMoon, iflag, true, nil, nil, nil, nil)
+			if retc == Err {
+				return fail(err)
+			}
+			// 星历文件不存在，改用Moshier星历
+			if retc == NotAvailable {
+				if tjd <= moshluephStart || tjd >= moshluephEnd {
+					return fail(err)
+				}
+				iflag = (iflag &^ SeflgSwieph) | SeflgMoseph
+				epheflag = SeflgMoseph
+			}
+		}
+		if epheflag == SeflgMoseph {
+			if retc, err = moshmoon(tjd, true, nil); retc == Err {
+				return fail(err)
+			}
+			// 日心位置还需要地球
+			if retc, err = moshplan(tjd, SeiEarth, true, nil, nil); retc == Err {
+				return fail(err)
+			}
+		}
+		// 日心、光行时等
+		if retc, err = appPosEtcMoon(iflag); retc != Ok {
+			return fail(err)
+		}
+	case ipl == SeSun && iflag&SeflgBaryctr != 0:
+		// 质心太阳需要单独处理：内部编号SeiSun与SeiEarth相同，
+		// mainPlanet()无法区分质心太阳和质心地球
+		xp = pedp.Xreturn[:]
+		var retc int
+		var err error
+		if epheflag == SeflgJpleph {
+			var ss [3]Float64
+			retc = Ok
+			if !swed.JplFileIsOpen {
+				retc, err = openJplFileSwe(ss[:])
+			}
+			if retc == Ok {
+				retc, err = jplPleph(tjd, JSun, JSbary, psdp.X[:])
+				if retc == Err || retc == BeyondEphLimits {
+					closeJplFileSwe()
+					return fail(err)
+				}
+			}
+			if retc == NotAvailable {
+				iflag = (iflag &^ SeflgJpleph) | SeflgSwieph
+				epheflag = SeflgSwieph
+			}
+		}
+		if epheflag == SeflgSwieph {
+			// sweplan()同时计算质心太阳，保存在swed.Pldat[SeiSunbary].X中
+			if retc, err = sweplan(tjd, SeiEarth, SeiFilePlanet, iflag, true, nil, nil, nil, nil); retc != Ok {
+				return fail(err)
+			}
+		}
+		psdp.Teval = tjd
+		if retc, err = appPosEtcSbar(iflag); retc != Ok {
+			return fail(err)
+		}
+		// 标志可能已改变
+		iflag = pedp.Xflgs
+		// 质心太阳现在位于质心地球的保存区中，
+		// 强制同一时刻随后的质心地球重新计算
+		pedp.Xflgs = -1
+	case ipl >= SeSun && ipl <= SePluto || ipl == SeEarth:
+		// 太阳的日心位置和地球的地心位置不存在
+		if iflag&SeflgHelctr != 0 && ipl == SeSun ||
+			iflag&(SeflgHelctr|SeflgBaryctr) == 0 && ipl == SeEarth {
+			for i := 0; i < 24; i++ {
+				x[i] = 0
+			}
+			return iflag, nil
+		}
+		ipli := pnoext2int[ipl]
+		pdp := &swed.Pldat[ipli]
+		xp = pdp.Xreturn[:]
+		if retc, err := mainPlanet(tjd, ipli, epheflag, iflag); retc == Err {
+			return fail(err)
+		}
+		// 标志可能在mainPlanet()中改变
+		iflag = pdp.Xflgs
+	case ipl == SeMeanNode || ipl == SeMeanApog:
+		ndp := &swed.Nddat[SeiMeanNode]
+		meanFn := meanNode
+		if ipl == SeMeanApog {
+			ndp = &swed.Nddat[SeiMeanApog]
+			meanFn = meanApog
+		}
+		xp = ndp.Xreturn[:]
+		xp2 := ndp.X[:]
+		if retc, err := meanFn(tjd, xp2); retc == Err {
+			return fail(err)
+		}
+		// 速度（平交点的速度几乎不变，平远地点的速度变化可达数角秒）
+		if retc, err := meanFn(tjd-meanNodeSpeedIntv, xp2[3:]); retc == Err {
+			return fail(err)
+		}
+		xp2[3] = difrad2n(xp2[0], xp2[3]) / meanNodeSpeedIntv
+		if ipl == SeMeanApog {
+			xp2[4] = difrad2n(xp2[1], xp2[4]) / meanNodeSpeedIntv
+		} else {
+			xp2[4] = 0
+		}
+		xp2[5] = 0
+		ndp.Teval = tjd
+		ndp.Xflgs = -1
+		// 光行时等
+		if retc, err := appPosEtcMean(ndpIndex(ipl), iflag); retc != Ok {
+			return fail(err)
+		}
+		if ipl == SeMeanApog {
+			// 避免坐标转换造成距离速度的微小偏差
+			ndp.Xreturn[5] = 0
+		} else if iflag&SeflgSidereal == 0 && iflag&SeflgJ2000 == 0 {
+			// 避免坐标转换造成黄纬的微小偏差
+			ndp.Xreturn[1] = 0  // 黄纬
+			ndp.Xreturn[4] = 0  // 黄纬速度
+			ndp.Xreturn[5] = 0  // 距离速度
+			ndp.Xreturn[8] = 0  // z坐标
+			ndp.Xreturn[11] = 0 // z速度
+		}
+	case ipl == SeTrueNode || ipl == SeOscuApog:
+		ndp := &swed.Nddat[ndpIndex(ipl)]
+		xp = ndp.Xreturn[:]
+		retc, err := lunarOscElem(tjd, ndpIndex(ipl), iflag)
+		if retc == Err {
+			return fail(err)
+		}
+		iflag = ndp.Xflgs
+		if ipl == SeTrueNode && iflag&SeflgSidereal == 0 && iflag&SeflgJ2000 == 0 {
+			ndp.Xreturn[1] = 0  // 黄纬
+			ndp.Xreturn[4] = 0  // 黄纬速度
+			ndp.Xreturn[8] = 0  // z坐标
+			ndp.Xreturn[11] = 0 // z速度
+		}
+	case ipl == SeIntpApog || ipl == SeIntpPerg:
+		if tjd < moshluephStart || tjd > moshluephEnd {
+			return fail(fmt.Errorf("插值拱点只能计算儒略日 %8.1f - %8.1f 之间的位置", moshluephStart, moshluephEnd))
+		}
+		ndp := &swed.Nddat[ndpIndex(ipl)]
+		xp = ndp.Xreturn[:]
+		if retc, err := intpApsides(tjd, ndpIndex(ipl), iflag); retc == Err {
+			return fail(err)
+		}
+		iflag = ndp.Xflgs
+	case ipl >= SeChiron && ipl <= SeVesta || ipl > SePlmoonOffset:
+		// 小行星
+		var ipli, ipliAst int
+		switch {
+		case ipl < SeNplanets:
+			ipli = pnoext2int[ipl]
+		case ipl > SeAstOffset && ipl <= SeAstOffset+MpcVesta:
+			ipli = SeiCeres + ipl - SeAstOffset - 1
+		default:
+			ipli = SeiAnybody
+		}
+		ipliAst = ipli
+		if ipli == SeiAnybody {
+			ipliAst = ipl
+		}
+		pdp := &swed.Pldat[ipli]
+		xp = pdp.Xreturn[:]
+		ifno := SeiFileMainAst
+		if ipliAst > SePlmoonOffset {
+			ifno = SeiFileAnyAst
+		}
+		if ipli == SeiChiron && (tjd < chironStart || tjd > chironEnd) {
+			return fail(fmt.Errorf("Chiron星历只能计算儒略日 %8.1f - %8.1f 之间的位置", chironStart, chironEnd))
+		}
+		if ipli == SeiPholus && (tjd < pholusStart || tjd > pholusEnd) {
+			return fail(fmt.Errorf("Pholus星历只能计算儒略日 %8.1f - %8.1f 之间的位置", pholusStart, pholusEnd))
+		}
+		for {
+			// 同时需要地球和太阳
+			if retc, err := mainPlanet(tjd, SeiEarth, epheflag, iflag); retc == Err {
+				return fail(err)
+			}
+			// 星历标志可能在mainPlanet()中改变
+			iflag = pedp.Xflgs
+			if retc, err := sweph(tjd, ipliAst, ifno, iflag, psdp.X[:], true, nil); retc != Ok {
+				return fail(err)
+			}
+			retc, err := appPosEtcPlan(ipliAst, iflag)
+			if retc == Err {
+				return fail(err)
+			}
+			// 光行时改正后的时刻可能超出星历范围，改用Moshier星历重新计算
+			if retc == NotAvailable || retc == BeyondEphLimits {
+				if epheflag == SeflgMoseph {
+					return fail(err)
+				}
+				iflag = (iflag &^ sefEphMask) | SeflgMoseph
+				epheflag = SeflgMoseph
+				continue
+			}
+			break
+		}
+	default:
+		return fail(fmt.Errorf("无效的天体编号 %d", ipl))
+	}
+	copy(x[:24], xp[:24])
+	return iflag, nil
+}
+
+// ndpIndex 外部的交点和拱点编号到swed.Nddat索引的映射
+func ndpIndex(ipl int) int {
+	switch ipl {
+	case SeMeanNode:
+		return SeiMeanNode
+	case SeTrueNode:
+		return SeiTrueNode
+	case SeMeanApog:
+		return SeiMeanApog
+	case SeOscuApog:
+		return SeiOscuApog
+	case SeIntpApog:
+		return SeiIntpApog
+	default:
+		return SeiIntpPerg
+	}
+}
+
+// moonPositions 计算月球在tjd-intv、tjd+intv和tjd三个时刻的位置和速度（当天黄道坐标）
+// 不需要速度时只计算最后一个；所需星历不可用时依次改用瑞士星历和Moshier星历
+func moonPositions(tjd Float64, iflag Int32, istart int, xpos *[3][6]Float64) (Int32, Float64, error) {
+	epheflag := iflag & sefEphMask
+	for {
+		var retc int
+		var err error
+		speedIntv := Float64(nodeCalcIntv)
+		if epheflag == SeflgMoseph {
+			// Moshier月球的交点和远地点在短时间内剧烈振荡，需要较大的间隔
+			speedIntv = nodeCalcIntvMosh
+		}
+		for i := istart; i <= 2; i++ {
+			t := tjd
+			switch i {
+			case 0:
+				t = tjd - speedIntv
+			case 1:
+				t = tjd + speedIntv
+			}
+			xp := xpos[i][:]
+			switch epheflag {
+			case SeflgJpleph:
+				retc, err = jplplan(t, SeiMoon, iflag, false, xp, nil, nil)
+				// 视交点需要经光行时改正的月球位置
+				if iflag&SeflgTruepos == 0 && retc == Ok {
+					retc, err = jplplan(t-lightTime(xp), SeiMoon, iflag, false, xp, nil, nil)
+				}
+			case SeflgSwieph:
+				retc, err = swemoon(t, iflag|SeflgSpeed, false, xp)
+				if iflag&SeflgTruepos == 0 && retc == Ok {
+					retc, err = swemoon(t-lightTime(xp), iflag|SeflgSpeed, false, xp)
+				}
+			default:
+				retc, err = moshmoon(t, false, xp)
+			}
+			if retc != Ok {
+				break
+			}
+			// 岁差和章动等
+			planForOscElem(iflag|SeflgSpeed, t, xp)
+		}
+		switch {
+		case retc == Ok:
+			return iflag, speedIntv, nil
+		case retc == NotAvailable && epheflag == SeflgJpleph:
+			iflag = (iflag &^ SeflgJpleph) | SeflgSwieph
+			epheflag = SeflgSwieph
+		case retc != Err && epheflag != SeflgMoseph && tjd > moshluephStart && tjd < moshluephEnd:
+			iflag = (iflag &^ sefEphMask) | SeflgMoseph
+			epheflag = SeflgMoseph
+		default:
+			return iflag, 0, err
+		}
+	}
+}
+
+// lunarOscElem 计算月球的密切交点（真交点）和密切远地点
+// 二者的结果分别保存在swed.Nddat[SeiTrueNode]和swed.Nddat[SeiOscuApog]中
+func lunarOscElem(tjd Float64, ipl int, iflag Int32) (int, error) {
+	var xpos, xx, xxa [3][6]Float64
+	var xnorm [3]Float64
+	oe := &swed.Oec
+	ndp := &swed.Nddat[ipl]
+	// 同一时刻已经计算过，除非需要速度而上次没有计算
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := ndp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	speedf1 := ndp.Xflgs & SeflgSpeed
+	speedf2 := iflag & SeflgSpeed
+	if tjd == ndp.Teval && tjd != 0 && flg1 == flg2 && (speedf2 == 0 || speedf1 != 0) {
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & sefEphMask
+		return Ok, nil
+	}
+	// 月球轨道面由月球的地心位置和速度矢量确定，交点在轨道面与黄道面的交线上
+	// 求交点需要一个带速度的月球位置，求交点的速度需要三个
+	// 保存区中可能有其他星历的月球，强制重新计算
+	swed.Pldat[SeiMoon].Teval = 0
+	istart := 2
+	if iflag&SeflgSpeed != 0 {
+		istart = 0
+	}
+	iflag, speedIntv, err := moonPositions(tjd, iflag, istart, &xpos)
+	if err != nil {
+		return Err, err
+	}
+	epheflag := iflag & sefEphMask
+	// 三个交点
+	for i := istart; i <= 2; i++ {
+		if math.Abs(xpos[i][5]) < 1e-15 {
+			xpos[i][5] = 1e-15
+		}
+		fac := xpos[i][2] / xpos[i][5]
+		sgn := xpos[i][5] / math.Abs(xpos[i][5])
+		for j := 0; j <= 2; j++ {
+			xx[i][j] = (xpos[i][j] - fac*xpos[i][j+3]) * sgn
+		}
+	}
+	// 至此得到交点的方向，其距离由下面的密切椭圆重新确定
+	// 三个远地点，同时求交点的距离
+	gmsm := Geogconst * (1 + 1/EarthMoonMrat) / Aunit / Aunit / Aunit * 86400.0 * 86400.0
+	for i := istart; i <= 2; i++ {
+		// 交点
+		rxy := math.Sqrt(xx[i][0]*xx[i][0] + xx[i][1]*xx[i][1])
+		cosnode := xx[i][0] / rxy
+		sinnode := xx[i][1] / rxy
+		// 轨道倾角
+		crossProd(xpos[i][:3], xpos[i][3:], xnorm[:])
+		rxy = xnorm[0]*xnorm[0] + xnorm[1]*xnorm[1]
+		c2 := rxy + xnorm[2]*xnorm[2]
+		rxyz := math.Sqrt(c2)
+		rxy = math.Sqrt(rxy)
+		sinincl := rxy / rxyz
+		cosincl := math.Sqrt(1 - sinincl*sinincl)
+		// 纬度幅角
+		cosu := xpos[i][0]*cosnode + xpos[i][1]*sinnode
+		sinu := xpos[i][2] / sinincl
+		uu := math.Atan2(sinu, cosu)
+		// 半长轴
+		rxyz = math.Sqrt(squareSum3(xpos[i][:]))
+		v2 := squareSum3(xpos[i][3:])
+		sema := 1 / (2/rxyz - v2/gmsm)
+		// 偏心率
+		pp := c2 / gmsm
+		ecce := math.Sqrt(1 - pp/sema)
+		// 偏近点角
+		cosE := 1 / ecce * (1 - rxyz/sema)
+		sinE := 1 / ecce / math.Sqrt(sema*gmsm) * dotProd3(xpos[i][:], xpos[i][3:])
+		// 真近点角
+		ny := 2 * math.Atan(math.Sqrt((1+ecce)/(1-ecce))*sinE/(1+cosE))
+		// 远地点到升交点的角距
+		xxa[i][0] = mod2PI(uu - ny + Pi)
+		xxa[i][1] = 0
+		xxa[i][2] = sema * (1 + ecce)
+		// 转换到黄道坐标，加上交点黄经即得远地点
+		polcart(xxa[i][:], xxa[i][:])
+		coortrf2(xxa[i][:], xxa[i][:], -sinincl, cosincl)
+		cartpol(xxa[i][:], xxa[i][:])
+		xxa[i][0] += math.Atan2(sinnode, cosnode)
+		polcart(xxa[i][:], xxa[i][:])
+		// 交点在轨道椭圆上的距离：交点的真近点角和偏近点角
+		ny = mod2PI(ny - uu)
+		cosE = math.Cos(2 * math.Atan(math.Tan(ny/2)/math.Sqrt((1+ecce)/(1-ecce))))
+		r0 := sema * (1 - ecce*cosE)
+		r1 := math.Sqrt(squareSum3(xx[i][:]))
+		for j := 0; j <= 2; j++ {
+			xx[i][j] *= r0 / r1
+		}
+	}
+	// 保存位置和速度
+	ndnp := &swed.Nddat[SeiTrueNode]
+	ndap := &swed.Nddat[SeiOscuApog]
+	for i := 0; i <= 2; i++ {
+		ndap.X[i] = xxa[2][i]
+		ndnp.X[i] = xx[2][i]
+		if iflag&SeflgSpeed != 0 {
+			ndap.X[i+3] = (xxa[1][i] - xxa[0][i]) / speedIntv / 2
+			ndnp.X[i+3] = (xx[1][i] - xx[0][i]) / speedIntv / 2
+		} else {
+			ndap.X[i+3] = 0
+			ndnp.X[i+3] = 0
+		}
+	}
+	ndap.Teval, ndnp.Teval = tjd, tjd
+	ndap.Iephe, ndnp.Iephe = epheflag, epheflag
+	// 月球位置已经过岁差、章动和光行时改正，现在计算极坐标和赤道坐标
+	for _, ndp := range []*PlanData{ndnp, ndap} {
+		xr := ndp.Xreturn[:]
+		for i := range xr {
+			xr[i] = 0
+		}
+		// 黄道直角坐标和极坐标
+		copy(xr[6:12], ndp.X[:])
+		cartpolSp(xr[6:], xr)
+		// 赤道直角坐标
+		coortrf2(xr[6:], xr[18:], -oe.Seps, oe.Ceps)
+		if iflag&SeflgSpeed != 0 {
+			coortrf2(xr[9:], xr[21:], -oe.Seps, oe.Ceps)
+		}
+		if iflag&SeflgNonut == 0 {
+			coortrf2(xr[18:], xr[18:], -swed.Nut.Snut, swed.Nut.Cnut)
+			if iflag&SeflgSpeed != 0 {
+				coortrf2(xr[21:], xr[21:], -swed.Nut.Snut, swed.Nut.Cnut)
+			}
+		}
+		// 赤道极坐标
+		cartpolSp(xr[18:], xr[12:])
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & sefEphMask
+		if iflag&SeflgSidereal != 0 {
+			// 交点和拱点相对于当天黄道，需转换到t0
+			var x [6]Float64
+			copy(x[:], xr[18:24])
+			if err := sidNdReturn(ndp.Teval, iflag, xr, x[:]); err != nil {
+				return Err, err
+			}
+		} else if iflag&SeflgJ2000 != 0 {
+			ndEclipticToJ2000(tjd, iflag, xr)
+		}
+		radiansToDegrees(xr)
+	}
+	return Ok, nil
+}
+
+// ndEclipticToJ2000 将相对于当天黄道的交点或拱点转换到J2000
+func ndEclipticToJ2000(tjd Float64, iflag Int32, xr []Float64) {
+	var x [6]Float64
+	copy(x[:], xr[18:24])
+	precess(x[:], tjd, iflag, jToJ2000)
+	if iflag&SeflgSpeed != 0 {
+		precessSpeed(x[:], tjd, iflag, jToJ2000)
+	}
+	copy(xr[18:24], x[:])
+	cartpolSp(xr[18:], xr[12:])
+	coortrf2(xr[18:], xr[6:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+	if iflag&SeflgSpeed != 0 {
+		coortrf2(xr[21:], xr[9:], swed.Oec2000.Seps, swed.Oec2000.Ceps)
+	}
+	cartpolSp(xr[6:], xr)
+}
+
+// radiansToDegrees 将保存区中的黄道和赤道极坐标由弧度转换为度
+func radiansToDegrees(xr []Float64) {
+	for i := 0; i < 2; i++ {
+		xr[i] *= RadToDeg // 黄道
+		xr[i+3] *= RadToDeg
+		xr[i+12] *= RadToDeg // 赤道
+		xr[i+15] *= RadToDeg
+	}
+	xr[0] = degnorm(xr[0])
+	xr[12] = degnorm(xr[12])
+}
+
+// intpApsides 计算插值的月球远地点或近地点
+func intpApsides(tjd Float64, ipl int, iflag Int32) (int, error) {
+	const speedIntv = 0.1
+	var xpos [3][6]Float64
+	var xx [6]Float64
+	oe := &swed.Oec
+	nut := &swed.Nut
+	ndp := &swed.Nddat[ipl]
+	// 同一时刻已经计算过，除非需要速度而上次没有计算
+	flg1 := iflag &^ SeflgEquatorial &^ SeflgXyz
+	flg2 := ndp.Xflgs &^ SeflgEquatorial &^ SeflgXyz
+	speedf1 := ndp.Xflgs & SeflgSpeed
+	speedf2 := iflag & SeflgSpeed
+	if tjd == ndp.Teval && tjd != 0 && flg1 == flg2 && (speedf2 == 0 || speedf1 != 0) {
+		ndp.Xflgs = iflag
+		ndp.Iephe = iflag & SeflgMoseph
+		return Ok, nil
+	}
+	// 三个拱点
+	for i := 0; i < 3; i++ {
+		if iflag&SeflgSpeed == 0 && i != 1 {
+			continue
+		}
+		moshIntpApsides(tjd+Float64(i-1)*speedIntv, xpos[i][:], ipl)
+	}
+	// 带速度的拱点
+	copy(xx[:3], xpos[1][:3])
+	if iflag&SeflgSpeed != 0 {
+		xx[3] = difrad2n(xpos[2][0], xpos[0][0]) / speedIntv / 2.0
+		xx[4] = (xpos[2][1] - xpos[0][1]) / speedIntv / 2.0
+		xx[5] = (xpos[2][2] - xpos[0][2]) / speedIntv / 2.0
+	}
+	xr := ndp.Xreturn[:]
+	for i := range xr {
+		xr[i] = 0
+	}
+	// 黄道极坐标转直角坐标
+	polcartSp(xx[:], xx[:])
+	// 光行时
+	if iflag&SeflgTruepos == 0 {
+		dt := lightTime(xx[:])
+		for i := 1; i < 3; i++ {
+			xx[i] -= dt * xx[i+3]
+		}
+	}
+	copy(xr[6:12], xx[:])
+	// 赤道直角坐标
+	coortrf2(xr[6:], xr[18:], -oe.Seps, oe.Ceps)
+	if iflag&SeflgSpeed != 0 {
+		coortrf2(xr[9:], xr[21:], -oe.Seps, oe.Ceps)
+	}
+	ndp.Teval = tjd
+	ndp.Xflgs = iflag
+	ndp.Iephe = iflag & sefEphMask
+	if iflag&SeflgSidereal != 0 {
+		// 拱点相对于当天黄道，需转换到t0
+		var x [6]Float64
+		copy(x[:], xr[18:24])
+		if err := sidNdReturn(tjd, iflag, xr, x[:]); err != nil {
+			return Err, err
+		}
+	} else if iflag&SeflgJ2000 != 0 {
+		ndEclipticToJ2000(tjd, iflag, xr)
+	} else {
+		// 已含岁差，但未含章动
+		if iflag&SeflgNonut == 0 {
+			nutate(xr[18:], iflag, false)
+		}
+		// 赤道极坐标
+		cartpolSp(xr[18:], xr[12:])
+		// 黄道直角坐标
+		coortrf2(xr[18:], xr[6:], oe.Seps, oe.Ceps)
+		if iflag&SeflgSpeed != 0 {
+			coortrf2(xr[21:], xr[9:], oe.Seps, oe.Ceps)
+		}
+		if iflag&SeflgNonut == 0 {
+			coortrf2(xr[6:], xr[6:], nut.Snut, nut.Cnut)
+			if iflag&SeflgSpeed != 0 {
+				coortrf2(xr[9:], xr[9:], nut.Snut, nut.Cnut)
+			}
+		}
+		// 黄道极坐标
+		cartpolSp(xr[6:], xr)
+	}
+	radiansToDegrees(xr)
+	return Ok, nil
+}
+
+// planForOscElem 将月球位置转换为计算密切交点和远地点所需的当天黄道直角坐标
+// 岁差和章动只旋转速度矢量，不加入岁差和章动本身的速度
+func planForOscElem(iflag Int32, tjd Float64, xx []Float64) {
+	var x [6]Float64
+	var oectmp Epsilon
+	var nuttmp Nut
+	// ICRS到J2000
+	if iflag&SeflgIcrs == 0 && getDenum(SeiSun, iflag) >= 403 {
+		bias(xx, tjd, iflag, false)
+	}
+	// 岁差：J2000赤道到当天赤道
+	precess(xx, tjd, iflag, j2000ToJ)
+	precess(xx[3:], tjd, iflag, j2000ToJ)
+	oe := &swed.Oec
+	switch {
+	case tjd == swed.Oec.Teps:
+	case tjd == J2000:
+		oe = &swed.Oec2000
+	default:
+		calcEpsilon(tjd, iflag, &oectmp)
+		oe = &oectmp
+	}
+	// 章动
+	nutp := &nuttmp
+	if iflag&SeflgNonut == 0 {
+		switch {
+		case tjd == swed.Nut.Tnut:
+			nutp = &swed.Nut
+		case tjd == swed.Nutv.Tnut:
+			nutp = &swed.Nutv
+		default:
+			setNut(nutp, tjd, iflag, oe)
+		}
+		for i := 0; i <= 2; i++ {
+			x[i] = xx[0]*nutp.Matrix[0][i] + xx[1]*nutp.Matrix[1][i] + xx[2]*nutp.Matrix[2][i]
+			x[i+3] = xx[3]*nutp.Matrix[0][i] + xx[4]*nutp.Matrix[1][i] + xx[5]*nutp.Matrix[2][i]
+		}
+		copy(xx[:6], x[:])
+	}
+	// 转换到黄道
+	coortrf2(xx, xx, oe.Seps, oe.Ceps)
+	coortrf2(xx[3:], xx[3:], oe.Seps, oe.Ceps)
+	if iflag&SeflgNonut == 0 {
+		coortrf2(xx, xx, nutp.Snut, nutp.Cnut)
+		coortrf2(xx[3:], xx[3:], nutp.Snut, nutp.Cnut)
+	}
+}
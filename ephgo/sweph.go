@@ -20,6 +20,11 @@ func SetEphePath(path string) {
 	swed := GetSweData()
 	swed.EphePathIsSet = true
 	swed.Ephepath = path
+	swed.InitDtDone = false // 重新读取新路径中的ΔT和闰秒文件
+	initLeapsecDone = false
+	resetEop()
+	resetFixstars() // 重新读取新路径中的恒星文件
+	forceAppPosEtc() // ΔT和观测者位置可能随EOP数据改变
 	SetSweData(swed)
 }
 
@@ -44,15 +49,19 @@ func Close() {
 		}
 	}
 	
-	if swed.Fixfp != nil {
-		swed.Fixfp.Close()
-		swed.Fixfp = nil
-	}
+	resetFixstars()
 	
 	// 重置状态
 	swed.SwedIsInitialised = false
 	swed.EphePathIsSet = false
 	swed.JplFileIsOpen = false
+	swed.GeoposIsSet = false
+	swed.Topd = TopoData{}
+	swed.AstroModels = [SeiNmodels]Int32{}
+	swed.InitDtDone = false
+	initLeapsecDone = false
+	resetEop()
+	setTidAcc(SeTidalAutomatic)
 	
 	SetSweData(swed)
 	isInitialized = false
@@ -63,6 +72,9 @@ func Close() {
 // ipl: 天体编号
 // iflag: 计算标志
 // 返回：坐标数组xx[6]，错误信息
+// 日心（SeflgHelctr）和质心（SeflgBaryctr）位置适用于所有天体和星历，
+// 但月球交点和拱点只有地心位置，Moshier星历不支持质心位置
+// 速度只在指定SeflgSpeed或SeflgSpeed3时计算，否则返回零
 func Calc(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
 	var xx [6]Float64
 	
@@ -77,27 +89,180 @@ func Calc(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
 		return xx, fmt.Errorf("初始化失败: %v", err)
 	}
 	
-	// 根据天体类型分发计算
-	switch {
-	case ipl >= SeSun && ipl <= SePluto:
-		return calcMainPlanet(tjd, ipl, iflag)
-	case ipl == SeMeanNode || ipl == SeTrueNode:
-		return calcNode(tjd, ipl, iflag)
-	case ipl == SeMeanApog || ipl == SeOscuApog:
-		return calcApogee(tjd, ipl, iflag)
-	case ipl >= SeCeres && ipl <= SeVesta:
-		return calcAsteroid(tjd, ipl, iflag)
-	case ipl >= SeChiron && ipl <= SePholus:
-		return calcMinorPlanet(tjd, ipl, iflag)
-	default:
-		return xx, fmt.Errorf("不支持的天体编号: %d", ipl)
+	swed := GetSweData()
+	xx, _, err = calc(tjd, ipl, iflag)
+	SetSweData(swed)
+	return xx, err
+}
+
+// calc 计算天体位置，同时返回实际使用的标志
+// 计算结果保存在swed.Savedat中，同一时刻和标志的重复调用直接返回
+func calc(tjd Float64, ipl int, iflag Int32) ([6]Float64, Int32, error) {
+	var x [6]Float64
+	iflgsave := iflag
+	// 高精度速度优先于3点法速度
+	if iflag&SeflgSpeed3 != 0 && iflag&SeflgSpeed != 0 {
+		iflag &^= SeflgSpeed3
+	}
+	useSpeed3 := iflag&SeflgSpeed3 != 0
+	// 包含光行差的站心速度不够准确，这时由三个位置求速度
+	if iflag&SeflgSpeed != 0 && iflag&SeflgTopoctr != 0 && iflag&SeflgNoaberr == 0 {
+		useSpeed3 = true
+	}
+	// 冥王星作为小行星134340调用时按主要行星计算
+	if ipl == SeAstOffset+134340 {
+		ipl = SePluto
+	}
+	// 星历与上次调用不同时清除保存区，关闭星历文件
+	epheflag := Int32(SeflgSwieph)
+	if iflag&SeflgMoseph != 0 {
+		epheflag = SeflgMoseph
+	} else if iflag&SeflgJpleph != 0 {
+		epheflag = SeflgJpleph
+	}
+	if swed.LastEpheflag != epheflag {
+		freePlanets()
+		// 黄赤交角和章动与星历无关，不会重新打开文件
+		if ipl != SeEclNut {
+			if swed.JplFileIsOpen {
+				closeJplFileSwe()
+			}
+			for i := range swed.Fidat {
+				closeEphFile(i)
+				swed.Fidat[i] = FileData{}
+			}
+			swed.LastEpheflag = epheflag
+		}
+	}
+	// 直角坐标不使用弧度标志
+	if iflag&SeflgXyz != 0 && iflag&SeflgRadians != 0 {
+		iflag &^= SeflgRadians
+	}
+	// 保存区
+	sd := &swed.Savedat[SeNplanets]
+	if ipl >= SeSun && ipl < SeNplanets {
+		sd = &swed.Savedat[ipl]
+	}
+	// 同一时刻和标志的位置已经在保存区中，保存区包含所有坐标系
+	if sd.Tsave != tjd || tjd == 0 || sd.Ipl != ipl ||
+		sd.Iflgsave&^sefCoordsys != iflag&^sefCoordsys {
+		sd.Tsave = tjd
+		sd.Ipl = ipl
+		if !useSpeed3 {
+			// 一次计算得到高精度速度
+			retflag, err := swecalc(tjd, ipl, iflag, sd.Xsaves[:])
+			sd.Iflgsave = retflag
+			if err != nil {
+				return x, Err, err
+			}
+		} else {
+			// 由三次计算的位置求速度，较慢且精度较低，仅供测试
+			var x0, x2 [24]Float64
+			dt := Float64(planSpeedIntv)
+			switch ipl {
+			case SeMoon:
+				dt = moonSpeedIntv
+			case SeOscuApog, SeTrueNode:
+				// 这是Moshier星历的最佳间隔，避免要求JPL星历而实际使用Moshier星历时速度完全错误
+				dt = nodeCalcIntvMosh
+			}
+			if _, err := swecalc(tjd-dt, ipl, iflag, x0[:]); err != nil {
+				sd.Iflgsave = Err
+				return x, Err, err
+			}
+			if _, err := swecalc(tjd+dt, ipl, iflag, x2[:]); err != nil {
+				sd.Iflgsave = Err
+				return x, Err, err
+			}
+			retflag, err := swecalc(tjd, ipl, iflag, sd.Xsaves[:])
+			sd.Iflgsave = retflag
+			if err != nil {
+				return x, Err, err
+			}
+			denormalizePositions(x0[:], sd.Xsaves[:], x2[:])
+			calcSpeed(x0[:], sd.Xsaves[:], x2[:], dt)
+		}
+	}
+	// 赤道或黄道坐标，直角或极坐标
+	xs := sd.Xsaves[:]
+	if iflag&SeflgEquatorial != 0 {
+		xs = xs[12:]
+	}
+	if iflag&SeflgXyz != 0 {
+		xs = xs[6:]
+	}
+	// 黄赤交角和章动只有4个值
+	n := 3
+	if ipl == SeEclNut {
+		n = 4
+	}
+	copy(x[:n], xs[:n])
+	// 未要求速度时速度为零
+	if iflag&(SeflgSpeed3|SeflgSpeed) != 0 {
+		copy(x[3:], xs[3:6])
+	}
+	if iflag&SeflgRadians != 0 {
+		if ipl == SeEclNut {
+			for j := 0; j < 4; j++ {
+				x[j] *= DegToRad
+			}
+		} else {
+			x[0] *= DegToRad
+			x[1] *= DegToRad
+			if iflag&(SeflgSpeed3|SeflgSpeed) != 0 {
+				x[3] *= DegToRad
+				x[4] *= DegToRad
+			}
+		}
+	}
+	// 实际使用的标志，加上调用者的坐标系标志
+	retflag := sd.Iflgsave&^sefCoordsys | iflgsave&sefCoordsys
+	// 调用者未指定星历时不返回所选的星历
+	if iflgsave&sefEphMask == 0 {
+		retflag &^= SeflgSwieph
+	}
+	return x, retflag, nil
+}
+
+// denormalizePositions 使三个时刻的黄经和赤经连续，避免跨越0度
+func denormalizePositions(x0, x1, x2 []Float64) {
+	for i := 0; i <= 12; i += 12 {
+		if x1[i]-x0[i] < -180 {
+			x0[i] -= 360
+		}
+		if x1[i]-x0[i] > 180 {
+			x0[i] += 360
+		}
+		if x1[i]-x2[i] < -180 {
+			x2[i] -= 360
+		}
+		if x1[i]-x2[i] > 180 {
+			x2[i] += 360
+		}
+	}
+}
+
+// calcSpeed 由tjd-dt、tjd和tjd+dt三个时刻的位置求tjd时刻的速度
+func calcSpeed(x0, x1, x2 []Float64, dt Float64) {
+	for j := 0; j <= 18; j += 6 {
+		for i := 0; i < 3; i++ {
+			k := j + i
+			b := (x2[k] - x0[k]) / 2
+			a := (x2[k]+x0[k])/2 - x1[k]
+			x1[k+3] = (2*a + b) / dt
+		}
 	}
 }
 
 // CalcUT 计算天体位置（世界时UT）
 func CalcUT(tjdUt Float64, ipl int, iflag Int32) ([6]Float64, error) {
 	// 转换UT到ET
-	dt := Deltat(tjdUt)
+	// ΔT与所用星历的潮汐加速度一致
+	ephflag := iflag & sefEphMask
+	if ephflag == 0 {
+		ephflag = SeflgSwieph
+	}
+	dt := DeltatEx(tjdUt, ephflag)
 	tjdEt := tjdUt + dt/86400.0
 	
 	return Calc(tjdEt, ipl, iflag)
@@ -139,6 +304,10 @@ func GetPlanetName(ipl int) string {
 		return SeNameMeanApog
 	case SeOscuApog:
 		return SeNameOscuApog
+	case SeIntpApog:
+		return SeNameIntpApog
+	case SeIntpPerg:
+		return SeNameIntpPerg
 	case SeEarth:
 		return SeNameEarth
 	case SeCeres:
@@ -202,208 +371,6 @@ func getDefaultEphePath() string {
 	return "./"
 }
 
-// calcMainPlanet 计算主要行星位置
-func calcMainPlanet(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 优先使用JPL星历
-	if (iflag&SeflgJpleph) != 0 || (iflag&SeflgSwieph) == 0 {
-		if IsJplAvailable() {
-			return calcWithJPL(tjd, ipl, iflag)
-		}
-	}
-	
-	// 使用Swiss Ephemeris文件
-	if (iflag & SeflgSwieph) != 0 {
-		return calcWithSwissEph(tjd, ipl, iflag)
-	}
-	
-	// 使用Moshier星历
-	if (iflag & SeflgMoseph) != 0 {
-		return calcWithMoshier(tjd, ipl, iflag)
-	}
-	
-	return xx, fmt.Errorf("没有可用的星历数据")
-}
-
-// calcWithJPL 使用JPL星历计算
-func calcWithJPL(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 映射天体编号到JPL编号
-	jplTarget := mapToJPLTarget(ipl)
-	jplCenter := JSbary // 默认相对于太阳系质心
-	
-	if (iflag & SeflgHelctr) != 0 {
-		jplCenter = JSun // 日心坐标
-	}
-	
-	// 调用JPL计算
-	result, err := Pleph(tjd, jplTarget, jplCenter)
-	if err != nil {
-		return xx, err
-	}
-	
-	// 复制结果
-	copy(xx[:], result[:])
-	
-	// 应用坐标变换
-	return applyCoordinateTransforms(xx, iflag), nil
-}
-
-// calcWithSwissEph 使用Swiss Ephemeris文件计算
-func calcWithSwissEph(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 这里应该实现Swiss Ephemeris文件读取和计算
-	// 简化实现，实际需要读取.se1文件
-	
-	return xx, fmt.Errorf("Swiss Ephemeris文件计算尚未实现")
-}
-
-// calcWithMoshier 使用Moshier星历计算
-func calcWithMoshier(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 这里应该实现Moshier理论计算
-	// 简化实现
-	
-	return xx, fmt.Errorf("Moshier星历计算尚未实现")
-}
-
-// calcNode 计算月球交点
-func calcNode(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 简化的月球交点计算
-	// 实际实现需要更复杂的算法
-	
-	t := (tjd - J2000) / 36525.0
-	
-	if ipl == SeMeanNode {
-		// 平交点
-		lon := 125.0445479 - 1934.1362891*t + 0.0020754*t*t
-		lat := 0.0
-		rad := 1.0
-		
-		xx[0] = math.Mod(lon*DegToRad, TwoPi)
-		xx[1] = lat * DegToRad
-		xx[2] = rad
-		
-		// 速度（简化）
-		xx[3] = -1934.1362891 * DegToRad / 36525.0
-		xx[4] = 0.0
-		xx[5] = 0.0
-	} else {
-		// 真交点（需要更复杂的计算）
-		return calcNode(tjd, SeMeanNode, iflag)
-	}
-	
-	return applyCoordinateTransforms(xx, iflag), nil
-}
-
-// calcApogee 计算月球远地点
-func calcApogee(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 简化的远地点计算
-	t := (tjd - J2000) / 36525.0
-	
-	if ipl == SeMeanApog {
-		// 平远地点
-		lon := 83.3532465 + 4069.0137287*t - 0.0103200*t*t
-		lat := 0.0
-		rad := 1.0
-		
-		xx[0] = math.Mod(lon*DegToRad, TwoPi)
-		xx[1] = lat * DegToRad
-		xx[2] = rad
-		
-		// 速度
-		xx[3] = 4069.0137287 * DegToRad / 36525.0
-		xx[4] = 0.0
-		xx[5] = 0.0
-	} else {
-		// 振荡远地点
-		return calcApogee(tjd, SeMeanApog, iflag)
-	}
-	
-	return applyCoordinateTransforms(xx, iflag), nil
-}
-
-// calcAsteroid 计算小行星位置
-func calcAsteroid(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 这里应该读取小行星轨道要素文件并计算
-	// 简化实现
-	
-	return xx, fmt.Errorf("小行星计算尚未实现")
-}
-
-// calcMinorPlanet 计算小天体位置
-func calcMinorPlanet(tjd Float64, ipl int, iflag Int32) ([6]Float64, error) {
-	var xx [6]Float64
-	
-	// 这里应该计算Chiron、Pholus等小天体
-	// 简化实现
-	
-	return xx, fmt.Errorf("小天体计算尚未实现")
-}
-
-// mapToJPLTarget 映射天体编号到JPL编号
-func mapToJPLTarget(ipl int) int {
-	switch ipl {
-	case SeSun:
-		return JSun
-	case SeMoon:
-		return JMoon
-	case SeMercury:
-		return JMercury
-	case SeVenus:
-		return JVenus
-	case SeMars:
-		return JMars
-	case SeJupiter:
-		return JJupiter
-	case SeSaturn:
-		return JSaturn
-	case SeUranus:
-		return JUranus
-	case SeNeptune:
-		return JNeptune
-	case SePluto:
-		return JPluto
-	case SeEarth:
-		return JEarth
-	default:
-		return JSun // 默认
-	}
-}
-
-// applyCoordinateTransforms 应用坐标变换
-func applyCoordinateTransforms(xx [6]Float64, iflag Int32) [6]Float64 {
-	var result [6]Float64 = xx
-	
-	// 极坐标到笛卡尔坐标
-	if (iflag & SeflgXyz) != 0 {
-		result = polarToCartesian(result)
-	}
-	
-	// 角度单位转换
-	if (iflag & SeflgRadians) == 0 && (iflag & SeflgXyz) == 0 {
-		// 转换为度
-		result[0] *= RadToDeg
-		result[1] *= RadToDeg
-		result[3] *= RadToDeg
-		result[4] *= RadToDeg
-	}
-	
-	// 其他变换...
-	
-	return result
-}
-
 // polarToCartesian 极坐标转笛卡尔坐标
 func polarToCartesian(polar [6]Float64) [6]Float64 {
 	var cart [6]Float64
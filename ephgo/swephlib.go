@@ -0,0 +1,1440 @@
+package ephgo
+
+import "math"
+
+// 岁差和章动方向
+const (
+	jToJ2000 = 1  // 从历元J到J2000
+	j2000ToJ = -1 // 从J2000到历元J
+)
+
+// 计算速度所用的时间间隔（天）
+const (
+	moonSpeedIntv     = 0.00005   // 4.32秒
+	planSpeedIntv     = 0.0001    // 8.64秒
+	meanNodeSpeedIntv = 0.001     // 平交点和平远地点
+	nodeCalcIntv      = 0.0001    // 月球交点计算间隔
+	nodeCalcIntvMosh  = 0.1       // Moshier月球交点计算间隔
+	nutSpeedIntv      = 0.0001    // 章动速度间隔
+	deflSpeedIntv     = 0.0000005 // 光线偏折速度间隔
+)
+
+// STR 每角秒的弧度数
+const STR = 4.8481368110953599359e-6
+
+// sunRadius 太阳视半径（弧度）
+const sunRadius = 959.63 / 3600 * DegToRad
+
+// sefEphMask 星历类型标志的掩码
+const sefEphMask = SeflgJpleph | SeflgSwieph | SeflgMoseph
+
+// sefCoordsys 坐标系标志的掩码，保存区中包含所有坐标系的结果
+const sefCoordsys = SeflgEquatorial | SeflgXyz | SeflgRadians
+
+// degnorm 将角度归一化到[0, 360)
+func degnorm(x Float64) Float64 {
+	y := math.Mod(x, 360.0)
+	if math.Abs(y) < 1e-13 {
+		y = 0
+	}
+	if y < 0.0 {
+		y += 360.0
+	}
+	return y
+}
+
+// radnorm 将弧度归一化到[0, 2π)
+func radnorm(x Float64) Float64 {
+	y := math.Mod(x, TwoPi)
+	if math.Abs(y) < 1e-13 {
+		y = 0
+	}
+	if y < 0.0 {
+		y += TwoPi
+	}
+	return y
+}
+
+// difdeg2n 计算p1-p2的角度差，归一化到[-180, 180)
+func difdeg2n(p1, p2 Float64) Float64 {
+	dif := degnorm(p1 - p2)
+	if dif >= 180.0 {
+		return dif - 360.0
+	}
+	return dif
+}
+
+// mod2PI 对2π取模
+func mod2PI(x Float64) Float64 {
+	y := math.Mod(x, TwoPi)
+	if y < 0.0 {
+		y += TwoPi
+	}
+	return y
+}
+
+// difrad2n 计算p1-p2的弧度差，归一化到[-π, π)
+func difrad2n(p1, p2 Float64) Float64 {
+	dif := radnorm(p1 - p2)
+	if dif >= Pi {
+		return dif - TwoPi
+	}
+	return dif
+}
+
+// crossProd 向量叉积
+func crossProd(a, b []Float64, x []Float64) {
+	x0 := a[1]*b[2] - a[2]*b[1]
+	x1 := a[2]*b[0] - a[0]*b[2]
+	x2 := a[0]*b[1] - a[1]*b[0]
+	x[0], x[1], x[2] = x0, x1, x2
+}
+
+// echeb 计算切比雪夫级数 coef[0..ncf-1] 在 x∈[-1,1] 处的值
+func echeb(x Float64, coef []Float64, ncf int) Float64 {
+	x2 := x * 2.
+	br := 0.
+	brp2 := 0.
+	brpp := 0.
+	for j := ncf - 1; j >= 0; j-- {
+		brp2 = brpp
+		brpp = br
+		br = x2*brpp - brp2 + coef[j]
+	}
+	return (br - brp2) * .5
+}
+
+// edcheb 计算切比雪夫级数的导数
+func edcheb(x Float64, coef []Float64, ncf int) Float64 {
+	var bjpl, xjpl, bf, bj, xjp2, bjp2 Float64
+	x2 := x * 2.
+	for j := ncf - 1; j >= 1; j-- {
+		dj := Float64(j + j)
+		xj := coef[j]*dj + xjp2
+		bj = x2*bjpl - bjp2 + xj
+		bf = bjp2
+		bjp2 = bjpl
+		bjpl = bj
+		xjp2 = xjpl
+		xjpl = xj
+	}
+	return (bj - bf) * .5
+}
+
+// coortrf 黄道与赤道直角坐标的相互转换
+// 黄道转赤道时eps取负值，赤道转黄道时取正值
+func coortrf(xpo, xpn []Float64, eps Float64) {
+	coortrf2(xpo, xpn, math.Sin(eps), math.Cos(eps))
+}
+
+// coortrf2 同coortrf，但直接给出sin(eps)和cos(eps)
+func coortrf2(xpo, xpn []Float64, sineps, coseps Float64) {
+	x0 := xpo[0]
+	x1 := xpo[1]*coseps + xpo[2]*sineps
+	x2 := -xpo[1]*sineps + xpo[2]*coseps
+	xpn[0], xpn[1], xpn[2] = x0, x1, x2
+}
+
+// cartpol 直角坐标转极坐标（弧度），x与l可以相同
+func cartpol(x, l []Float64) {
+	if x[0] == 0 && x[1] == 0 && x[2] == 0 {
+		l[0], l[1], l[2] = 0, 0, 0
+		return
+	}
+	var ll [3]Float64
+	rxy := x[0]*x[0] + x[1]*x[1]
+	ll[2] = math.Sqrt(rxy + x[2]*x[2])
+	rxy = math.Sqrt(rxy)
+	ll[0] = math.Atan2(x[1], x[0])
+	if ll[0] < 0.0 {
+		ll[0] += TwoPi
+	}
+	if rxy == 0 {
+		if x[2] >= 0 {
+			ll[1] = Pi / 2
+		} else {
+			ll[1] = -(Pi / 2)
+		}
+	} else {
+		ll[1] = math.Atan(x[2] / rxy)
+	}
+	l[0], l[1], l[2] = ll[0], ll[1], ll[2]
+}
+
+// polcart 极坐标转直角坐标，l与x可以相同
+func polcart(l, x []Float64) {
+	cosl1 := math.Cos(l[1])
+	x0 := l[2] * cosl1 * math.Cos(l[0])
+	x1 := l[2] * cosl1 * math.Sin(l[0])
+	x2 := l[2] * math.Sin(l[1])
+	x[0], x[1], x[2] = x0, x1, x2
+}
+
+// cartpolSp 位置和速度从直角坐标转极坐标
+// 位置为零时返回运动方向
+func cartpolSp(x, l []Float64) {
+	var ll [6]Float64
+	// 位置为零
+	if x[0] == 0 && x[1] == 0 && x[2] == 0 {
+		ll[5] = math.Sqrt(x[3]*x[3] + x[4]*x[4] + x[5]*x[5])
+		cartpol(x[3:6], ll[:3])
+		ll[2] = 0
+		copy(l[:6], ll[:])
+		return
+	}
+	// 速度为零
+	if x[3] == 0 && x[4] == 0 && x[5] == 0 {
+		l[3], l[4], l[5] = 0, 0, 0
+		cartpol(x, l)
+		return
+	}
+	// 位置
+	rxy := x[0]*x[0] + x[1]*x[1]
+	ll[2] = math.Sqrt(rxy + x[2]*x[2])
+	rxy = math.Sqrt(rxy)
+	ll[0] = math.Atan2(x[1], x[0])
+	if ll[0] < 0.0 {
+		ll[0] += TwoPi
+	}
+	ll[1] = math.Atan(x[2] / rxy)
+	// 速度：先绕z轴旋转经度，再绕新的y轴旋转纬度
+	coslon := x[0] / rxy
+	sinlon := x[1] / rxy
+	coslat := rxy / ll[2]
+	sinlat := x[2] / ll[2]
+	xx3 := x[3]*coslon + x[4]*sinlon
+	xx4 := -x[3]*sinlon + x[4]*coslon
+	l3 := xx4 / rxy
+	xx4 = -sinlat*xx3 + coslat*x[5]
+	xx5 := coslat*xx3 + sinlat*x[5]
+	l[3] = l3
+	l[4] = xx4 / ll[2]
+	l[5] = xx5
+	l[0], l[1], l[2] = ll[0], ll[1], ll[2]
+}
+
+// polcartSp 位置和速度从极坐标转直角坐标
+func polcartSp(l, x []Float64) {
+	// 速度为零
+	if l[3] == 0 && l[4] == 0 && l[5] == 0 {
+		x[3], x[4], x[5] = 0, 0, 0
+		polcart(l, x)
+		return
+	}
+	coslon := math.Cos(l[0])
+	sinlon := math.Sin(l[0])
+	coslat := math.Cos(l[1])
+	sinlat := math.Sin(l[1])
+	xx0 := l[2] * coslat * coslon
+	xx1 := l[2] * coslat * sinlon
+	xx2 := l[2] * sinlat
+	rxyz := l[2]
+	rxy := math.Sqrt(xx0*xx0 + xx1*xx1)
+	xx5 := l[5]
+	xx4 := l[4] * rxyz
+	x5 := sinlat*xx5 + coslat*xx4
+	xx3 := coslat*xx5 - sinlat*xx4
+	xx4 = l[3] * rxy
+	x[3] = coslon*xx3 - sinlon*xx4
+	x[4] = sinlon*xx3 + coslon*xx4
+	x[5] = x5
+	x[0], x[1], x[2] = xx0, xx1, xx2
+}
+
+// squareSum3 切片前三个分量的平方和
+func squareSum3(x []Float64) Float64 {
+	return x[0]*x[0] + x[1]*x[1] + x[2]*x[2]
+}
+
+// dotProd3 切片前三个分量的点积
+func dotProd3(x, y []Float64) Float64 {
+	return x[0]*y[0] + x[1]*y[1] + x[2]*y[2]
+}
+
+// dotProdUnit 切片前三个分量的单位向量点积，即夹角的余弦
+func dotProdUnit(x, y []Float64) Float64 {
+	dop := dotProd3(x, y)
+	dop /= math.Sqrt(squareSum3(x))
+	dop /= math.Sqrt(squareSum3(y))
+	if dop > 1 {
+		dop = 1
+	}
+	if dop < -1 {
+		dop = -1
+	}
+	return dop
+}
+
+// cotrans 极坐标xpo（度）绕x轴旋转eps（度），结果写入xpn，距离xpo[2]不变
+// 黄道坐标转换为赤道坐标时eps为负，反之为正
+func cotrans(xpo, xpn []Float64, eps Float64) {
+	var x [6]Float64
+	x[0] = xpo[0] * DegToRad
+	x[1] = xpo[1] * DegToRad
+	x[2] = 1
+	polcart(x[:], x[:])
+	coortrf(x[:], x[:], eps*DegToRad)
+	cartpol(x[:], x[:])
+	xpn[0] = x[0] * RadToDeg
+	xpn[1] = x[1] * RadToDeg
+	xpn[2] = xpo[2]
+}
+
+// Vondrák、Capitaine和Wallace（2011）长期岁差模型
+const (
+	as2r = DegToRad / 3600.0
+	eps0 = 84381.406 * as2r
+)
+
+// 黄赤交角（pre_peps）的多项式项和周期项
+var pepol = [4][2]Float64{
+	{+8134.017132, +84028.206305},
+	{+5043.0520035, +0.3624445},
+	{-0.00710733, -0.00004039},
+	{+0.000000271, -0.000000110},
+}
+
+var peper = [5][10]Float64{
+	{+409.90, +396.15, +537.22, +402.90, +417.15, +288.92, +4043.00, +306.00, +277.00, +203.00},
+	{-6908.287473, -3198.706291, +1453.674527, -857.748557, +1173.231614, -156.981465, +371.836550, -216.619040, +193.691479, +11.891524},
+	{+753.872780, -247.805823, +379.471484, -53.880558, -90.109153, -353.600190, -63.115353, -28.248187, +17.703387, +38.911307},
+	{-2845.175469, +449.844989, -1255.915323, +886.736783, +418.887514, +997.912441, -240.979710, +76.541307, -36.788069, -170.964086},
+	{-1704.720302, -862.308358, +447.832178, -889.571909, +190.402846, -56.564991, -296.222622, -75.859952, +67.473503, +3.014055},
+}
+
+// 黄道岁差（pre_pecl）的多项式项和周期项
+var pqpol = [4][2]Float64{
+	{+5851.607687, -1600.886300},
+	{-0.1189000, +1.1689818},
+	{-0.00028913, -0.00000020},
+	{+0.000000101, -0.000000437},
+}
+
+var pqper = [5][8]Float64{
+	{708.15, 2309, 1620, 492.2, 1183, 622, 882, 547},
+	{-5486.751211, -17.127623, -617.517403, 413.44294, 78.614193, -180.732815, -87.676083, 46.140315},
+	// 按A&A 541, C1 (2012) 更正了原文的排印错误
+	{-684.66156, 2446.28388, 399.671049, -356.652376, -186.387003, -316.80007, 198.296701, 101.135679},
+	{667.66673, -2354.886252, -428.152441, 376.202861, 184.778874, 335.321713, -185.138669, -120.97283},
+	{-5523.863691, -549.74745, -310.998056, 421.535876, -36.776172, -145.278396, -34.74445, 22.885731},
+}
+
+// 赤道岁差（pre_pequ）的多项式项和周期项
+var xypol = [4][2]Float64{
+	{+5453.282155, -73750.930350},
+	{+0.4252841, -0.7675452},
+	{-0.00037173, -0.00018725},
+	{-0.000000152, +0.000000231},
+}
+
+var xyper = [5][14]Float64{
+	{256.75, 708.15, 274.2, 241.45, 2309, 492.2, 396.1, 288.9, 231.1, 1610, 620, 157.87, 220.3, 1200},
+	{-819.940624, -8444.676815, 2600.009459, 2755.17563, -167.659835, 871.855056, 44.769698, -512.313065, -819.415595, -538.071099, -189.793622, -402.922932, 179.516345, -9.814756},
+	{75004.344875, 624.033993, 1251.136893, -1102.212834, -2660.66498, 699.291817, 153.16722, -950.865637, 499.754645, -145.18821, 558.116553, -23.923029, -165.405086, 9.344131},
+	{81491.287984, 787.163481, 1251.296102, -1257.950837, -2966.79973, 639.744522, 131.600209, -445.040117, 584.522874, -89.756563, 524.42963, -13.549067, -210.157124, -44.919798},
+	{1558.515853, 7774.939698, -2219.534038, -2523.969396, 247.850422, -846.485643, -1393.124055, 368.526116, 749.045012, 444.704518, 235.934465, 374.049623, -171.33018, -22.899655},
+}
+
+// ldpPeps 计算历元tjd的一般岁差dpre和黄赤交角deps（弧度）
+func ldpPeps(tjd Float64) (dpre, deps Float64) {
+	t := (tjd - J2000) / 36525.0
+	var p, q Float64
+	// 周期项
+	for i := 0; i < len(peper[0]); i++ {
+		w := TwoPi * t
+		a := w / peper[0][i]
+		s := math.Sin(a)
+		c := math.Cos(a)
+		p += c*peper[1][i] + s*peper[3][i]
+		q += c*peper[2][i] + s*peper[4][i]
+	}
+	// 多项式项
+	w := 1.0
+	for i := 0; i < len(pepol); i++ {
+		p += pepol[i][0] * w
+		q += pepol[i][1] * w
+		w *= t
+	}
+	return p * as2r, q * as2r
+}
+
+// prePecl 黄道极的岁差
+func prePecl(tjd Float64) [3]Float64 {
+	t := (tjd - J2000) / 36525.0
+	var p, q Float64
+	for i := 0; i < len(pqper[0]); i++ {
+		w := TwoPi * t
+		a := w / pqper[0][i]
+		s := math.Sin(a)
+		c := math.Cos(a)
+		p += c*pqper[1][i] + s*pqper[3][i]
+		q += c*pqper[2][i] + s*pqper[4][i]
+	}
+	w := 1.0
+	for i := 0; i < len(pqpol); i++ {
+		p += pqpol[i][0] * w
+		q += pqpol[i][1] * w
+		w *= t
+	}
+	p *= as2r
+	q *= as2r
+	z := 1 - p*p - q*q
+	if z < 0 {
+		z = 0
+	} else {
+		z = math.Sqrt(z)
+	}
+	s := math.Sin(eps0)
+	c := math.Cos(eps0)
+	return [3]Float64{p, -q*c - z*s, -q*s + z*c}
+}
+
+// prePequ 赤道极的岁差
+func prePequ(tjd Float64) [3]Float64 {
+	t := (tjd - J2000) / 36525.0
+	var x, y Float64
+	for i := 0; i < len(xyper[0]); i++ {
+		w := TwoPi * t
+		a := w / xyper[0][i]
+		s := math.Sin(a)
+		c := math.Cos(a)
+		x += c*xyper[1][i] + s*xyper[3][i]
+		y += c*xyper[2][i] + s*xyper[4][i]
+	}
+	w := 1.0
+	for i := 0; i < len(xypol); i++ {
+		x += xypol[i][0] * w
+		y += xypol[i][1] * w
+		w *= t
+	}
+	x *= as2r
+	y *= as2r
+	veq := [3]Float64{x, y, 0}
+	w = x*x + y*y
+	if w < 1 {
+		veq[2] = math.Sqrt(1 - w)
+	}
+	return veq
+}
+
+// prePmat 岁差矩阵
+func prePmat(tjd Float64) [9]Float64 {
+	peqr := prePequ(tjd)
+	pecl := prePecl(tjd)
+	var v, eqx [3]Float64
+	// 春分点方向
+	crossProd(peqr[:], pecl[:], v[:])
+	w := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	eqx[0] = v[0] / w
+	eqx[1] = v[1] / w
+	eqx[2] = v[2] / w
+	crossProd(peqr[:], eqx[:], v[:])
+	return [9]Float64{eqx[0], eqx[1], eqx[2], v[0], v[1], v[2], peqr[0], peqr[1], peqr[2]}
+}
+
+// Owen（1990）岁差模型的切比雪夫系数，五个时段各以Tc=-160, -80, 0, 80, 160世纪为中心
+var owenEps0Coef = [5][10]Float64{
+	{23.699391439256386, 5.2330816033981775e-1, -5.6259493384864815e-2, -8.2033318431602032e-3, 6.6774163554156385e-4, 2.4931584012812606e-5, -3.1313623302407878e-6, 2.0343814827951515e-7, 2.9182026615852936e-8, -4.1118760893281951e-9},
+	{24.124759551704588, -1.2094875596566286e-1, -8.3914869653015218e-2, 3.5357075322387405e-3, 6.4557467824807032e-4, -2.5092064378707704e-5, -1.7631607274450848e-6, 1.3363622791424094e-7, 1.5577817511054047e-8, -2.4613907093017122e-9},
+	{23.439103144206208, -4.9386077073143590e-1, -2.3965445283267805e-4, 8.6637485629656489e-3, -5.2828151901367600e-5, -4.3951004595359217e-5, -1.1058785949914705e-6, 6.2431490022621172e-8, 3.4725376218710764e-8, 1.3658853127005757e-9},
+	{22.724671295125046, -1.6041813558650337e-1, 7.0646783888132504e-2, 1.4967806745062837e-3, -6.6857270989190734e-4, 5.7578378071604775e-6, 3.3738508454638728e-6, -2.2917813537654764e-7, -2.1019907929218137e-8, 4.3139832091694682e-9},
+	{22.914636050333696, 3.2123508304962416e-1, 3.6633220173792710e-2, -5.9228324767696043e-3, -1.882379107379328e-4, 3.2274552870236244e-5, 4.9052463646336507e-7, -5.9064298731578425e-8, -2.0485712675098837e-8, -6.2163304813908160e-10},
+}
+
+var owenPsiaCoef = [5][10]Float64{
+	{-218.57864954903122, 51.752257487741612, 1.3304715765661958e-1, 9.2048123521890745e-2, -6.0877528127241278e-3, -7.0013893644531700e-5, -4.9217728385458495e-5, -1.8578234189053723e-6, 7.4396426162029877e-7, -5.9157528981843864e-9},
+	{-111.94350527506128, 55.175558131675861, 4.7366115762797613e-1, -4.7701750975398538e-2, -9.2445765329325809e-3, 7.0962838707454917e-4, 1.5140455277814658e-4, -7.7813159018954928e-7, -2.4729402281953378e-6, -1.0898887008726418e-7},
+	{-2.041452011529441e-1, 55.969995858494106, -1.9295093699770936e-1, -5.6819574830421158e-3, 1.1073687302518981e-2, -9.0868489896815619e-5, -1.1999773777895820e-4, 9.9748697306154409e-6, 5.7911493603430550e-7, -2.3647526839778175e-7},
+	{111.61366860604471, 56.404525305162447, 4.4403302410703782e-1, 7.1490030578883907e-2, -4.9184559079790816e-3, -1.3912698949042046e-3, -6.8490613661884005e-5, 1.2394328562905297e-6, 1.7719847841480384e-6, 2.4889095220628068e-7},
+	{228.40683531269390, 60.056143904919826, 2.9583200718478960e-2, -1.5710838319490748e-1, -7.0017356811600801e-3, 3.3009615142224537e-3, 2.0318123852537664e-4, -6.5840216067828310e-5, -5.9077673352976155e-6, 1.3983942185303064e-6},
+}
+
+var owenOmaCoef = [5][10]Float64{
+	{25.541291140949806, 2.377889511272162e-1, -3.7337334723142133e-1, 2.4579295485161534e-2, 4.3840999514263623e-3, -3.1126873333599556e-4, -9.8443045771748915e-6, -7.9403103080496923e-7, 1.0840116743893556e-9, 9.2865105216887919e-9},
+	{24.429357654237926, -9.5205745947740161e-1, 8.6738296270534816e-2, 3.0061543426062955e-2, -4.1532480523019988e-3, -3.7920928393860939e-4, 3.5117012399609737e-5, 4.6811877283079217e-6, -8.1836046585546861e-8, -6.1803706664211173e-8},
+	{23.450465062489337, -9.7259278279739817e-2, 1.1082286925130981e-2, -3.1469883339372219e-2, -1.0041906996819648e-4, 5.6455168475133958e-4, -8.4403910211030209e-6, -3.8269157371098435e-6, 3.1422585261198437e-7, 9.3481729116773404e-9},
+	{22.581778052947806, -8.7069701538602037e-1, -9.8140710050197307e-2, 2.6025931340678079e-2, 4.8165322168786755e-3, -1.906558772193363e-4, -4.6838759635421777e-5, -1.6608525315998471e-6, -3.2347811293516124e-8, 2.8104728109642000e-9},
+	{21.518861835737142, 2.0494789509441385e-1, 3.5193604846503161e-1, 1.5305977982348925e-2, -7.5015367726336455e-3, -4.0322553186065610e-4, 1.0655320434844041e-4, 7.1792339586935752e-6, -1.603874697543020e-6, -1.613563462813512e-7},
+}
+
+var owenChiaCoef = [5][10]Float64{
+	{8.2378850337329404e-1, -3.7443109739678667, 4.0143936898854026e-1, 8.1822830214590811e-2, -8.5978790792656293e-3, -2.8350488448426132e-5, -4.2474671728156727e-5, -1.6214840884656678e-6, 7.8560442001953050e-7, -1.032016641696707e-8},
+	{-2.1726062070318606, 7.8470515033132925e-1, 4.4044931004195718e-1, -8.0671247169971653e-2, -8.9672662444325007e-3, 9.2248978383109719e-4, 1.5143472266372874e-4, -1.6387009056475679e-6, -2.4405558979328144e-6, -1.0148113464009015e-7},
+	{-4.8518673570735556e-1, 1.0016737299946743e-1, -4.7074888613099918e-1, -5.8604054305076092e-3, 1.4300208240553435e-2, -6.7127991650300028e-5, -1.3703764889645475e-4, 9.0505213684444634e-6, 6.0368690647808607e-7, -2.2135404747652171e-7},
+	{-2.0950740076326087, -9.4447359463206877e-1, 4.0940512860493755e-1, 1.0261699700263508e-1, -5.3133241571955160e-3, -1.6634631550720911e-3, -5.9477519536647907e-5, 2.9651387319208926e-6, 1.6434499452070584e-6, 2.3720647656961084e-7},
+	{6.3315163285678715e-1, 3.5241082918420464, 2.1223076605364606e-1, -1.5648122502767368e-1, -9.1964075390801980e-3, 3.3896161239812411e-3, 2.1485178626085787e-4, -6.6261759864793735e-5, -5.9257969712852667e-6, 1.3918759086160525e-6},
+}
+
+// owenChebyshev 返回tjd所在时段的系数下标和切比雪夫多项式k[0..9]
+func owenChebyshev(tjd Float64) (int, [10]Float64) {
+	t0s := [5]Float64{-3392455.5, -470455.5, 2451544.5, 5373544.5, 8295544.5}
+	t0 := t0s[0]
+	icof := 0
+	for i := 1; i < 5; i++ {
+		if tjd >= (t0s[i-1]+t0s[i])/2 {
+			t0 = t0s[i]
+			icof++
+		}
+	}
+	var tau, k [10]Float64
+	tau[1] = (tjd - t0) / 36525.0 / 40.0
+	for i := 2; i <= 9; i++ {
+		tau[i] = tau[1] * tau[i-1]
+	}
+	k[0] = 1
+	k[1] = tau[1]
+	k[2] = 2*tau[2] - 1
+	k[3] = 4*tau[3] - 3*tau[1]
+	k[4] = 8*tau[4] - 8*tau[2] + 1
+	k[5] = 16*tau[5] - 20*tau[3] + 5*tau[1]
+	k[6] = 32*tau[6] - 48*tau[4] + 18*tau[2] - 1
+	k[7] = 64*tau[7] - 112*tau[5] + 56*tau[3] - 7*tau[1]
+	k[8] = 128*tau[8] - 256*tau[6] + 160*tau[4] - 32*tau[2] + 1
+	k[9] = 256*tau[9] - 576*tau[7] + 432*tau[5] - 120*tau[3] + 9*tau[1]
+	return icof, k
+}
+
+// owenPreMatrix Owen 1990的岁差矩阵
+func owenPreMatrix(tjd Float64, iflag Int32) [9]Float64 {
+	icof, k := owenChebyshev(tjd)
+	var psia, oma, chia Float64
+	for i := 0; i < 10; i++ {
+		psia += k[i] * owenPsiaCoef[icof][i]
+		oma += k[i] * owenOmaCoef[icof][i]
+		chia += k[i] * owenChiaCoef[icof][i]
+	}
+	// 与JPL Horizons相比黄经有约-0.000019度的几乎恒定的偏差，在此改正
+	if iflag&(SeflgJplhor|SeflgJplhorApprox) != 0 {
+		psia += -0.000018560
+	}
+	eps0 := 84381.448 / 3600.0 * DegToRad
+	psia *= DegToRad
+	chia *= DegToRad
+	oma *= DegToRad
+	coseps0, sineps0 := math.Cos(eps0), math.Sin(eps0)
+	coschia, sinchia := math.Cos(chia), math.Sin(chia)
+	cospsia, sinpsia := math.Cos(psia), math.Sin(psia)
+	cosoma, sinoma := math.Cos(oma), math.Sin(oma)
+	var rp [9]Float64
+	rp[0] = coschia*cospsia + sinchia*cosoma*sinpsia
+	rp[1] = (-coschia*sinpsia+sinchia*cosoma*cospsia)*coseps0 + sinchia*sinoma*sineps0
+	rp[2] = (-coschia*sinpsia+sinchia*cosoma*cospsia)*sineps0 - sinchia*sinoma*coseps0
+	rp[3] = -sinchia*cospsia + coschia*cosoma*sinpsia
+	rp[4] = (sinchia*sinpsia+coschia*cosoma*cospsia)*coseps0 + coschia*sinoma*sineps0
+	rp[5] = (sinchia*sinpsia+coschia*cosoma*cospsia)*sineps0 - coschia*sinoma*coseps0
+	rp[6] = sinoma * sinpsia
+	rp[7] = sinoma*cospsia*coseps0 - cosoma*sineps0
+	rp[8] = sinoma*cospsia*sineps0 + cosoma*coseps0
+	return rp
+}
+
+// epsilnOwen1986 Owen的平黄赤交角（度）
+func epsilnOwen1986(tjd Float64) Float64 {
+	icof, k := owenChebyshev(tjd)
+	var eps Float64
+	for i := 0; i < 10; i++ {
+		eps += k[i] * owenEps0Coef[icof][i]
+	}
+	return eps
+}
+
+// JPL Horizons模式的常数
+const (
+	horizonsTjd0DpsiDepsIau1980 = 2437684.5       // dpsi和deps改正值的起始日期（1962年1月20日）
+	dpsiIau1980Tjd0             = 64.284 / 1000.0 // 1962年1月20日的dpsi改正值（角秒）
+	depsIau1980Tjd0             = 6.151 / 1000.0  // 1962年1月20日的deps改正值（角秒）
+	horizonsTjdBeg              = 2378131.5       // 1799年1月1日
+	horizonsTjdEnd              = 2525323.5       // 2202年1月1日
+	dcorJplTjd0                 = 2437846.5       // 逐年改正值表的起始日期
+)
+
+// dcorEpsJpl 近似JPL Horizons模式中黄赤交角的逐年改正值（毫角秒）
+var dcorEpsJpl = []Float64{
+	36.726, 36.627, 36.595, 36.578, 36.640, 36.659, 36.731, 36.765,
+	36.662, 36.555, 36.335, 36.321, 36.354, 36.227, 36.289, 36.348, 36.257, 36.163,
+	35.979, 35.896, 35.842, 35.825, 35.912, 35.950, 36.093, 36.191, 36.009, 35.943,
+	35.875, 35.771, 35.788, 35.753, 35.822, 35.866, 35.771, 35.732, 35.543, 35.498,
+	35.449, 35.409, 35.497, 35.556, 35.672, 35.760, 35.596, 35.565, 35.510, 35.394,
+	35.385, 35.375, 35.415,
+}
+
+// dcorRaJpl 近似JPL Horizons模式中赤经的逐年改正值（毫角秒）
+var dcorRaJpl = []Float64{
+	-51.257, -51.103, -51.065, -51.503, -51.224, -50.796, -51.161, -51.181,
+	-50.932, -51.064, -51.182, -51.386, -51.416, -51.428, -51.586, -51.766, -52.038, -52.370,
+	-52.553, -52.397, -52.340, -52.676, -52.348, -51.964, -52.444, -52.364, -51.988, -52.212,
+	-52.370, -52.523, -52.541, -52.496, -52.590, -52.629, -52.788, -53.014, -53.053, -52.902,
+	-52.850, -53.087, -52.635, -52.185, -52.588, -52.292, -51.796, -51.961, -52.055, -52.134,
+	-52.165, -52.141, -52.255,
+}
+
+// jplhoraModel 返回近似JPL Horizons模式所用的方法
+func jplhoraModel() Int32 {
+	jplhoraModel := swed.AstroModels[SeModelJplhoraMode]
+	if jplhoraModel == 0 {
+		jplhoraModel = SemodJplhoraDefault
+	}
+	return jplhoraModel
+}
+
+// isJplhor 是否像JPL Horizons那样使用IAU 1976岁差和IAU 1980章动
+// 近似模式SemodJplhora3在1962年以前也使用这种方法
+func isJplhor(J Float64, iflag Int32) bool {
+	if iflag&SeflgJplhor != 0 {
+		return true
+	}
+	return iflag&SeflgJplhorApprox != 0 && jplhoraModel() == SemodJplhora3 && J <= horizonsTjd0DpsiDepsIau1980
+}
+
+// dcorJpl 从逐年改正值表中取历元tjd的改正值（毫角秒），表外使用表首或表尾的值
+// 内插方式与原版完全相同，以便得到相同的结果
+func dcorJpl(tab []Float64, tjd Float64) Float64 {
+	n := len(tab)
+	t := (tjd - dcorJplTjd0) / 365.25
+	switch {
+	case t < 0:
+		return tab[0]
+	case t >= Float64(n-1):
+		return tab[n-1]
+	}
+	t0 := int(t)
+	return (t-Float64(t0))*(tab[t0]-tab[t0+1]) + tab[t0]
+}
+
+// epsiln 计算历元J的平黄赤交角（弧度），模型与岁差模型一致
+// IAU 1976: Lieske et al., A&A 58, 1-16 (1977)
+// Laskar: A&A 157, 59070 (1986)
+// Bretagnon 2003: A&A 400, 785
+// JPL Horizons模式在1799至2202年间使用IAU 1976，其余时间使用Owen 1990
+func epsiln(J Float64, iflag Int32) Float64 {
+	var eps Float64
+	precModel := swed.AstroModels[SeModelPrecLongterm]
+	precModelShort := swed.AstroModels[SeModelPrecShortterm]
+	if precModel == 0 {
+		precModel = SemodPrecDefault
+	}
+	if precModelShort == 0 {
+		precModelShort = SemodPrecDefaultShort
+	}
+	T := (J - J2000) / 36525.0
+	switch {
+	case isJplhor(J, iflag) && J > horizonsTjdBeg && J < horizonsTjdEnd,
+		iflag&SeflgJplhorApprox != 0 && jplhoraModel() == SemodJplhora2:
+		eps = (((1.813e-3*T-5.9e-4)*T-46.8150)*T + 84381.448) * DegToRad / 3600
+	case isJplhor(J, iflag):
+		eps = epsilnOwen1986(J) * DegToRad
+	case precModelShort == SemodPrecIau1976 && math.Abs(T) <= precIau1976Cties,
+		precModel == SemodPrecIau1976:
+		eps = (((1.813e-3*T-5.9e-4)*T-46.8150)*T + 84381.448) * DegToRad / 3600
+	case precModelShort == SemodPrecIau2000 && math.Abs(T) <= precIau2000Cties,
+		precModel == SemodPrecIau2000:
+		eps = (((1.813e-3*T-5.9e-4)*T-46.84024)*T + 84381.406) * DegToRad / 3600
+	case precModelShort == SemodPrecIau2006 && math.Abs(T) <= precIau2006Cties:
+		eps = (((((-4.34e-8*T-5.76e-7)*T+2.0034e-3)*T-1.831e-4)*T-46.836769)*T + 84381.406) * DegToRad / 3600.0
+	case precModel == SemodPrecNewcomb:
+		Tn := (J - 2396758.0) / 36525.0
+		eps = (0.0017*Tn*Tn*Tn - 0.0085*Tn*Tn - 46.837*Tn + 84451.68) * DegToRad / 3600.0
+	case precModel == SemodPrecIau2006:
+		eps = (((((-4.34e-8*T-5.76e-7)*T+2.0034e-3)*T-1.831e-4)*T-46.836769)*T + 84381.406) * DegToRad / 3600.0
+	case precModel == SemodPrecBretagnon2003:
+		eps = ((((((-3e-11*T-2.48e-8)*T-5.23e-7)*T+1.99911e-3)*T-1.667e-4)*T-46.836051)*T + 84381.40880) * DegToRad / 3600.0
+	case precModel == SemodPrecSimon1994:
+		eps = (((((2.5e-8*T-5.1e-7)*T+1.9989e-3)*T-1.52e-4)*T-46.80927)*T + 84381.412) * DegToRad / 3600.0
+	case precModel == SemodPrecWilliams1994:
+		eps = ((((-1.0e-6*T+2.0e-3)*T-1.74e-4)*T-46.833960)*T + 84381.409) * DegToRad / 3600.0
+	case precModel == SemodPrecLaskar1986 || precModel == SemodPrecWillEpsLask:
+		T /= 10.0
+		eps = (((((((((2.45e-10*T+5.79e-9)*T+2.787e-7)*T+
+			7.12e-7)*T-3.905e-5)*T-2.4967e-3)*T-
+			5.138e-3)*T+1.99925)*T-0.0155)*T-468.093)*T +
+			84381.448
+		eps *= DegToRad / 3600.0
+	case precModel == SemodPrecOwen1990:
+		eps = epsilnOwen1986(J) * DegToRad
+	default:
+		_, eps = ldpPeps(J)
+		if iflag&SeflgJplhorApprox != 0 && jplhoraModel() != SemodJplhora2 {
+			eps += dcorJpl(dcorEpsJpl, J) / (1000.0 * 3600.0) * DegToRad
+		}
+	}
+	return eps
+}
+
+// 短期岁差模型的适用范围（以J2000为中心的儒略世纪数）
+const (
+	precIau1976Cties = 2.0
+	precIau2000Cties = 2.0
+	precIau2006Cties = 75.0
+)
+
+// precess 将赤道直角坐标R在历元J与J2000之间进行岁差换算
+// direction为jToJ2000或j2000ToJ，结果写回R
+// 岁差模型由swed.AstroModels选择，短期模型只在其适用范围内使用
+// JPL Horizons模式与JPL Horizons一样使用IAU 1976岁差，1799年以前和2202年以后使用Owen 1990
+func precess(R []Float64, J Float64, iflag Int32, direction int) {
+	T := (J - J2000) / 36525.0
+	precModel := swed.AstroModels[SeModelPrecLongterm]
+	precModelShort := swed.AstroModels[SeModelPrecShortterm]
+	if precModel == 0 {
+		precModel = SemodPrecDefault
+	}
+	if precModelShort == 0 {
+		precModelShort = SemodPrecDefaultShort
+	}
+	switch {
+	case isJplhor(J, iflag) && J > horizonsTjdBeg && J < horizonsTjdEnd:
+		precess1(R, J, direction, SemodPrecIau1976)
+	case isJplhor(J, iflag):
+		precess3(R, J, iflag, direction, SemodPrecOwen1990)
+	case precModelShort == SemodPrecIau1976 && math.Abs(T) <= precIau1976Cties:
+		precess1(R, J, direction, SemodPrecIau1976)
+	case precModel == SemodPrecIau1976:
+		precess1(R, J, direction, SemodPrecIau1976)
+	case precModelShort == SemodPrecIau2000 && math.Abs(T) <= precIau2000Cties:
+		precess1(R, J, direction, SemodPrecIau2000)
+	case precModel == SemodPrecIau2000:
+		precess1(R, J, direction, SemodPrecIau2000)
+	case precModelShort == SemodPrecIau2006 && math.Abs(T) <= precIau2006Cties:
+		precess1(R, J, direction, SemodPrecIau2006)
+	case precModel == SemodPrecIau2006:
+		precess1(R, J, direction, SemodPrecIau2006)
+	case precModel == SemodPrecBretagnon2003:
+		precess1(R, J, direction, SemodPrecBretagnon2003)
+	case precModel == SemodPrecNewcomb:
+		precess1(R, J, direction, SemodPrecNewcomb)
+	case precModel == SemodPrecLaskar1986:
+		precess2(R, J, iflag, direction, SemodPrecLaskar1986)
+	case precModel == SemodPrecSimon1994:
+		precess2(R, J, iflag, direction, SemodPrecSimon1994)
+	case precModel == SemodPrecWilliams1994 || precModel == SemodPrecWillEpsLask:
+		precess2(R, J, iflag, direction, SemodPrecWilliams1994)
+	case precModel == SemodPrecOwen1990:
+		precess3(R, J, iflag, direction, SemodPrecOwen1990)
+	default:
+		precess3(R, J, iflag, direction, SemodPrecVondrak2011)
+	}
+}
+
+// precess1 用赤道岁差角zeta、z、theta进行岁差换算
+// IAU 1976: Lieske et al., A&A 58, 1-16 (1977)
+// IAU 2000/2006: Capitaine, Wallace & Chapront, A&A 412, 567-586 (2003)
+// Newcomb: 按Kinoshita（1975）的表达式
+func precess1(R []Float64, J Float64, direction int, precMethod int) {
+	var Z, z, TH Float64
+	if J == J2000 {
+		return
+	}
+	T := (J - J2000) / 36525.0
+	switch precMethod {
+	case SemodPrecIau1976:
+		Z = ((0.017998*T+0.30188)*T + 2306.2181) * T * DegToRad / 3600
+		z = ((0.018203*T+1.09468)*T + 2306.2181) * T * DegToRad / 3600
+		TH = ((-0.041833*T-0.42665)*T + 2004.3109) * T * DegToRad / 3600
+	case SemodPrecIau2000:
+		Z = (((((-0.0000002*T-0.0000327)*T+0.0179663)*T+0.3019015)*T+2306.0809506)*T + 2.5976176) * DegToRad / 3600
+		z = (((((-0.0000003*T-0.000047)*T+0.0182237)*T+1.0947790)*T+2306.0803226)*T - 2.5976176) * DegToRad / 3600
+		TH = ((((-0.0000001*T-0.0000601)*T-0.0418251)*T-0.4269353)*T + 2004.1917476) * T * DegToRad / 3600
+	case SemodPrecIau2006:
+		Z = (((((-0.0000003173*T-0.000005971)*T+0.01801828)*T+0.2988499)*T+2306.083227)*T + 2.650545) * DegToRad / 3600
+		z = (((((-0.0000002904*T-0.000028596)*T+0.01826837)*T+1.0927348)*T+2306.077181)*T - 2.650545) * DegToRad / 3600
+		TH = ((((-0.00000011274*T-0.000007089)*T-0.04182264)*T-0.4294934)*T + 2004.191903) * T * DegToRad / 3600
+	case SemodPrecBretagnon2003:
+		Z = ((((((-0.00000000013*T-0.0000003040)*T-0.000005708)*T+0.01801752)*T+0.3023262)*T+2306.080472)*T + 2.72767) * DegToRad / 3600
+		z = ((((((-0.00000000005*T-0.0000002486)*T-0.000028276)*T+0.01826676)*T+1.0956768)*T+2306.076070)*T - 2.72767) * DegToRad / 3600
+		TH = ((((((0.000000000009*T+0.00000000036)*T-0.0000001127)*T-0.000007291)*T-0.04182364)*T-0.4266980)*T + 2004.190936) * T * DegToRad / 3600
+	case SemodPrecNewcomb:
+		const mills = 365242.198782 // 回归千年
+		t1 := (J2000 - B1850) / mills
+		t2 := (J - B1850) / mills
+		T = t2 - t1
+		T2 := T * T
+		T3 := T2 * T
+		Z1 := 23035.5548 + 139.720*t1 + 0.069*t1*t1
+		Z = Z1*T + (30.242-0.269*t1)*T2 + 17.996*T3
+		z = Z1*T + (109.478-0.387*t1)*T2 + 18.324*T3
+		TH = (20051.125-85.294*t1-0.365*t1*t1)*T + (-42.647-0.365*t1)*T2 - 41.802*T3
+		Z *= DegToRad / 3600.0
+		z *= DegToRad / 3600.0
+		TH *= DegToRad / 3600.0
+	default:
+		return
+	}
+	sinth, costh := math.Sin(TH), math.Cos(TH)
+	sinZ, cosZ := math.Sin(Z), math.Cos(Z)
+	sinz, cosz := math.Sin(z), math.Cos(z)
+	A := cosZ * costh
+	B := sinZ * costh
+	var x [3]Float64
+	if direction == j2000ToJ {
+		x[0] = (A*cosz-sinZ*sinz)*R[0] - (B*cosz+cosZ*sinz)*R[1] - sinth*cosz*R[2]
+		x[1] = (A*sinz+sinZ*cosz)*R[0] - (B*sinz-cosZ*cosz)*R[1] - sinth*sinz*R[2]
+		x[2] = cosZ*sinth*R[0] - sinZ*sinth*R[1] + costh*R[2]
+	} else {
+		x[0] = (A*cosz-sinZ*sinz)*R[0] + (A*sinz+sinZ*cosz)*R[1] + cosZ*sinth*R[2]
+		x[1] = -(B*cosz+cosZ*sinz)*R[0] - (B*sinz-cosZ*cosz)*R[1] - sinZ*sinth*R[2]
+		x[2] = -sinth*cosz*R[0] - sinth*sinz*R[1] + costh*R[2]
+	}
+	R[0], R[1], R[2] = x[0], x[1], x[2]
+}
+
+// Laskar、Simon和Williams模型的岁差系数：
+// 黄经总岁差pA（角秒），动黄道在J2000黄道上的交点和倾角（弧度）
+// Simon和Williams保留了Laskar的t^4以上各项
+var (
+	pAcofWilliams   = [10]Float64{-8.66e-10, -4.759e-8, 2.424e-7, 1.3095e-5, 1.7451e-4, -1.8055e-3, -0.235316, 0.076, 110.5407, 50287.70000}
+	nodecofWilliams = [11]Float64{6.6402e-16, -2.69151e-15, -1.547021e-12, 7.521313e-12, 1.9e-10, -3.54e-9, -1.8103e-7, 1.26e-7, 7.436169e-5, -0.04207794833, 3.052115282424}
+	inclcofWilliams = [11]Float64{1.2147e-16, 7.3759e-17, -8.26287e-14, 2.503410e-13, 2.4650839e-11, -5.4000441e-11, 1.32115526e-9, -6.012e-7, -1.62442e-5, 0.00227850649, 0.0}
+
+	pAcofSimon   = [10]Float64{-8.66e-10, -4.759e-8, 2.424e-7, 1.3095e-5, 1.7451e-4, -1.8055e-3, -0.235316, 0.07732, 111.2022, 50288.200}
+	nodecofSimon = [11]Float64{6.6402e-16, -2.69151e-15, -1.547021e-12, 7.521313e-12, 1.9e-10, -3.54e-9, -1.8103e-7, 2.579e-8, 7.4379679e-5, -0.0420782900, 3.0521126906}
+	inclcofSimon = [11]Float64{1.2147e-16, 7.3759e-17, -8.26287e-14, 2.503410e-13, 2.4650839e-11, -5.4000441e-11, 1.32115526e-9, -5.99908e-7, -1.624383e-5, 0.002278492868, 0.0}
+
+	pAcofLaskar   = [10]Float64{-8.66e-10, -4.759e-8, 2.424e-7, 1.3095e-5, 1.7451e-4, -1.8055e-3, -0.235316, 0.07732, 111.1971, 50290.966}
+	nodecofLaskar = [11]Float64{6.6402e-16, -2.69151e-15, -1.547021e-12, 7.521313e-12, 6.3190131e-10, -3.48388152e-9, -1.813065896e-7, 2.75036225e-8, 7.4394531426e-5, -0.042078604317, 3.052112654975}
+	inclcofLaskar = [11]Float64{1.2147e-16, 7.3759e-17, -8.26287e-14, 2.503410e-13, 2.4650839e-11, -5.4000441e-11, 1.32115526e-9, -5.998737027e-7, -1.6242797091e-5, 0.002278495537, 0.0}
+)
+
+// precess2 用Laskar的展开式通过基本旋转进行岁差换算
+// Laskar, A&A 157, 59070 (1986); Simon et al., A&A 282, 663 (1994);
+// Williams, AJ 108, 711 (1994)
+func precess2(R []Float64, J Float64, iflag Int32, direction int, precMethod int) {
+	if J == J2000 {
+		return
+	}
+	var pAcof *[10]Float64
+	var nodecof, inclcof *[11]Float64
+	switch precMethod {
+	case SemodPrecSimon1994:
+		pAcof, nodecof, inclcof = &pAcofSimon, &nodecofSimon, &inclcofSimon
+	case SemodPrecWilliams1994:
+		pAcof, nodecof, inclcof = &pAcofWilliams, &nodecofWilliams, &inclcofWilliams
+	default:
+		pAcof, nodecof, inclcof = &pAcofLaskar, &nodecofLaskar, &inclcofLaskar
+	}
+	T := (J - J2000) / 36525.0
+	// 先绕x轴从起始赤道旋转到黄道
+	var eps Float64
+	if direction == jToJ2000 {
+		eps = epsiln(J, iflag)
+	} else {
+		eps = epsiln(J2000, iflag)
+	}
+	sineps, coseps := math.Sin(eps), math.Cos(eps)
+	var x [3]Float64
+	x[0] = R[0]
+	x[1] = coseps*R[1] + sineps*R[2]
+	x[2] = -sineps*R[1] + coseps*R[2]
+	// 黄经岁差
+	T /= 10.0 // 千年
+	pA := pAcof[0]
+	for i := 1; i < 10; i++ {
+		pA = pA*T + pAcof[i]
+	}
+	pA *= DegToRad / 3600 * T
+	// 动黄道在J2000黄道上的交点
+	W := nodecof[0]
+	for i := 1; i < 11; i++ {
+		W = W*T + nodecof[i]
+	}
+	// 绕z轴旋转到交点
+	z := W
+	if direction == jToJ2000 {
+		z = W + pA
+	}
+	B, A := math.Cos(z), math.Sin(z)
+	x[0], x[1] = B*x[0]+A*x[1], -A*x[0]+B*x[1]
+	// 绕新的x轴旋转动黄道相对J2000黄道的倾角
+	z = inclcof[0]
+	for i := 1; i < 11; i++ {
+		z = z*T + inclcof[i]
+	}
+	if direction == jToJ2000 {
+		z = -z
+	}
+	B, A = math.Cos(z), math.Sin(z)
+	x[1], x[2] = B*x[1]+A*x[2], -A*x[1]+B*x[2]
+	// 绕新的z轴从交点转回
+	if direction == jToJ2000 {
+		z = -W
+	} else {
+		z = -W - pA
+	}
+	B, A = math.Cos(z), math.Sin(z)
+	x[0], x[1] = B*x[0]+A*x[1], -A*x[0]+B*x[1]
+	// 绕x轴旋转到终了赤道
+	if direction == jToJ2000 {
+		eps = epsiln(J2000, iflag)
+	} else {
+		eps = epsiln(J, iflag)
+	}
+	sineps, coseps = math.Sin(eps), math.Cos(eps)
+	x[1], x[2] = coseps*x[1]-sineps*x[2], sineps*x[1]+coseps*x[2]
+	R[0], R[1], R[2] = x[0], x[1], x[2]
+}
+
+// precess3 用岁差矩阵进行岁差换算：Owen 1990或Vondrák 2011
+func precess3(R []Float64, J Float64, iflag Int32, direction int, precMethod int) {
+	if J == J2000 {
+		return
+	}
+	var pmat [9]Float64
+	if precMethod == SemodPrecOwen1990 {
+		pmat = owenPreMatrix(J, iflag)
+	} else {
+		pmat = prePmat(J)
+	}
+	var x [3]Float64
+	if direction == j2000ToJ {
+		for i := 0; i <= 2; i++ {
+			j := i * 3
+			x[i] = R[0]*pmat[j+0] + R[1]*pmat[j+1] + R[2]*pmat[j+2]
+		}
+	} else {
+		for i := 0; i <= 2; i++ {
+			x[i] = R[0]*pmat[i+0] + R[1]*pmat[i+3] + R[2]*pmat[i+6]
+		}
+	}
+	R[0], R[1], R[2] = x[0], x[1], x[2]
+}
+
+// guessEpheFlag 推测当前使用的星历：JPL文件已打开时为JPL星历，否则为瑞士星历
+func guessEpheFlag() Int32 {
+	if swed.JplFileIsOpen {
+		return SeflgJpleph
+	}
+	return SeflgSwieph
+}
+
+// bessel 用Bessel公式在逐日的表v（n个值）中内插，t为距表首的天数
+// 表外使用表首或表尾的值
+func bessel(v []Float64, n int, t Float64) Float64 {
+	if t <= 0 {
+		return v[0]
+	}
+	if t >= Float64(n-1) {
+		return v[n-1]
+	}
+	p := math.Floor(t)
+	iy := int(t)
+	// 零阶估计为当天的值
+	ans := v[iy]
+	k := iy + 1
+	if k >= n {
+		return ans
+	}
+	// 表格间隔的小数部分
+	p = t - p
+	ans += p * (v[k] - v[iy])
+	if iy-1 < 0 || iy+2 >= n {
+		return ans
+	}
+	// 一阶差分
+	var d [5]Float64
+	k = iy - 2
+	for i := 0; i < 5; i++ {
+		if k < 0 || k+1 >= n {
+			d[i] = 0
+		} else {
+			d[i] = v[k+1] - v[k]
+		}
+		k++
+	}
+	// 二阶差分
+	for i := 0; i < 4; i++ {
+		d[i] = d[i+1] - d[i]
+	}
+	b := 0.25 * p * (p - 1.0)
+	ans += b * (d[1] + d[2])
+	if iy+2 >= n {
+		return ans
+	}
+	// 三阶差分
+	for i := 0; i < 3; i++ {
+		d[i] = d[i+1] - d[i]
+	}
+	b = 2.0 * b / 3.0
+	ans += (p - 0.5) * b * d[1]
+	if iy-2 < 0 || iy+3 > n {
+		return ans
+	}
+	// 四阶差分
+	for i := 0; i < 2; i++ {
+		d[i] = d[i+1] - d[i]
+	}
+	b = 0.125 * b * (p + 1.0) * (p - 2.0)
+	ans += b * (d[0] + d[1])
+	return ans
+}
+
+// nutation 计算历元tjd的黄经章动和交角章动（弧度）
+// 章动模型由swed.AstroModels选择
+// JPL Horizons模式使用IAU 1980章动，并加上EOP文件中逐日的dpsi和deps改正值；
+// 1962年以前使用1962年1月20日的改正值，表尾以后使用最后的改正值
+func nutation(tjd Float64, iflag Int32) [2]Float64 {
+	nutModel := swed.AstroModels[SeModelNut]
+	if nutModel == 0 {
+		nutModel = SemodNutDefault
+	}
+	if isJplhor(tjd, iflag) {
+		nutlo := calcNutationIau1980(tjd)
+		if iflag&SeflgJplhor != 0 {
+			n := int(swed.EopTjdEnd - swed.EopTjdBeg + 0.000001)
+			J2 := math.Max(tjd, swed.EopTjdBegHorizons)
+			nutlo[0] += bessel(swed.Dpsi, n+1, J2-swed.EopTjdBeg) / 3600.0 * DegToRad
+			nutlo[1] += bessel(swed.Deps, n+1, J2-swed.EopTjdBeg) / 3600.0 * DegToRad
+		} else {
+			nutlo[0] += dpsiIau1980Tjd0 / 3600.0 * DegToRad
+			nutlo[1] += depsIau1980Tjd0 / 3600.0 * DegToRad
+		}
+		return nutlo
+	}
+	switch nutModel {
+	case SemodNutIau1980, SemodNutIauCorr1987:
+		return calcNutationIau1980(tjd)
+	case SemodNutWoolard:
+		return calcNutationWoolard(tjd)
+	default:
+		nutlo := calcNutationIau2000ab(tjd)
+		if iflag&SeflgJplhorApprox != 0 && jplhoraModel() == SemodJplhora2 {
+			nutlo[0] += -41.7750 / 3600.0 / 1000.0 * DegToRad
+			nutlo[1] += -6.8192 / 3600.0 / 1000.0 * DegToRad
+		}
+		return nutlo
+	}
+}
+
+// calcNutationIau1980 IAU 1980章动理论（Seidelmann等，1982）
+// 章动模型为SemodNutIauCorr1987时加上Herring（1987）的修正
+func calcNutationIau1980(J Float64) [2]Float64 {
+	// 多倍角的正弦和余弦
+	var ss, cc [5][8]Float64
+	nutModel := swed.AstroModels[SeModelNut]
+	if nutModel == 0 {
+		nutModel = SemodNutDefault
+	}
+	T := (J - J2000) / 36525.0
+	T2 := T * T
+	// FK5参考系中的基本幅角
+	// 月球升交点平黄经
+	OM := -6962890.539*T + 450160.280 + (0.008*T+7.455)*T2
+	OM = degnorm(OM/3600) * DegToRad
+	// 太阳平近点角
+	MS := 129596581.224*T + 1287099.804 - (0.012*T+0.577)*T2
+	MS = degnorm(MS/3600) * DegToRad
+	// 月球平近点角
+	MM := 1717915922.633*T + 485866.733 + (0.064*T+31.310)*T2
+	MM = degnorm(MM/3600) * DegToRad
+	// 月球纬度幅角
+	FF := 1739527263.137*T + 335778.877 + (0.011*T-13.257)*T2
+	FF = degnorm(FF/3600) * DegToRad
+	// 月球平距角
+	DD := 1602961601.328*T + 1072261.307 + (0.019*T-6.891)*T2
+	DD = degnorm(DD/3600) * DegToRad
+	args := [5]Float64{MM, MS, FF, DD, OM}
+	ns := [5]int{3, 2, 4, 4, 2}
+	for k := 0; k <= 4; k++ {
+		su := math.Sin(args[k])
+		cu := math.Cos(args[k])
+		ss[k][0] = su
+		cc[k][0] = cu
+		sv := 2.0 * su * cu
+		cv := cu*cu - su*su
+		ss[k][1] = sv
+		cc[k][1] = cv
+		for i := 2; i < ns[k]; i++ {
+			s := su*cv + cu*sv
+			cv = cu*cv - su*sv
+			sv = s
+			ss[k][i] = sv
+			cc[k][i] = cv
+		}
+	}
+	// 表中没有的第一项
+	C := (-0.01742*T - 17.1996) * ss[4][0]
+	D := (0.00089*T + 9.2025) * cc[4][0]
+	for n := 0; n < len(nutIau1980Tab); n += 9 {
+		p := nutIau1980Tab[n : n+9]
+		if nutModel != SemodNutIauCorr1987 && (p[0] == 101 || p[0] == 102) {
+			continue
+		}
+		// 幅角的正弦和余弦
+		k1 := false
+		var cv, sv Float64
+		for m := 0; m < 5; m++ {
+			j := int(p[m])
+			if j > 100 {
+				j = 0 // p[0]为标志
+			}
+			if j == 0 {
+				continue
+			}
+			k := j
+			if j < 0 {
+				k = -k
+			}
+			su := ss[m][k-1]
+			if j < 0 {
+				su = -su
+			}
+			cu := cc[m][k-1]
+			if !k1 {
+				sv = su
+				cv = cu
+				k1 = true
+			} else {
+				sw := su*cv + cu*sv
+				cv = cu*cv - su*sv
+				sv = sw
+			}
+		}
+		f := Float64(p[5]) * 0.0001
+		if p[6] != 0 {
+			f += 0.00001 * T * Float64(p[6])
+		}
+		g := Float64(p[7]) * 0.0001
+		if p[8] != 0 {
+			g += 0.00001 * T * Float64(p[8])
+		}
+		if p[0] >= 100 {
+			f *= 0.1
+			g *= 0.1
+		}
+		if p[0] != 102 {
+			C += f * sv
+			D += g * cv
+		} else {
+			C += f * cv
+			D += g * sv
+		}
+	}
+	return [2]Float64{DegToRad * C / 3600.0, DegToRad * D / 3600.0}
+}
+
+// calcNutationIau2000ab IAU 2000A/2000B章动理论
+// IAU 2000A包括行星章动（MHB2000，不含自由核章动）
+func calcNutationIau2000ab(J Float64) [2]Float64 {
+	T := (J - J2000) / 36525.0
+	// 基本幅角，Simon等（1994）
+	// 月球平近点角
+	M := degnorm((485868.249036+
+		T*(1717915923.2178+
+			T*(31.8792+
+				T*(0.051635+
+					T*(-0.00024470)))))/3600.0) * DegToRad
+	// 太阳平近点角
+	SM := degnorm((1287104.79305+
+		T*(129596581.0481+
+			T*(-0.5532+
+				T*(0.000136+
+					T*(-0.00001149)))))/3600.0) * DegToRad
+	// 月球纬度幅角
+	F := degnorm((335779.526232+
+		T*(1739527262.8478+
+			T*(-12.7512+
+				T*(-0.001037+
+					T*(0.00000417)))))/3600.0) * DegToRad
+	// 月球平距角
+	D := degnorm((1072260.70369+
+		T*(1602961601.2090+
+			T*(-6.3706+
+				T*(0.006593+
+					T*(-0.00003169)))))/3600.0) * DegToRad
+	// 月球升交点平黄经
+	OM := degnorm((450160.398036+
+		T*(-6962890.5431+
+			T*(7.4722+
+				T*(0.007702+
+					T*(-0.00005939)))))/3600.0) * DegToRad
+	// 日月章动级数，从小项开始倒序求和
+	var dpsi, deps Float64
+	nutModel := swed.AstroModels[SeModelNut]
+	if nutModel == 0 {
+		nutModel = SemodNutDefault
+	}
+	inls := nutNls
+	if nutModel == SemodNutIau2000B {
+		inls = nutNls2000B
+	}
+	for i := inls - 1; i >= 0; i-- {
+		j := i * 5
+		darg := radnorm(Float64(nutNlsArg[j+0])*M +
+			Float64(nutNlsArg[j+1])*SM +
+			Float64(nutNlsArg[j+2])*F +
+			Float64(nutNlsArg[j+3])*D +
+			Float64(nutNlsArg[j+4])*OM)
+		sinarg := math.Sin(darg)
+		cosarg := math.Cos(darg)
+		k := i * 6
+		dpsi += (Float64(nutClsCoef[k+0])+Float64(nutClsCoef[k+1])*T)*sinarg + Float64(nutClsCoef[k+2])*cosarg
+		deps += (Float64(nutClsCoef[k+3])+Float64(nutClsCoef[k+4])*T)*cosarg + Float64(nutClsCoef[k+5])*sinarg
+	}
+	var nutlo [2]Float64
+	nutlo[0] = dpsi * o1mas2deg
+	nutlo[1] = deps * o1mas2deg
+	if nutModel == SemodNutIau2000A {
+		// 行星章动。MHB2000程序计算日月章动和行星章动时所用的
+		// Delaunay幅角略有不同，这里照样保留
+		// 月球平近点角
+		AL := radnorm(2.35555598 + 8328.6914269554*T)
+		// 太阳平近点角
+		ALSU := radnorm(6.24006013 + 628.301955*T)
+		// 月球纬度幅角
+		AF := radnorm(1.627905234 + 8433.466158131*T)
+		// 月球平距角
+		AD := radnorm(5.198466741 + 7771.3771468121*T)
+		// 月球升交点平黄经
+		AOM := radnorm(2.18243920 - 33.757045*T)
+		// 水星至海王星的平黄经（Souchay等，1999）
+		ALME := radnorm(4.402608842 + 2608.7903141574*T)
+		ALVE := radnorm(3.176146697 + 1021.3285546211*T)
+		ALEA := radnorm(1.753470314 + 628.3075849991*T)
+		ALMA := radnorm(6.203480913 + 334.0612426700*T)
+		ALJU := radnorm(0.599546497 + 52.9690962641*T)
+		ALSA := radnorm(0.874016757 + 21.3299104960*T)
+		ALUR := radnorm(5.481293871 + 7.4781598567*T)
+		ALNE := radnorm(5.321159000 + 3.8127774000*T)
+		// 黄经总岁差
+		APA := (0.02438175 + 0.00000538691*T) * T
+		// 行星章动级数，倒序求和
+		dpsi = 0
+		deps = 0
+		for i := nutNpl - 1; i >= 0; i-- {
+			j := i * 14
+			darg := radnorm(Float64(nutNplArg[j+0])*AL +
+				Float64(nutNplArg[j+1])*ALSU +
+				Float64(nutNplArg[j+2])*AF +
+				Float64(nutNplArg[j+3])*AD +
+				Float64(nutNplArg[j+4])*AOM +
+				Float64(nutNplArg[j+5])*ALME +
+				Float64(nutNplArg[j+6])*ALVE +
+				Float64(nutNplArg[j+7])*ALEA +
+				Float64(nutNplArg[j+8])*ALMA +
+				Float64(nutNplArg[j+9])*ALJU +
+				Float64(nutNplArg[j+10])*ALSA +
+				Float64(nutNplArg[j+11])*ALUR +
+				Float64(nutNplArg[j+12])*ALNE +
+				Float64(nutNplArg[j+13])*APA)
+			k := i * 4
+			sinarg := math.Sin(darg)
+			cosarg := math.Cos(darg)
+			dpsi += Float64(nutIcpl[k+0])*sinarg + Float64(nutIcpl[k+1])*cosarg
+			deps += Float64(nutIcpl[k+2])*sinarg + Float64(nutIcpl[k+3])*cosarg
+		}
+		nutlo[0] += dpsi * o1mas2deg
+		nutlo[1] += deps * o1mas2deg
+		// 采用P03岁差（IAU 2006）所需的改正，Capitaine等，A&A 432, 366 (2005)
+		dpsi = -8.1*math.Sin(OM) - 0.6*math.Sin(2*F-2*D+2*OM)
+		dpsi += T * (47.8*math.Sin(OM) + 3.7*math.Sin(2*F-2*D+2*OM) + 0.6*math.Sin(2*F+2*OM) - 0.6*math.Sin(2*OM))
+		deps = T * (-25.6*math.Cos(OM) - 1.6*math.Cos(2*F-2*D+2*OM))
+		nutlo[0] += dpsi / (3600.0 * 1000000.0)
+		nutlo[1] += deps / (3600.0 * 1000000.0)
+	}
+	nutlo[0] *= DegToRad
+	nutlo[1] *= DegToRad
+	return nutlo
+}
+
+// calcNutationWoolard Woolard（1953）章动理论的简化实现
+func calcNutationWoolard(J Float64) [2]Float64 {
+	mjd := J - J1900
+	t := mjd / 36525.
+	t2 := t * t
+	a := 100.0021358 * t
+	b := 360. * (a - Float64(int64(a)))
+	ls := 279.697 + .000303*t2 + b
+	a = 1336.855231 * t
+	b = 360. * (a - Float64(int64(a)))
+	ld := 270.434 - .001133*t2 + b
+	a = 99.99736056000026 * t
+	b = 360. * (a - Float64(int64(a)))
+	ms := 358.476 - .00015*t2 + b
+	a = 13255523.59 * t
+	b = 360. * (a - Float64(int64(a)))
+	md := 296.105 + .009192*t2 + b
+	a = 5.372616667 * t
+	b = 360. * (a - Float64(int64(a)))
+	nm := 259.183 + .002078*t2 - b
+	// 转换为弧度
+	tls := 2 * ls * DegToRad
+	nm = nm * DegToRad
+	tnm := 2 * nm
+	ms = ms * DegToRad
+	tld := 2 * ld * DegToRad
+	md = md * DegToRad
+	// 黄经章动和交角章动（角秒）
+	dpsi := (-17.2327-.01737*t)*math.Sin(nm) + (-1.2729-.00013*t)*math.Sin(tls) +
+		.2088*math.Sin(tnm) - .2037*math.Sin(tld) + (.1261-.00031*t)*math.Sin(ms) +
+		.0675*math.Sin(md) - (.0497-.00012*t)*math.Sin(tls+ms) -
+		.0342*math.Sin(tld-nm) - .0261*math.Sin(tld+md) + .0214*math.Sin(tls-ms) -
+		.0149*math.Sin(tls-tld+md) + .0124*math.Sin(tls-nm) + .0114*math.Sin(tld-md)
+	deps := (9.21+.00091*t)*math.Cos(nm) + (.5522-.00029*t)*math.Cos(tls) -
+		.0904*math.Cos(tnm) + .0884*math.Cos(tld) + .0216*math.Cos(tls+ms) +
+		.0183*math.Cos(tld-nm) + .0113*math.Cos(tld+md) - .0093*math.Cos(tls-ms) -
+		.0066*math.Cos(tls-nm)
+	return [2]Float64{dpsi / 3600.0 * DegToRad, deps / 3600.0 * DegToRad}
+}
+
+// nutMatrix 根据章动和平黄赤交角计算章动矩阵
+func nutMatrix(nu *Nut, oe *Epsilon) {
+	psi := nu.Nutlo[0]
+	eps := oe.Eps + nu.Nutlo[1]
+	sinpsi := math.Sin(psi)
+	cospsi := math.Cos(psi)
+	sineps0 := oe.Seps
+	coseps0 := oe.Ceps
+	sineps := math.Sin(eps)
+	coseps := math.Cos(eps)
+	nu.Matrix[0][0] = cospsi
+	nu.Matrix[0][1] = sinpsi * coseps
+	nu.Matrix[0][2] = sinpsi * sineps
+	nu.Matrix[1][0] = -sinpsi * coseps0
+	nu.Matrix[1][1] = cospsi*coseps*coseps0 + sineps*sineps0
+	nu.Matrix[1][2] = cospsi*sineps*coseps0 - coseps*sineps0
+	nu.Matrix[2][0] = -sinpsi * sineps0
+	nu.Matrix[2][1] = cospsi*coseps*sineps0 - sineps*coseps0
+	nu.Matrix[2][2] = cospsi*sineps*sineps0 + coseps*coseps0
+}
+
+// frameBias2006 IAU 2006参考架偏差矩阵
+var frameBias2006 = [3][3]Float64{
+	{+0.99999999999999412, +0.00000007078368695, -0.00000008056214212},
+	{-0.00000007078368961, +0.99999999999999700, -0.00000003306427981},
+	{+0.00000008056213978, +0.00000003306428553, +0.99999999999999634},
+}
+
+// frameBias2000 IAU 2000参考架偏差矩阵，与2006矩阵的结果几乎没有差别
+var frameBias2000 = [3][3]Float64{
+	{+0.9999999999999942, +0.0000000707827948, -0.0000000805621738},
+	{-0.0000000707827974, +0.9999999999999969, -0.0000000330604088},
+	{+0.0000000805621715, +0.0000000330604145, +0.9999999999999962},
+}
+
+// approxJplhor 近似JPL Horizons模式中赤经的改正
+func approxJplhor(x []Float64, tjd Float64, iflag Int32, backward bool) {
+	if iflag&SeflgJplhorApprox == 0 || jplhoraModel() == SemodJplhora2 {
+		return
+	}
+	dofs := dcorJpl(dcorRaJpl, tjd) / (1000.0 * 3600.0)
+	cartpol(x, x)
+	if backward {
+		x[0] -= dofs * DegToRad
+	} else {
+		x[0] += dofs * DegToRad
+	}
+	polcart(x, x)
+}
+
+// bias 在GCRS与J2000之间进行参考架偏差改正
+// 偏差模型由swed.AstroModels选择，SemodBiasNone时不作改正
+// 近似JPL Horizons模式另加赤经改正；其方法SemodJplhora2和1962年以前的SemodJplhora3不作偏差改正
+func bias(x []Float64, tjd Float64, iflag Int32, backward bool) {
+	biasModel := swed.AstroModels[SeModelBias]
+	if biasModel == 0 {
+		biasModel = SemodBiasDefault
+	}
+	if biasModel == SemodBiasNone {
+		return
+	}
+	if iflag&SeflgJplhorApprox != 0 {
+		if jplhoraModel() == SemodJplhora2 {
+			return
+		}
+		if jplhoraModel() == SemodJplhora3 && tjd < horizonsTjd0DpsiDepsIau1980 {
+			return
+		}
+	}
+	rb := &frameBias2006
+	if biasModel == SemodBiasIau2000 {
+		rb = &frameBias2000
+	}
+	var xx [6]Float64
+	if backward {
+		approxJplhor(x, tjd, iflag, true)
+		for i := 0; i <= 2; i++ {
+			xx[i] = x[0]*rb[i][0] + x[1]*rb[i][1] + x[2]*rb[i][2]
+			if iflag&SeflgSpeed != 0 {
+				xx[i+3] = x[3]*rb[i][0] + x[4]*rb[i][1] + x[5]*rb[i][2]
+			}
+		}
+	} else {
+		for i := 0; i <= 2; i++ {
+			xx[i] = x[0]*rb[0][i] + x[1]*rb[1][i] + x[2]*rb[2][i]
+			if iflag&SeflgSpeed != 0 {
+				xx[i+3] = x[3]*rb[0][i] + x[4]*rb[1][i] + x[5]*rb[2][i]
+			}
+		}
+		approxJplhor(xx[:], tjd, iflag, false)
+	}
+	copy(x[:3], xx[:3])
+	if iflag&SeflgSpeed != 0 {
+		copy(x[3:6], xx[3:6])
+	}
+}
+
+// icrs2fk5 在ICRS与FK5（J2000）之间转换，backward为真时由FK5转换到ICRS
+func icrs2fk5(x []Float64, iflag Int32, backward bool) {
+	rb := [3][3]Float64{
+		{+0.9999999999999928, +0.0000001110223287, +0.0000000441180557},
+		{-0.0000001110223330, +0.9999999999999891, +0.0000000964779176},
+		{-0.0000000441180450, -0.0000000964779225, +0.9999999999999943},
+	}
+	var xx [6]Float64
+	if backward {
+		for i := 0; i <= 2; i++ {
+			xx[i] = x[0]*rb[i][0] + x[1]*rb[i][1] + x[2]*rb[i][2]
+			if iflag&SeflgSpeed != 0 {
+				xx[i+3] = x[3]*rb[i][0] + x[4]*rb[i][1] + x[5]*rb[i][2]
+			}
+		}
+	} else {
+		for i := 0; i <= 2; i++ {
+			xx[i] = x[0]*rb[0][i] + x[1]*rb[1][i] + x[2]*rb[2][i]
+			if iflag&SeflgSpeed != 0 {
+				xx[i+3] = x[3]*rb[0][i] + x[4]*rb[1][i] + x[5]*rb[2][i]
+			}
+		}
+	}
+	copy(x[:6], xx[:])
+}
+
+// fk4Fk5 将B1950（FK4）的赤道直角坐标改正到FK5（Expl. Suppl.第167页）
+func fk4Fk5(xp []Float64, tjd Float64) {
+	if xp[0] == 0 && xp[1] == 0 && xp[2] == 0 {
+		return
+	}
+	// 速度为零时认为确实为零
+	correctSpeed := xp[3] != 0
+	cartpolSp(xp, xp)
+	xp[0] += (0.035 + 0.085*(tjd-B1950)/36524.2198782) / 3600 * 15 * DegToRad
+	if correctSpeed {
+		xp[3] += (0.085 / 36524.2198782) / 3600 * 15 * DegToRad
+	}
+	polcartSp(xp, xp)
+}
+
+// crc32Table 星历文件校验用的CRC-32表（AUTODIN II多项式，高位优先）
+var crc32Table [256]uint32
+
+func init() {
+	const crc32Poly = 0x04c11db7
+	for i := uint32(0); i < 256; i++ {
+		c := i << 24
+		for j := 8; j > 0; j-- {
+			if c&0x80000000 != 0 {
+				c = (c << 1) ^ crc32Poly
+			} else {
+				c = c << 1
+			}
+		}
+		crc32Table[i] = c
+	}
+}
+
+// crc32 计算星历文件使用的CRC-32校验和
+func crc32(buf []byte) uint32 {
+	crc := uint32(0xffffffff)
+	for _, b := range buf {
+		crc = (crc << 8) ^ crc32Table[(crc>>24)^uint32(b)]
+	}
+	return ^crc
+}
@@ -0,0 +1,139 @@
+// 儒略日与Go的time.Time之间的换算，时区（包括历史上的夏令时和时区变更）取自Go的时区数据库。
+//
+// time.Time使用外推的格里高利历，没有闰秒，其UTC在这里作为世界时处理；
+// 需要区分UT1和TT时使用UtcTimeToJd、JdetToTime和Jdut1ToTime。
+// float64儒略日在现代日期的分辨率约为40微秒，因此纳秒只在换算时按四舍五入保留到可表示的精度。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DstPolicy 规定夏令时切换时不唯一或不存在的地方时如何处理
+type DstPolicy int
+
+// 地方时的歧义处理，名称与含义同JavaScript Temporal的disambiguation选项
+const (
+	DstCompatible DstPolicy = iota // 重复的地方时取较早的时刻，跳过的地方时按切换前的时区偏移向后推移（如02:30变为03:30）
+	DstEarlier                     // 都取较早的时刻（跳过的地方时按切换后的时区偏移，如02:30变为01:30）
+	DstLater                       // 都取较晚的时刻（跳过的地方时按切换前的时区偏移，如02:30变为03:30）
+	DstReject                      // 返回错误
+)
+
+const (
+	jdUnixEpoch = 2440587.5 // 1970年1月1日0时UTC的儒略日
+	nsPerDay    = 86400e9   // 每日的纳秒数
+)
+
+// TimeToJd 将时刻t换算为儒略日（世界时），t的时区不影响结果
+// 日数与日内的纳秒分别计算，只在最后相加时舍入一次；需要更高精度时使用TimeToJulianDay
+func TimeToJd(t time.Time) Float64 {
+	return TimeToJulianDay(t).Float64()
+}
+
+// JdToTime 将儒略日tjd（世界时）换算为时区loc中的time.Time，loc为nil时使用UTC
+// 日内的时间按四舍五入取到纳秒
+func JdToTime(tjd Float64, loc *time.Location) time.Time {
+	return NewJulianDay(tjd).Time(loc)
+}
+
+// LocalToJd 将时区loc中的地方时换算为儒略日（世界时）
+// 年月日为外推的格里高利历，各项不能超出范围（time.Date会自动进位，这里视为错误）。
+// 夏令时切换时重复或跳过的地方时按policy处理；1890年前后标准时区建立以前，
+// 时区数据库给出地方平时（LMT）的偏移
+func LocalToJd(year, month, day, hour, min, sec, nsec int, loc *time.Location, policy DstPolicy) (Float64, error) {
+	if loc == nil {
+		return 0, fmt.Errorf("时区为空")
+	}
+	// 把地方时当作UTC，得到的时刻减去时区偏移即为真正的时刻
+	wall := time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.UTC)
+	if wall.Year() != year || int(wall.Month()) != month || wall.Day() != day {
+		return 0, fmt.Errorf("无效日期: 年 = %d, 月 = %d, 日 = %d", year, month, day)
+	}
+	if wall.Hour() != hour || wall.Minute() != min || wall.Second() != sec || wall.Nanosecond() != nsec {
+		return 0, fmt.Errorf("无效时间: %d:%d:%d.%09d", hour, min, sec, nsec)
+	}
+	t, err := resolveLocal(wall, loc, policy)
+	if err != nil {
+		return 0, err
+	}
+	return TimeToJd(t), nil
+}
+
+// resolveLocal 求地方时wall（以UTC表示的钟面时间）在时区loc中对应的时刻
+// 时区偏移在一日之内最多变化一次，因此只考虑前后一日的偏移
+func resolveLocal(wall time.Time, loc *time.Location, policy DstPolicy) (time.Time, error) {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+	var found []time.Time
+	for _, ofs := range []int{before, after} {
+		t := wall.Add(-time.Duration(ofs) * time.Second)
+		if _, o := t.In(loc).Zone(); o == ofs && (len(found) == 0 || !found[0].Equal(t)) {
+			found = append(found, t.In(loc))
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0], nil
+	case len(found) == 2:
+		// 重复的地方时（如夏令时结束）
+		if found[1].Before(found[0]) {
+			found[0], found[1] = found[1], found[0]
+		}
+		switch policy {
+		case DstLater:
+			return found[1], nil
+		case DstReject:
+			return time.Time{}, fmt.Errorf("地方时%s在时区%s中重复出现", wall.Format("2006-01-02 15:04:05"), loc)
+		}
+		return found[0], nil
+	}
+	// 跳过的地方时（如夏令时开始）：按切换前的偏移得到较晚的时刻，按切换后的偏移得到较早的时刻
+	switch policy {
+	case DstEarlier:
+		return wall.Add(-time.Duration(after) * time.Second).In(loc), nil
+	case DstReject:
+		return time.Time{}, fmt.Errorf("地方时%s在时区%s中不存在", wall.Format("2006-01-02 15:04:05"), loc)
+	}
+	return wall.Add(-time.Duration(before) * time.Second).In(loc), nil
+}
+
+// ZoneOffset 返回儒略日tjd（世界时）时时区loc的偏移（小时，东为正）和时区缩写，
+// 可用于UtcTimeZone
+func ZoneOffset(tjd Float64, loc *time.Location) (dTimezone Float64, name string) {
+	name, ofs := JdToTime(tjd, loc).Zone()
+	return Float64(ofs) / 3600.0, name
+}
+
+// UtcTimeToJd 将时刻t作为UTC换算为ET（TT）和UT1儒略日，见UtcToJd
+func UtcTimeToJd(t time.Time) (et, ut1 Float64, err error) {
+	t = t.UTC()
+	dsec := Float64(t.Second()) + Float64(t.Nanosecond())/1e9
+	return UtcToJd(Int32(t.Year()), Int32(t.Month()), Int32(t.Day()), Int32(t.Hour()), Int32(t.Minute()), dsec, SeGregCal)
+}
+
+// JdetToTime 将ET（TT）儒略日换算为时区loc中的time.Time（UTC），见JdetToUtc
+// time.Time不能表示闰秒，23:59:60.x表示为次日的00:00:00.x
+func JdetToTime(tjdEt Float64, loc *time.Location) time.Time {
+	iyear, imonth, iday, ihour, imin, dsec := JdetToUtc(tjdEt, SeGregCal)
+	return utcToTime(iyear, imonth, iday, ihour, imin, dsec, loc)
+}
+
+// Jdut1ToTime 将UT1儒略日换算为时区loc中的time.Time（UTC），见Jdut1ToUtc
+func Jdut1ToTime(tjdUt Float64, loc *time.Location) time.Time {
+	iyear, imonth, iday, ihour, imin, dsec := Jdut1ToUtc(tjdUt, SeGregCal)
+	return utcToTime(iyear, imonth, iday, ihour, imin, dsec, loc)
+}
+
+// utcToTime 由UTC的日期和时间组成时区loc中的time.Time，秒按四舍五入取到纳秒
+func utcToTime(iyear, imonth, iday, ihour, imin Int32, dsec Float64, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	ns := int64(math.Round(dsec * 1e9))
+	t := time.Date(int(iyear), time.Month(imonth), int(iday), int(ihour), int(imin), 0, 0, time.UTC)
+	return t.Add(time.Duration(ns)).In(loc)
+}
@@ -0,0 +1,127 @@
+// 站心坐标：观测者的地理位置，及其相对地心的J2000赤道直角坐标和速度，
+// 同swe_set_topo和swi_get_observer。
+
+package ephgo
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetTopo 设置观测者的地理位置，用于站心坐标（SeflgTopoctr）
+// geolon: 地理经度（度，东经为正）
+// geolat: 地理纬度（度，北纬为正）
+// geoalt: 海拔高度（米）
+func SetTopo(geolon, geolat, geoalt Float64) {
+	swed := GetSweData()
+	setTopo(geolon, geolat, geoalt)
+	SetSweData(swed)
+}
+
+// setTopo 设置观测者的地理位置，见SetTopo
+func setTopo(geolon, geolat, geoalt Float64) {
+	if swed.GeoposIsSet && swed.Topd.Geolon == geolon &&
+		swed.Topd.Geolat == geolat && swed.Topd.Geoalt == geoalt {
+		return
+	}
+	swed.Topd.Geolon = geolon
+	swed.Topd.Geolat = geolat
+	swed.Topd.Geoalt = geoalt
+	swed.GeoposIsSet = true
+	// 强制重新计算观测者位置矢量以及光行时等
+	swed.Topd.Teval = 0
+	forceAppPosEtc()
+}
+
+// getObserver 计算观测者在tjd（ET）时刻相对地心的位置和速度（J2000赤道直角坐标，天文单位）
+// doSave为真时结果保存在swed.Topd中
+func getObserver(tjd Float64, iflag Int32, doSave bool) ([6]Float64, error) {
+	var xobs [6]Float64
+	var eps, nut Float64
+	var nutlo [2]Float64
+	if !swed.GeoposIsSet {
+		return xobs, fmt.Errorf("未设置观测者的地理位置")
+	}
+	// 观测者的地心位置取决于恒星时，即取决于UT。
+	// 由ET换算的UT与用户的UT略有不同，但差别极小
+	tjdUt := tjd - calcDeltat(tjd, iflag)/86400.0
+	if swed.Oec.Teps == tjd && swed.Nut.Tnut == tjd {
+		eps = swed.Oec.Eps
+		nutlo = swed.Nut.Nutlo
+	} else {
+		eps = epsiln(tjd, iflag)
+		if iflag&SeflgNonut == 0 {
+			nutlo = nutation(tjd, iflag)
+		}
+	}
+	if iflag&SeflgNonut == 0 {
+		eps += nutlo[1]
+		nut = nutlo[0]
+	}
+	// 平恒星时或视恒星时，取决于是否设置SeflgNonut
+	sidt := sidtime0(tjdUt, eps*RadToDeg, nut*RadToDeg) * 15
+	// 高度相对于地球椭球面，而非大地水准面，由此引起的误差在500米以下，
+	// 对月球约为0.2至0.3角秒
+	f := Float64(EarthOblateness)
+	cosfi := math.Cos(swed.Topd.Geolat * DegToRad)
+	sinfi := math.Sin(swed.Topd.Geolat * DegToRad)
+	cc := 1 / math.Sqrt(cosfi*cosfi+(1-f)*(1-f)*sinfi*sinfi)
+	ss := (1 - f) * (1 - f) * cc
+	cosl := math.Cos(swed.Topd.Geolon * DegToRad)
+	sinl := math.Sin(swed.Topd.Geolon * DegToRad)
+	h := swed.Topd.Geoalt
+	// 地球参考系（ITRS）中的位置
+	xobs[0] = (EarthRadius*cc + h) * cosfi * cosl
+	xobs[1] = (EarthRadius*cc + h) * cosfi * sinl
+	xobs[2] = (EarthRadius*ss + h) * sinfi
+	// 极移：W = R2(xp)·R1(yp)，由ITRS转到以天球中间极为极的地球系；
+	// 只在EOP已加载且在数据范围内时使用，否则忽略（最大约0.5角秒，即15米）
+	if xp, yp, ok := eopPolarMotion(tjdUt); ok {
+		cx, sx := math.Cos(xp*DegToRad/3600), math.Sin(xp*DegToRad/3600)
+		cy, sy := math.Cos(yp*DegToRad/3600), math.Sin(yp*DegToRad/3600)
+		x0, y0, z0 := xobs[0], xobs[1], xobs[2]
+		y1 := cy*y0 + sy*z0
+		z1 := -sy*y0 + cy*z0
+		xobs[0] = cx*x0 - sx*z1
+		xobs[1] = y1
+		xobs[2] = sx*x0 + cx*z1
+	}
+	// 按恒星时转到真赤道坐标
+	coss := math.Cos(sidt * DegToRad)
+	sins := math.Sin(sidt * DegToRad)
+	x0, y0 := xobs[0], xobs[1]
+	xobs[0] = coss*x0 - sins*y0
+	xobs[1] = sins*x0 + coss*y0
+	// 速度
+	cartpol(xobs[:], xobs[:])
+	xobs[3] = EarthRotSpeed
+	xobs[4] = 0
+	xobs[5] = 0
+	polcartSp(xobs[:], xobs[:])
+	for i := 0; i <= 5; i++ {
+		xobs[i] /= Aunit
+	}
+	// 去掉章动；光行差总是需要观测者的速度
+	if iflag&SeflgNonut == 0 {
+		coortrf2(xobs[:], xobs[:], -swed.Nut.Snut, swed.Nut.Cnut)
+		coortrf2(xobs[3:], xobs[3:], -swed.Nut.Snut, swed.Nut.Cnut)
+		nutate(xobs[:], iflag|SeflgSpeed, true)
+	}
+	// 岁差到J2000，忽略参考架偏差（约45厘米）
+	precess(xobs[:], tjd, iflag, jToJ2000)
+	precessSpeed(xobs[:], tjd, iflag, jToJ2000)
+	if doSave {
+		swed.Topd.Xobs = xobs
+		swed.Topd.Teval = tjd
+		swed.Topd.TjdUt = tjdUt
+	}
+	return xobs, nil
+}
+
+// topoObserver 返回观测者在teval时刻相对地心的位置，与保存的位置时刻相同时直接使用
+func topoObserver(teval Float64, iflag Int32) ([6]Float64, error) {
+	if swed.Topd.Teval != teval || swed.Topd.Teval == 0 {
+		return getObserver(teval, iflag|SeflgNonut, true)
+	}
+	return swed.Topd.Xobs, nil
+}
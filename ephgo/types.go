@@ -209,6 +209,9 @@ type SweData struct {
 	SavedPlanetName       string    // 保存的行星名称
 	Dpsi                  []Float64 // 章动经度数组
 	Deps                  []Float64 // 章动倾角数组
+	Ut1Tai                []Float64 // UT1-TAI数组（秒）
+	PolarX                []Float64 // 极移x数组（角秒）
+	PolarY                []Float64 // 极移y数组（角秒）
 	Timeout               Int32     // 超时
 	AstroModels           [SeiNmodels]Int32 // 天体模型
 	DoInterpolateNut      Bool      // 是否插值章动
@@ -225,10 +228,12 @@ type SweData struct {
 	Nutv                  Nut       // 速度章动
 	Topd                  TopoData  // 地心数据
 	Sidd                  SidData   // 恒星时数据
-	NFixstarsReal         Bool      // 实际恒星数量
-	NFixstarsNamed        Bool      // 命名恒星数量
-	NFixstarsRecords      Bool      // 恒星记录数量
+	NFixstarsReal         int       // 实际恒星数量
+	NFixstarsNamed        int       // 命名恒星数量
+	NFixstarsRecords      int       // 恒星记录数量
 	FixedStars            []FixedStar // 恒星数组
+	FixstarIndex          map[string]int // 搜索关键字到FixedStars下标的索引
+	StarCatalogs          []StarCatalog  // 按优先顺序查找的恒星星表，为空时只使用恒星文件
 }
 
 // 全局数据实例（线程安全）